@@ -6,7 +6,12 @@ import (
 	"github.com/arfan21/project-sprint-social-media-api/config"
 	"github.com/arfan21/project-sprint-social-media-api/internal/server"
 	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/jwtkey"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/loginguard"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/notifier"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/pubsub"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/storage"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/telemetry"
 )
 
@@ -21,6 +26,11 @@ func Serve() error {
 		return err
 	}
 
+	err = config.Get().CheckSecrets()
+	if err != nil {
+		return err
+	}
+
 	if config.Get().Otel.EnableLogging {
 		logShutdown, err := telemetry.InitLogs()
 		if err != nil {
@@ -73,8 +83,39 @@ func Serve() error {
 		return err
 	}
 
+	fileStorage, err := storage.New()
+	if err != nil {
+		return err
+	}
+
+	ps, err := pubsub.New(db)
+	if err != nil {
+		return err
+	}
+	defer ps.Close()
+
+	otpNotifier, err := notifier.New()
+	if err != nil {
+		return err
+	}
+
+	loginGuard, err := loginguard.New(db, loginguard.LogAuditor{})
+	if err != nil {
+		return err
+	}
+
+	jwtKeys, err := jwtkey.Load()
+	if err != nil {
+		return err
+	}
+
 	server := server.New(
 		db,
+		fileStorage,
+		ps,
+		otpNotifier,
+		loginGuard,
+		jwtKeys,
 	)
 	return server.Run()
 }
@@ -12,14 +12,29 @@ import (
 type config struct {
 	HttpPort string `mapstructure:"HTTP_PORT"`
 	Env      string `mapstructure:"ENV"`
+	// ProxyHeader is the header holding the client ip when the api runs behind a proxy, e.g. X-Forwarded-For
+	ProxyHeader string `mapstructure:"PROXY_HEADER"`
 
-	Database   database   `mapstructure:",squash"`
-	Service    service    `mapstructure:",squash"`
-	JWT        jwt        `mapstructure:",squash"`
-	S3         s3         `mapstructure:",squash"`
-	Otel       otel       `mapstructure:",squash"`
-	Prometheus prometheus `mapstructure:",squash"`
-	Bcrypt     bcrypt     `mapstructure:",squash"`
+	Database        database        `mapstructure:",squash"`
+	Service         service         `mapstructure:",squash"`
+	JWT             jwt             `mapstructure:",squash"`
+	S3              s3              `mapstructure:",squash"`
+	Storage         storage         `mapstructure:",squash"`
+	Sanitizer       sanitizer       `mapstructure:",squash"`
+	Post            post            `mapstructure:",squash"`
+	PubSub          pubsub          `mapstructure:",squash"`
+	Stream          stream          `mapstructure:",squash"`
+	Cursor          cursor          `mapstructure:",squash"`
+	Tag             tag             `mapstructure:",squash"`
+	Notifier        notifier        `mapstructure:",squash"`
+	OTP             otp             `mapstructure:",squash"`
+	TOTP            totp            `mapstructure:",squash"`
+	LoginGuard      loginGuard      `mapstructure:",squash"`
+	Session         session         `mapstructure:",squash"`
+	AccountDeletion accountDeletion `mapstructure:",squash"`
+	Otel            otel            `mapstructure:",squash"`
+	Prometheus      prometheus      `mapstructure:",squash"`
+	Bcrypt          bcrypt          `mapstructure:",squash"`
 }
 
 type service struct {
@@ -45,8 +60,13 @@ func (d database) GetURL() string {
 }
 
 type jwt struct {
-	Secret   string `mapstructure:"JWT_SECRET"`
-	ExpireIn int    `mapstructure:"JWT_EXPIRE_IN"`
+	Secret          string `mapstructure:"JWT_SECRET"`
+	ExpireIn        int    `mapstructure:"JWT_EXPIRE_IN"`
+	RefreshExpireIn int    `mapstructure:"JWT_REFRESH_EXPIRE_IN"`
+	// PrivateKeyFile is a pem RSA or Ed25519 private key, tokens are signed with RS256 or EdDSA instead of the secret when it is set
+	PrivateKeyFile string `mapstructure:"JWT_PRIVATE_KEY_FILE"`
+	// VerificationKeyFiles are comma separated pem keys of the previous signing keys, they are still accepted during a rotation
+	VerificationKeyFiles string `mapstructure:"JWT_VERIFICATION_KEY_FILES"`
 }
 
 type otel struct {
@@ -77,6 +97,92 @@ type s3 struct {
 	Region    string `mapstructure:"S3_REGION"`
 }
 
+type storage struct {
+	Driver       string `mapstructure:"STORAGE_DRIVER"`
+	LocalDir     string `mapstructure:"STORAGE_LOCAL_DIR"`
+	LocalBaseURL string `mapstructure:"STORAGE_LOCAL_BASE_URL"`
+}
+
+type sanitizer struct {
+	Mode              string `mapstructure:"SANITIZER_MODE"`
+	AllowedTags       string `mapstructure:"SANITIZER_ALLOWED_TAGS"`
+	AllowedAttributes string `mapstructure:"SANITIZER_ALLOWED_ATTRIBUTES"`
+}
+
+type post struct {
+	FeedCommentLimit int `mapstructure:"POST_FEED_COMMENT_LIMIT"`
+}
+
+type pubsub struct {
+	Driver  string `mapstructure:"PUBSUB_DRIVER"`
+	Channel string `mapstructure:"PUBSUB_CHANNEL"`
+}
+
+type stream struct {
+	HeartbeatInterval int `mapstructure:"STREAM_HEARTBEAT_INTERVAL"`
+}
+
+type cursor struct {
+	Secret string `mapstructure:"CURSOR_SECRET"`
+}
+
+type tag struct {
+	TrendingWindow          int `mapstructure:"TAG_TRENDING_WINDOW"`
+	TrendingHalfLife        int `mapstructure:"TAG_TRENDING_HALF_LIFE"`
+	TrendingRefreshInterval int `mapstructure:"TAG_TRENDING_REFRESH_INTERVAL"`
+}
+
+type notifier struct {
+	Driver   string `mapstructure:"NOTIFIER_DRIVER"`
+	FilePath string `mapstructure:"NOTIFIER_FILE_PATH"`
+}
+
+type otp struct {
+	Secret         string `mapstructure:"OTP_SECRET"`
+	Length         int    `mapstructure:"OTP_LENGTH"`
+	ExpireIn       int    `mapstructure:"OTP_EXPIRE_IN"`
+	MaxAttempts    int    `mapstructure:"OTP_MAX_ATTEMPTS"`
+	ResendInterval int    `mapstructure:"OTP_RESEND_INTERVAL"`
+	// SendLimit is the most password reset codes sent to a user, and the most verification codes sent to an address, inside SendWindow
+	SendLimit  int `mapstructure:"OTP_SEND_LIMIT"`
+	SendWindow int `mapstructure:"OTP_SEND_WINDOW"`
+}
+
+type totp struct {
+	Issuer            string `mapstructure:"TOTP_ISSUER"`
+	ChallengeExpireIn int    `mapstructure:"TOTP_CHALLENGE_EXPIRE_IN"`
+	RecoveryCodeCount int    `mapstructure:"TOTP_RECOVERY_CODE_COUNT"`
+}
+
+type session struct {
+	// CacheTTL is how long JWTAuth trusts an active session before checking it again,
+	// it bounds both the delay before a revocation reaches other replicas and the precision of lastSeenAt
+	CacheTTL int `mapstructure:"SESSION_CACHE_TTL"`
+}
+
+type accountDeletion struct {
+	// GracePeriod is how long a deleted account can still be recovered by logging in
+	GracePeriod int `mapstructure:"ACCOUNT_DELETION_GRACE_PERIOD"`
+	// Interval is how often the worker looks for accounts past their grace period
+	Interval  int `mapstructure:"ACCOUNT_DELETION_INTERVAL"`
+	BatchSize int `mapstructure:"ACCOUNT_DELETION_BATCH_SIZE"`
+}
+
+type loginGuard struct {
+	Driver                     string `mapstructure:"LOGIN_GUARD_DRIVER"`
+	CredentialFreeAttempts     int    `mapstructure:"LOGIN_GUARD_CREDENTIAL_FREE_ATTEMPTS"`
+	CredentialLockoutThreshold int    `mapstructure:"LOGIN_GUARD_CREDENTIAL_LOCKOUT_THRESHOLD"`
+	IPFreeAttempts             int    `mapstructure:"LOGIN_GUARD_IP_FREE_ATTEMPTS"`
+	IPLockoutThreshold         int    `mapstructure:"LOGIN_GUARD_IP_LOCKOUT_THRESHOLD"`
+	BaseDelay                  int    `mapstructure:"LOGIN_GUARD_BASE_DELAY"`
+	MaxDelay                   int    `mapstructure:"LOGIN_GUARD_MAX_DELAY"`
+	LockoutDuration            int    `mapstructure:"LOGIN_GUARD_LOCKOUT_DURATION"`
+	Window                     int    `mapstructure:"LOGIN_GUARD_WINDOW"`
+}
+
+// DefaultJWTSecret is only meant for development, CheckSecrets refuses it outside ENV=dev
+const DefaultJWTSecret = "secret"
+
 var configInstance *config
 var viperInstance *viper.Viper
 
@@ -162,9 +268,56 @@ func initDefaultValue(v *viper.Viper) {
 	v.SetDefault("OTEL_INSECURE", true)
 	v.SetDefault("OTEL_EXPORTER_PROMETHEUS_PATH", "/metrics")
 	v.SetDefault("OTEL_EXPORTER_PROMETHEUS_PORT", "2223")
-	v.SetDefault("JWT_SECRET", "secret")
+	v.SetDefault("JWT_SECRET", DefaultJWTSecret)
 	v.SetDefault("JWT_EXPIRE_IN", 120)
+	v.SetDefault("JWT_REFRESH_EXPIRE_IN", 2592000)
 	v.SetDefault("S3_REGION", "ap-southeast-1")
+	v.SetDefault("STORAGE_DRIVER", "s3")
+	v.SetDefault("STORAGE_LOCAL_DIR", "storage")
+	v.SetDefault("STORAGE_LOCAL_BASE_URL", "http://127.0.0.1:8080")
+	v.SetDefault("SANITIZER_MODE", "strip")
+	v.SetDefault("SANITIZER_ALLOWED_TAGS", "p,br,b,strong,i,em,u,s,a,ul,ol,li,blockquote,code,pre,span")
+	v.SetDefault("SANITIZER_ALLOWED_ATTRIBUTES", "a:href,a:title")
+	v.SetDefault("POST_FEED_COMMENT_LIMIT", 3)
+	v.SetDefault("PUBSUB_DRIVER", "memory")
+	v.SetDefault("PUBSUB_CHANNEL", "social_media_events")
+	v.SetDefault("STREAM_HEARTBEAT_INTERVAL", 15)
+	v.SetDefault("TAG_TRENDING_WINDOW", 72)
+	v.SetDefault("TAG_TRENDING_HALF_LIFE", 12)
+	v.SetDefault("TAG_TRENDING_REFRESH_INTERVAL", 300)
+	v.SetDefault("NOTIFIER_DRIVER", "log")
+	v.SetDefault("NOTIFIER_FILE_PATH", "storage/notifier.log")
+	v.SetDefault("OTP_LENGTH", 6)
+	v.SetDefault("OTP_EXPIRE_IN", 600)
+	v.SetDefault("OTP_MAX_ATTEMPTS", 5)
+	v.SetDefault("OTP_RESEND_INTERVAL", 60)
+	v.SetDefault("OTP_SEND_LIMIT", 5)
+	v.SetDefault("OTP_SEND_WINDOW", 86400)
+	v.SetDefault("TOTP_CHALLENGE_EXPIRE_IN", 300)
+	v.SetDefault("TOTP_RECOVERY_CODE_COUNT", 10)
+	v.SetDefault("LOGIN_GUARD_DRIVER", "memory")
+	v.SetDefault("LOGIN_GUARD_CREDENTIAL_FREE_ATTEMPTS", 3)
+	v.SetDefault("LOGIN_GUARD_CREDENTIAL_LOCKOUT_THRESHOLD", 10)
+	v.SetDefault("LOGIN_GUARD_IP_FREE_ATTEMPTS", 20)
+	v.SetDefault("LOGIN_GUARD_IP_LOCKOUT_THRESHOLD", 100)
+	v.SetDefault("LOGIN_GUARD_BASE_DELAY", 1)
+	v.SetDefault("LOGIN_GUARD_MAX_DELAY", 300)
+	v.SetDefault("LOGIN_GUARD_LOCKOUT_DURATION", 900)
+	v.SetDefault("LOGIN_GUARD_WINDOW", 3600)
+	v.SetDefault("SESSION_CACHE_TTL", 30)
+	v.SetDefault("ACCOUNT_DELETION_GRACE_PERIOD", 2592000)
+	v.SetDefault("ACCOUNT_DELETION_INTERVAL", 3600)
+	v.SetDefault("ACCOUNT_DELETION_BATCH_SIZE", 100)
 	v.SetDefault("OTEL_ENABLE_METRICS", true)
 	v.SetDefault("OTEL_ONLY_PROMETHEUS_EXPORTER", true)
 }
+
+// CheckSecrets refuses to start outside ENV=dev with the default jwt secret,
+// whether or not JWT_PRIVATE_KEY_FILE, OTP_SECRET and CURSOR_SECRET are set
+func (c *config) CheckSecrets() error {
+	if c.Env != "dev" && c.JWT.Secret == DefaultJWTSecret {
+		return fmt.Errorf("config: JWT_SECRET is the default secret, set JWT_SECRET or run with ENV=dev")
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestCheckSecrets(t *testing.T) {
+	tests := []struct {
+		name           string
+		env            string
+		jwtSecret      string
+		privateKeyFile string
+		otpSecret      string
+		cursorSecret   string
+		wantErr        bool
+	}{
+		{name: "dev accepts the default secret", env: "dev", jwtSecret: DefaultJWTSecret},
+		{name: "custom secret", env: "production", jwtSecret: "a-long-random-secret"},
+		{name: "default secret signs the tokens", env: "production", jwtSecret: DefaultJWTSecret, otpSecret: "o", cursorSecret: "c", wantErr: true},
+		{name: "default secret hashes the otps", env: "production", jwtSecret: DefaultJWTSecret, privateKeyFile: "key.pem", cursorSecret: "c", wantErr: true},
+		{name: "default secret signs the cursors", env: "production", jwtSecret: DefaultJWTSecret, privateKeyFile: "key.pem", otpSecret: "o", wantErr: true},
+		{name: "default secret with every other secret set", env: "production", jwtSecret: DefaultJWTSecret, privateKeyFile: "key.pem", otpSecret: "o", cursorSecret: "c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &config{Env: tt.env}
+			c.JWT.Secret = tt.jwtSecret
+			c.JWT.PrivateKeyFile = tt.privateKeyFile
+			c.OTP.Secret = tt.otpSecret
+			c.Cursor.Secret = tt.cursorSecret
+
+			err := c.CheckSecrets()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckSecrets() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	NotificationTypeFriendRequest  = "friend_request"
+	NotificationTypeFriendAccepted = "friend_accepted"
+	NotificationTypeComment        = "comment"
+	NotificationTypeReply          = "reply"
+	NotificationTypeMention        = "mention"
+)
+
+type Notification struct {
+	ID        uuid.UUID     `json:"id"`
+	UserID    uuid.UUID     `json:"userId"`
+	ActorID   uuid.UUID     `json:"actorId"`
+	Type      string        `json:"type"`
+	PostID    uuid.NullUUID `json:"postId"`
+	CommentID uuid.NullUUID `json:"commentId"`
+	ReadAt    null.Time     `json:"readAt"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	Actor     User          `json:"actor"`
+	Total     int           `json:"total"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -7,15 +7,28 @@ import (
 	"gopkg.in/guregu/null.v4"
 )
 
+// audience of a post, the creator can always see their own post
+const (
+	PostVisibilityPublic  = "public"
+	PostVisibilityFriends = "friends"
+	PostVisibilityOnlyMe  = "only_me"
+)
+
 type Post struct {
-	ID        uuid.UUID             `json:"id"`
-	UserID    uuid.UUID             `json:"userId"`
-	Body      string                `json:"body"`
-	Tags      []string              `json:"tags"`
-	CreatedAt time.Time             `json:"created_at"`
-	UpdatedAt time.Time             `json:"updated_at"`
-	Comments  []PostCommentNullable `json:"comments"`
-	Total     int                   `json:"total"`
+	ID           uuid.UUID             `json:"id"`
+	UserID       uuid.UUID             `json:"userId"`
+	Body         string                `json:"body"`
+	BodyOriginal null.String           `json:"bodyOriginal"`
+	Tags         []string              `json:"tags"`
+	Visibility   string                `json:"visibility"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+	DeletedAt    null.Time             `json:"deleted_at"`
+	Reactions    ReactionCount         `json:"reactions"`
+	CommentCount int                   `json:"commentCount"`
+	Highlight    null.String           `json:"highlight"`
+	Comments     []PostCommentNullable `json:"comments"`
+	Total        int                   `json:"total"`
 }
 
 func (Post) TableName() string {
@@ -23,18 +36,49 @@ func (Post) TableName() string {
 }
 
 type PostComment struct {
-	ID        uuid.UUID `json:"id"`
-	PostID    uuid.UUID `json:"postId"`
-	UserID    uuid.UUID `json:"userId"`
-	Comment   string    `json:"comment"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uuid.UUID     `json:"id"`
+	PostID          uuid.UUID     `json:"postId"`
+	UserID          uuid.UUID     `json:"userId"`
+	Comment         string        `json:"comment"`
+	CommentOriginal null.String   `json:"commentOriginal"`
+	ParentCommentID uuid.NullUUID `json:"parentCommentId"`
+	ReplyCount      int           `json:"replyCount"`
+	EditedAt        null.Time     `json:"edited_at"`
+	DeletedAt       null.Time     `json:"deleted_at"`
+	Reactions       ReactionCount `json:"reactions"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
 }
 
 func (PostComment) TableName() string {
 	return "post_comments"
 }
 
+type PostCommentRevision struct {
+	ID              uuid.UUID   `json:"id"`
+	CommentID       uuid.UUID   `json:"commentId"`
+	Comment         string      `json:"comment"`
+	CommentOriginal null.String `json:"commentOriginal"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+func (PostCommentRevision) TableName() string {
+	return "post_comment_revisions"
+}
+
+// PostMention is a user mentioned in a post, or in one of its comments when the comment id is set
+type PostMention struct {
+	ID        uuid.UUID     `json:"id"`
+	PostID    uuid.UUID     `json:"postId"`
+	CommentID uuid.NullUUID `json:"commentId"`
+	UserID    uuid.UUID     `json:"userId"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+func (PostMention) TableName() string {
+	return "post_mentions"
+}
+
 type PostCommentNullable struct {
 	ID        uuid.NullUUID `json:"id"`
 	PostID    uuid.NullUUID `json:"postId"`
@@ -44,6 +88,40 @@ type PostCommentNullable struct {
 	UpdatedAt null.Time     `json:"updated_at"`
 }
 
+const (
+	ReactionTypeLike  = "like"
+	ReactionTypeLove  = "love"
+	ReactionTypeHaha  = "haha"
+	ReactionTypeWow   = "wow"
+	ReactionTypeSad   = "sad"
+	ReactionTypeAngry = "angry"
+)
+
+// ReactionCount is the denormalized count of each reaction type on a post or comment
+type ReactionCount struct {
+	Like  int `json:"like"`
+	Love  int `json:"love"`
+	Haha  int `json:"haha"`
+	Wow   int `json:"wow"`
+	Sad   int `json:"sad"`
+	Angry int `json:"angry"`
+}
+
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetComment = "comment"
+)
+
+// Reaction is a reaction of a user on a post or a comment, TargetID refers to
+// the post id or the comment id depending on the reaction target
+type Reaction struct {
+	TargetID  uuid.UUID `json:"targetId"`
+	UserID    uuid.UUID `json:"userId"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type PostCounter struct {
 	Count int `json:"count"`
 }
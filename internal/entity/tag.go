@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type Tag struct {
+	Name       string    `json:"name"`
+	UseCount   int       `json:"useCount"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+type TagTrend struct {
+	Name        string    `json:"name"`
+	Score       float64   `json:"score"`
+	PostCount   int       `json:"postCount"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+}
+
+func (TagTrend) TableName() string {
+	return "tag_trends"
+}
@@ -8,16 +8,22 @@ import (
 )
 
 type User struct {
-	ID          uuid.UUID   `json:"id"`
-	Email       null.String `json:"email"`
-	Phone       null.String `json:"phone"`
-	Name        string      `json:"name"`
-	Password    string      `json:"password"`
-	ImageUrl    null.String `json:"imageUrl"`
-	CreatedAt   time.Time   `json:"createdAt"`
-	UpdatedAt   time.Time   `json:"updatedAt"`
-	FriendCount int         `json:"friendCount"`
-	Total       int         `json:"total"`
+	ID              uuid.UUID   `json:"id"`
+	Email           null.String `json:"email"`
+	Phone           null.String `json:"phone"`
+	Name            string      `json:"name"`
+	Password        string      `json:"password"`
+	ImageUrl        null.String `json:"imageUrl"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	UpdatedAt       time.Time   `json:"updatedAt"`
+	FriendCount     int         `json:"friendCount"`
+	EmailVerifiedAt null.Time   `json:"emailVerifiedAt"`
+	PhoneVerifiedAt null.Time   `json:"phoneVerifiedAt"`
+	TOTPEnabledAt   null.Time   `json:"totpEnabledAt"`
+	// DeletionScheduledAt is when the deletion worker anonymizes the account, logging in before it cancels the deletion
+	DeletionScheduledAt null.Time `json:"deletionScheduledAt"`
+	DeletedAt           null.Time `json:"deletedAt"`
+	Total               int       `json:"total"`
 }
 
 func (User) TableName() string {
@@ -34,3 +40,134 @@ type Friend struct {
 func (Friend) TableName() string {
 	return "friends"
 }
+
+type UserBlock struct {
+	UserIDBlocker uuid.UUID `json:"userIdBlocker"`
+	UserIDBlocked uuid.UUID `json:"userIdBlocked"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func (UserBlock) TableName() string {
+	return "user_blocks"
+}
+
+type UserMute struct {
+	UserIDMuter uuid.UUID `json:"userIdMuter"`
+	UserIDMuted uuid.UUID `json:"userIdMuted"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (UserMute) TableName() string {
+	return "user_mutes"
+}
+
+type Session struct {
+	ID               uuid.UUID   `json:"id"`
+	UserID           uuid.UUID   `json:"userId"`
+	RefreshTokenHash string      `json:"-"`
+	ExpiresAt        time.Time   `json:"expiresAt"`
+	RevokedAt        null.Time   `json:"revokedAt"`
+	Device           null.String `json:"device"`
+	UserAgent        null.String `json:"userAgent"`
+	IP               null.String `json:"ip"`
+	LastSeenAt       time.Time   `json:"lastSeenAt"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	UpdatedAt        time.Time   `json:"updatedAt"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// PasswordReset holds the hash of the otp sent to reset the password of the user
+type PasswordReset struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	Channel   string    `json:"channel"`
+	OTPHash   string    `json:"-"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	UsedAt    null.Time `json:"usedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (PasswordReset) TableName() string {
+	return "password_resets"
+}
+
+// CredentialVerification holds the hash of the otp sent to prove the ownership of an email or phone,
+// the value is only linked to the user once it is verified
+type CredentialVerification struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	Channel   string    `json:"channel"`
+	Value     string    `json:"value"`
+	OTPHash   string    `json:"-"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	UsedAt    null.Time `json:"usedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (CredentialVerification) TableName() string {
+	return "credential_verifications"
+}
+
+// UserTOTP holds the secret shared with the authenticator app of the user,
+// login asks for a code only once it is enabled
+type UserTOTP struct {
+	UserID       uuid.UUID `json:"userId"`
+	Secret       string    `json:"-"`
+	EnabledAt    null.Time `json:"enabledAt"`
+	LastUsedStep int64     `json:"lastUsedStep"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (UserTOTP) TableName() string {
+	return "user_totps"
+}
+
+// LoginChallenge is the pending second step of a login with two factor authentication
+type LoginChallenge struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+	// CredentialKey is the login guard key of the credential the password was checked for
+	CredentialKey string    `json:"credentialKey"`
+	Attempts      int       `json:"attempts"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+	UsedAt        null.Time `json:"usedAt"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
+
+const (
+	FriendRequestStatusPending   = "pending"
+	FriendRequestStatusAccepted  = "accepted"
+	FriendRequestStatusRejected  = "rejected"
+	FriendRequestStatusCancelled = "cancelled"
+)
+
+// relationship of a user with the viewer
+const (
+	RelationshipSelf    = "self"
+	RelationshipFriend  = "friend"
+	RelationshipPending = "pending"
+	RelationshipNone    = "none"
+)
+
+type FriendRequest struct {
+	ID              uuid.UUID `json:"id"`
+	UserIDRequester uuid.UUID `json:"userIdRequester"`
+	UserIDTarget    uuid.UUID `json:"userIdTarget"`
+	Status          string    `json:"status"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	Total           int       `json:"total"`
+}
+
+func (FriendRequest) TableName() string {
+	return "friend_requests"
+}
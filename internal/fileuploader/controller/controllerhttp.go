@@ -20,7 +20,7 @@ func New(service fileuploader.Service) *ControllerHTTP {
 }
 
 // @Summary Upload Image
-// @Description Upload image to s3
+// @Description Upload image to the configured storage
 // @Tags Image Uploader
 // @Accept multipart/form-data
 // @Produce json
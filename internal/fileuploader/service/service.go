@@ -4,17 +4,17 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/arfan21/project-sprint-social-media-api/config"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
-	"github.com/arfan21/project-sprint-social-media-api/pkg/s3"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/storage"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
 )
 
 type Service struct {
+	storage storage.Storage
 }
 
-func New() *Service {
-	return &Service{}
+func New(storage storage.Storage) *Service {
+	return &Service{storage: storage}
 }
 
 func (s *Service) UploadImage(ctx context.Context, req model.FileUploaderImageRequest) (res model.FileUploaderImageResponse, err error) {
@@ -33,22 +33,14 @@ func (s *Service) UploadImage(ctx context.Context, req model.FileUploaderImageRe
 		return
 	}
 
-	client, err := s3.New()
-	if err != nil {
-		err = fmt.Errorf("imageuploader.service.Upload: failed to create s3 client: %w", err)
-		return
-	}
-
 	folder := "images"
-	bucket := config.Get().S3.Bucket
-	resStr, err := client.Upload(ctx, bucket, folder, req.File)
+	key, err := s.storage.Upload(ctx, folder, req.File)
 	if err != nil {
 		err = fmt.Errorf("imageuploader.service.Upload: failed to upload file: %w", err)
 		return
 	}
-	url := client.GetURL(bucket, resStr)
 
-	res.ImageURL = url
+	res.ImageURL = s.storage.GetURL(key)
 
 	return res, nil
 }
@@ -9,5 +9,7 @@ type JWTClaims struct {
 	UserID string `json:"-"`
 
 	Name string `json:"name"`
+	// Purpose is empty for access tokens
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
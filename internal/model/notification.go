@@ -0,0 +1,45 @@
+package model
+
+type NotificationCreateRequest struct {
+	UserID    string `validate:"required"`
+	ActorID   string `validate:"required"`
+	Type      string `validate:"required,oneof=friend_request friend_accepted comment reply mention"`
+	PostID    string
+	CommentID string
+}
+
+type NotificationGetListRequest struct {
+	Limit      int    `query:"limit" validate:"omitempty,gte=0"`
+	Offset     int    `query:"offset" validate:"omitempty,gte=0"`
+	UnreadOnly bool   `query:"unreadOnly"`
+	UserID     string `query:"-" validate:"required"`
+}
+
+type NotificationMarkReadRequest struct {
+	NotificationID string `json:"-" validate:"required"`
+	UserID         string `json:"-" validate:"required"`
+}
+
+type NotificationPreferenceRequest struct {
+	OptOut []string `json:"optOut" validate:"dive,oneof=friend_request friend_accepted comment reply mention"`
+	UserID string   `json:"-" validate:"required"`
+}
+
+type NotificationListResponse struct {
+	UnreadCount   int                    `json:"unreadCount"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+type NotificationResponse struct {
+	NotificationID string       `json:"notificationId"`
+	Type           string       `json:"type"`
+	Actor          UserResponse `json:"actor"`
+	PostID         *string      `json:"postId"`
+	CommentID      *string      `json:"commentId"`
+	Read           bool         `json:"read"`
+	CreatedAt      string       `json:"createdAt"`
+}
+
+type NotificationPreferenceResponse struct {
+	OptOut []string `json:"optOut"`
+}
@@ -3,40 +3,131 @@ package model
 type PostRequest struct {
 	PostInHtml string   `json:"postInHtml" validate:"required,min=3,max=500"`
 	Tags       []string `json:"tags" validate:"required,dive,required"`
+	Visibility string   `json:"visibility" validate:"omitempty,oneof=public friends only_me" example:"friends"`
 	UserID     string   `json:"-" validate:"required"`
 }
 
+type PostGetByIDRequest struct {
+	PostID string `json:"-" validate:"required"`
+	UserID string `json:"-" validate:"required"`
+}
+
+type PostUpdateRequest struct {
+	PostID     string   `json:"-" validate:"required"`
+	UserID     string   `json:"-" validate:"required"`
+	PostInHtml string   `json:"postInHtml" validate:"omitempty,min=3,max=500"`
+	Tags       []string `json:"tags" validate:"omitempty,dive,required"`
+	Visibility string   `json:"visibility" validate:"omitempty,oneof=public friends only_me"`
+}
+
+type PostDeleteRequest struct {
+	PostID string `json:"-" validate:"required"`
+	UserID string `json:"-" validate:"required"`
+}
+
 type PostCommentRequest struct {
-	PostID  string `json:"postId" validate:"required"`
-	Comment string `json:"comment" validate:"required,min=2,max=500"`
-	UserID  string `json:"-" validate:"required"`
+	PostID          string `json:"postId" validate:"required"`
+	ParentCommentID string `json:"parentCommentId"`
+	Comment         string `json:"comment" validate:"required,min=2,max=500"`
+	UserID          string `json:"-" validate:"required"`
+}
+
+type PostCommentUpdateRequest struct {
+	CommentID string `json:"-" validate:"required"`
+	UserID    string `json:"-" validate:"required"`
+	Comment   string `json:"comment" validate:"required,min=2,max=500"`
+}
+
+type PostCommentDeleteRequest struct {
+	CommentID string `json:"-" validate:"required"`
+	UserID    string `json:"-" validate:"required"`
+}
+
+type PostCommentGetListRequest struct {
+	PostID          string `query:"-" validate:"required"`
+	UserID          string `query:"-" validate:"required"`
+	ParentCommentID string `query:"parentCommentId"`
+	Limit           int    `query:"limit" validate:"omitempty,gte=0"`
+	Cursor          string `query:"cursor"`
+	CursorID        string `query:"-"`
+	CursorPrev      bool   `query:"-"`
+}
+
+type PostReactionRequest struct {
+	PostID string `json:"-" validate:"required"`
+	UserID string `json:"-" validate:"required"`
+	Type   string `json:"type" validate:"required,oneof=like love haha wow sad angry"`
+}
+
+type PostReactionDeleteRequest struct {
+	PostID string `json:"-" validate:"required"`
+	UserID string `json:"-" validate:"required"`
+}
+
+type PostCommentReactionRequest struct {
+	CommentID string `json:"-" validate:"required"`
+	UserID    string `json:"-" validate:"required"`
+	Type      string `json:"type" validate:"required,oneof=like love haha wow sad angry"`
+}
+
+type PostCommentReactionDeleteRequest struct {
+	CommentID string `json:"-" validate:"required"`
+	UserID    string `json:"-" validate:"required"`
 }
 
 type PostGetListRequest struct {
 	UserID        string   `query:"-" validate:"required"`
+	AuthorID      string   `query:"-"`
+	Discover      bool     `query:"-"`
+	Visibilities  []string `query:"-"`
 	Limit         int      `query:"limit" validate:"omitempty,gte=0"`
 	Offset        int      `query:"offset" validate:"omitempty,gte=0"`
 	Search        string   `query:"search"`
 	SearchTags    []string `query:"searchTag"`
+	Mentioned     bool     `query:"mentioned"`
+	Cursor        string   `query:"cursor"`
+	CursorID      string   `query:"-"`
+	CursorPrev    bool     `query:"-"`
 	DisableOffset bool     `query:"-"`
 	DisableOrder  bool     `query:"-"`
 }
 
 type PostListResponse struct {
-	PostID   string                `json:"postId"`
-	Post     PostResponse          `json:"post"`
-	Creator  UserResponse          `json:"creator"`
-	Comments []PostCommentResponse `json:"comments"`
+	PostID         string                `json:"postId"`
+	Post           PostResponse          `json:"post"`
+	Creator        UserResponse          `json:"creator"`
+	Comments       []PostCommentResponse `json:"comments"`
+	CommentCount   int                   `json:"commentCount"`
+	Highlight      string                `json:"highlight,omitempty"`
+	Reactions      ReactionCountResponse `json:"reactions"`
+	ViewerReaction *string               `json:"viewerReaction"`
 }
 
 type PostResponse struct {
 	PostInHtml string   `json:"postInHtml"`
 	Tags       []string `json:"tags"`
+	Visibility string   `json:"visibility"`
 	CreatedAt  string   `json:"createdAt"`
 }
 
 type PostCommentResponse struct {
-	Comment   string       `json:"comment"`
-	CreatedAt string       `json:"createdAt"`
-	Creator   UserResponse `json:"creator"`
+	CommentID       string                `json:"commentId"`
+	ParentCommentID *string               `json:"parentCommentId"`
+	Comment         string                `json:"comment"`
+	ReplyCount      int                   `json:"replyCount"`
+	Edited          bool                  `json:"edited"`
+	EditedAt        *string               `json:"editedAt"`
+	CreatedAt       string                `json:"createdAt"`
+	Creator         UserResponse          `json:"creator"`
+	Reactions       ReactionCountResponse `json:"reactions"`
+	ViewerReaction  *string               `json:"viewerReaction"`
+}
+
+type ReactionCountResponse struct {
+	Like  int `json:"like"`
+	Love  int `json:"love"`
+	Haha  int `json:"haha"`
+	Wow   int `json:"wow"`
+	Sad   int `json:"sad"`
+	Angry int `json:"angry"`
 }
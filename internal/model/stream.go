@@ -0,0 +1,24 @@
+package model
+
+const (
+	StreamEventPostCreated     = "post.created"
+	StreamEventCommentCreated  = "comment.created"
+	StreamEventFriendRequested = "friend.requested"
+	StreamEventFriendAccepted  = "friend.accepted"
+	StreamEventFriendRemoved   = "friend.removed"
+)
+
+type StreamPostEvent struct {
+	PostID string `json:"postId"`
+	UserID string `json:"userId"`
+}
+
+type StreamCommentEvent struct {
+	PostID    string `json:"postId"`
+	CommentID string `json:"commentId"`
+	UserID    string `json:"userId"`
+}
+
+type StreamFriendEvent struct {
+	UserID string `json:"userId"`
+}
@@ -0,0 +1,22 @@
+package model
+
+type TagGetListRequest struct {
+	Prefix string `query:"prefix" validate:"required,max=255"`
+	Limit  int    `query:"limit" validate:"omitempty,gte=0,lte=50"`
+}
+
+type TagTrendingRequest struct {
+	Limit int `query:"limit" validate:"omitempty,gte=0,lte=50"`
+}
+
+type TagResponse struct {
+	Tag      string `json:"tag" example:"golang"`
+	UseCount int    `json:"useCount" example:"12"`
+}
+
+type TagTrendingResponse struct {
+	Tag         string  `json:"tag" example:"golang"`
+	Score       float64 `json:"score" example:"3.42"`
+	PostCount   int     `json:"postCount" example:"5"`
+	RefreshedAt string  `json:"refreshedAt"`
+}
@@ -12,23 +12,141 @@ package model
 // }
 
 type UserRegisterRequest struct {
+	Client          SessionClient `json:"-"`
+	CredentialType  string        `json:"credentialType" validate:"required,oneof=phone email"`
+	CredentialValue string        `json:"credentialValue" validate:"required,emailorphone=CredentialType"`
+	Name            string        `json:"name" validate:"required,min=5,max=50"`
+	Password        string        `json:"password" validate:"required,min=5,max=15"`
+}
+
+// SessionClient describes the client a session is created for, it is filled from the request headers
+type SessionClient struct {
+	Device    string
+	UserAgent string
+	IP        string
+}
+
+type UserLoginRequest struct {
+	// Client of the request, failed logins are also counted per ip
+	Client          SessionClient `json:"-"`
+	CredentialType  string        `json:"credentialType" validate:"required,oneof=phone email"`
+	CredentialValue string        `json:"credentialValue" validate:"required,emailorphone=CredentialType"`
+	Password        string        `json:"password" validate:"required,min=5,max=15"`
+}
+
+type UserLoginResponse struct {
+	Phone        *string `json:"phone,omitempty"`
+	Email        *string `json:"email,omitempty"`
+	Name         string  `json:"name"`
+	AccessToken  string  `json:"accessToken"`
+	RefreshToken string  `json:"refreshToken"`
+	// TwoFactorRequired is set instead of the tokens when the user enabled two factor authentication,
+	// the challenge token is exchanged for the tokens at /v1/user/login/2fa
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	ChallengeToken    string `json:"challengeToken,omitempty"`
+	// DeletionCancelled is set when the login cancelled a scheduled deletion of the account
+	DeletionCancelled bool `json:"deletionCancelled,omitempty"`
+}
+
+type UserLoginTwoFactorRequest struct {
+	Client         SessionClient `json:"-"`
+	ChallengeToken string        `json:"challengeToken" validate:"required"`
+	// Code is the code of the authenticator app or one of the recovery codes
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+type UserTwoFactorEnrollRequest struct {
+	UserID string `json:"-" validate:"required"`
+}
+
+type UserTwoFactorEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type UserTwoFactorEnableRequest struct {
+	UserID string `json:"-" validate:"required"`
+	Code   string `json:"code" validate:"required,numeric,len=6"`
+}
+
+type UserTwoFactorEnableResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type UserTwoFactorDisableRequest struct {
+	UserID   string `json:"-" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+type UserPasswordUpdateRequest struct {
+	UserID      string `json:"-" validate:"required"`
+	SessionID   string `json:"-" validate:"required"`
+	OldPassword string `json:"oldPassword" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=5,max=15,nefield=OldPassword"`
+}
+
+type UserPasswordForgotRequest struct {
 	CredentialType  string `json:"credentialType" validate:"required,oneof=phone email"`
 	CredentialValue string `json:"credentialValue" validate:"required,emailorphone=CredentialType"`
-	Name            string `json:"name" validate:"required,min=5,max=50"`
-	Password        string `json:"password" validate:"required,min=5,max=15"`
 }
 
-type UserLoginRequest struct {
+type UserPasswordResetRequest struct {
 	CredentialType  string `json:"credentialType" validate:"required,oneof=phone email"`
 	CredentialValue string `json:"credentialValue" validate:"required,emailorphone=CredentialType"`
-	Password        string `json:"password" validate:"required,min=5,max=15"`
+	OTP             string `json:"otp" validate:"required,numeric"`
+	NewPassword     string `json:"newPassword" validate:"required,min=5,max=15"`
 }
 
-type UserLoginResponse struct {
-	Phone       *string `json:"phone,omitempty"`
-	Email       *string `json:"email,omitempty"`
-	Name        string  `json:"name"`
-	AccessToken string  `json:"accessToken"`
+type UserVerifyRequest struct {
+	UserID         string `json:"-" validate:"required"`
+	CredentialType string `json:"credentialType" validate:"required,oneof=phone email"`
+	OTP            string `json:"otp" validate:"required,numeric"`
+}
+
+type UserVerificationResendRequest struct {
+	UserID         string `json:"-" validate:"required"`
+	CredentialType string `json:"credentialType" validate:"required,oneof=phone email"`
+}
+
+type UserRefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type UserLogoutRequest struct {
+	UserID    string `json:"-" validate:"required"`
+	SessionID string `json:"-" validate:"required"`
+}
+
+type UserSessionGetListRequest struct {
+	UserID string `json:"-" validate:"required"`
+	// SessionID is the session of the request, it is flagged as current in the list
+	SessionID string `json:"-"`
+}
+
+type UserSessionRevokeRequest struct {
+	UserID string `json:"-" validate:"required"`
+	ID     string `json:"-" validate:"required"`
+}
+
+type UserSessionResponse struct {
+	ID         string `json:"id"`
+	Device     string `json:"device"`
+	UserAgent  string `json:"userAgent"`
+	IP         string `json:"ip"`
+	Current    bool   `json:"current"`
+	LastSeenAt string `json:"lastSeenAt"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiresAt  string `json:"expiresAt"`
+}
+
+type UserDeleteRequest struct {
+	UserID   string `json:"-" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type UserDeleteResponse struct {
+	DeletionScheduledAt string `json:"deletionScheduledAt"`
 }
 
 type FriendRequest struct {
@@ -36,6 +154,42 @@ type FriendRequest struct {
 	UserID      string `json:"userId" validate:"required"`
 }
 
+type FriendRequestActionRequest struct {
+	FriendRequestID string `json:"-" validate:"required"`
+	UserID          string `json:"-" validate:"required"`
+}
+
+type FriendRequestGetListRequest struct {
+	Limit    int    `query:"limit" validate:"omitempty,gte=0"`
+	Offset   int    `query:"offset" validate:"omitempty,gte=0"`
+	UserID   string `query:"-" validate:"required"`
+	Incoming bool   `query:"-"`
+}
+
+type FriendRequestResponse struct {
+	FriendRequestID string       `json:"friendRequestId"`
+	Status          string       `json:"status"`
+	User            UserResponse `json:"user"`
+	CreatedAt       string       `json:"createdAt"`
+}
+
+type UserBlockRequest struct {
+	UserIDBlocker string `json:"-" validate:"required"`
+	UserID        string `json:"userId" validate:"required"`
+}
+
+type UserMuteRequest struct {
+	UserIDMuter string `json:"-" validate:"required"`
+	UserID      string `json:"userId" validate:"required"`
+}
+
+// UserRestrictionGetListRequest lists the users blocked or muted by the user
+type UserRestrictionGetListRequest struct {
+	Limit  int    `query:"limit" validate:"omitempty,gte=0"`
+	Offset int    `query:"offset" validate:"omitempty,gte=0"`
+	UserID string `query:"-" validate:"required"`
+}
+
 type UserGetListRequest struct {
 	Limit         int      `query:"limit" validate:"omitempty,gte=0"`
 	Offset        int      `query:"offset" validate:"omitempty,gte=0"`
@@ -45,6 +199,9 @@ type UserGetListRequest struct {
 	Search        string   `query:"search"`
 	UserID        string   `query:"-"`
 	UserIDs       []string `query:"-"`
+	Cursor        string   `query:"cursor"`
+	CursorID      string   `query:"-"`
+	CursorPrev    bool     `query:"-"`
 	DisableOffset bool     `query:"-"`
 	DisableOrder  bool     `query:"-"`
 }
@@ -57,6 +214,26 @@ type UserResponse struct {
 	CreatedAt   string `json:"createdAt,omitempty"`
 }
 
+type UserProfileRequest struct {
+	UserID   string `json:"-" validate:"required"`
+	ViewerID string `json:"-" validate:"required"`
+}
+
+type UserProfileResponse struct {
+	UserResponse
+	Relationship          string `json:"relationship" example:"friend"`
+	FriendRequestID       string `json:"friendRequestId,omitempty"`
+	FriendRequestIncoming bool   `json:"friendRequestIncoming,omitempty"`
+}
+
+type MentionResolveRequest struct {
+	OwnerID string `validate:"required"`
+	// AuthorID wrote the mentions, users blocked by the author or who blocked the author are left out
+	AuthorID       string   `validate:"required"`
+	Handles        []string `validate:"required"`
+	IncludeFriends bool
+}
+
 type UserPhoneUpdateRequest struct {
 	Phone  string `json:"phone" validate:"required,phone"`
 	UserID string `json:"-" validate:"required"`
@@ -0,0 +1,185 @@
+package notificationctrl
+
+import (
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/notification"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/exception"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/pkgutil"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ControllerHTTP struct {
+	svc notification.Service
+}
+
+func New(svc notification.Service) *ControllerHTTP {
+	return &ControllerHTTP{svc: svc}
+}
+
+// @Summary Get list notification
+// @Description Get list notification of the user with the unread count
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param limit query int false "Limit data"
+// @Param offset query int false "Offset data"
+// @Param unreadOnly query bool false "Only unread notification"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.NotificationListResponse,meta=pkgutil.MetaResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/notification [get]
+func (ctrl ControllerHTTP) GetList(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.NotificationGetListRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	res, count, err := ctrl.svc.GetList(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: res,
+		Meta: pkgutil.MetaResponse{
+			Offset: req.Offset,
+			Limit:  req.Limit,
+			Total:  count,
+		},
+	})
+}
+
+// @Summary Mark notification as read
+// @Description Mark notification as read
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Notification id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/notification/{id}/read [post]
+func (ctrl ControllerHTTP) MarkRead(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.MarkRead(c.UserContext(), model.NotificationMarkReadRequest{
+		NotificationID: c.Params("id"),
+		UserID:         claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Notification marked as read",
+	})
+}
+
+// @Summary Mark all notification as read
+// @Description Mark all notification of the user as read
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/notification/read-all [post]
+func (ctrl ControllerHTTP) MarkAllRead(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.MarkAllRead(c.UserContext(), claims.UserID)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "All notification marked as read",
+	})
+}
+
+// @Summary Get notification preference
+// @Description Get the notification types the user opted out of
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.NotificationPreferenceResponse}
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/notification/preference [get]
+func (ctrl ControllerHTTP) GetPreference(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	res, err := ctrl.svc.GetPreference(c.UserContext(), claims.UserID)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: res,
+	})
+}
+
+// @Summary Update notification preference
+// @Description Replace the notification types the user opted out of
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.NotificationPreferenceRequest true "Payload notification preference request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/notification/preference [put]
+func (ctrl ControllerHTTP) UpdatePreference(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.NotificationPreferenceRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.UpdatePreference(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Notification preference updated successfully",
+	})
+}
@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	notificationrepo "github.com/arfan21/project-sprint-social-media-api/internal/notification/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	Begin(ctx context.Context) (tx pgx.Tx, err error)
+	WithTx(tx pgx.Tx) *notificationrepo.Repository
+
+	Create(ctx context.Context, data entity.Notification) (err error)
+	GetList(ctx context.Context, filter model.NotificationGetListRequest) (data []entity.Notification, err error)
+	GetUnreadCount(ctx context.Context, userID string) (count int, err error)
+	MarkRead(ctx context.Context, id, userID string) (err error)
+	MarkAllRead(ctx context.Context, userID string) (err error)
+	GetOptOut(ctx context.Context, userID string) (optOut []string, err error)
+	UpdateOptOut(ctx context.Context, userID string, optOut []string) (err error)
+}
@@ -0,0 +1,210 @@
+package notificationrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Repository struct {
+	db dbpostgres.Queryer
+}
+
+func New(db dbpostgres.Queryer) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r Repository) Begin(ctx context.Context) (tx pgx.Tx, err error) {
+	return r.db.Begin(ctx)
+}
+
+func (r Repository) WithTx(tx pgx.Tx) *Repository {
+	r.db = tx
+	return &r
+}
+
+// Create stores the notification unless the recipient opted out of the notification type or one of the users blocked the other
+func (r Repository) Create(ctx context.Context, data entity.Notification) (err error) {
+	query := `
+		INSERT INTO notifications (id, userId, actorId, type, postId, commentId)
+		SELECT $1, u.id, $3, $4, $5, $6
+		FROM users u
+		WHERE u.id = $2 AND NOT ($4 = ANY(u.notificationOptOut))
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.userIdBlocker = $2 AND b.userIdBlocked = $3) OR (b.userIdBlocker = $3 AND b.userIdBlocked = $2)
+			)
+	`
+
+	_, err = r.db.Exec(ctx, query, data.ID, data.UserID, data.ActorID, data.Type, data.PostID, data.CommentID)
+	if err != nil {
+		err = fmt.Errorf("notification.repository.Create: failed to create notification: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetList(ctx context.Context, filter model.NotificationGetListRequest) (data []entity.Notification, err error) {
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_count, n.id, n.userId, n.actorId, n.type, n.postId, n.commentId, n.readAt, n.createdAt,
+			u.name, u.imageUrl
+		FROM notifications n
+		JOIN users u ON u.id = n.actorId
+		WHERE n.userId = $1
+	`
+
+	if filter.UnreadOnly {
+		query += "AND n.readAt IS NULL "
+	}
+
+	query += `
+		ORDER BY n.id DESC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.UserID, filter.Limit, filter.Offset)
+	if err != nil {
+		err = fmt.Errorf("notification.repository.GetList: failed to get list of notification: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var notification entity.Notification
+		err = rows.Scan(
+			&notification.Total,
+			&notification.ID,
+			&notification.UserID,
+			&notification.ActorID,
+			&notification.Type,
+			&notification.PostID,
+			&notification.CommentID,
+			&notification.ReadAt,
+			&notification.CreatedAt,
+			&notification.Actor.Name,
+			&notification.Actor.ImageUrl,
+		)
+		if err != nil {
+			err = fmt.Errorf("notification.repository.GetList: failed to scan notification: %w", err)
+			return
+		}
+
+		notification.Actor.ID = notification.ActorID
+		data = append(data, notification)
+	}
+
+	return
+}
+
+func (r Repository) GetUnreadCount(ctx context.Context, userID string) (count int, err error) {
+	query := `
+		SELECT COUNT(*)
+		FROM notifications
+		WHERE userId = $1 AND readAt IS NULL
+	`
+
+	err = r.db.QueryRow(ctx, query, userID).Scan(&count)
+	if err != nil {
+		err = fmt.Errorf("notification.repository.GetUnreadCount: failed to get unread count: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) MarkRead(ctx context.Context, id, userID string) (err error) {
+	query := `
+		UPDATE notifications
+		SET readAt = COALESCE(readAt, now())
+		WHERE id = $1 AND userId = $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				err = constant.ErrNotificationNotFound
+			}
+		}
+
+		err = fmt.Errorf("notification.repository.MarkRead: failed to mark notification as read: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("notification.repository.MarkRead: failed to mark notification as read: %w", constant.ErrNotificationNotFound)
+		return
+	}
+
+	return
+}
+
+func (r Repository) MarkAllRead(ctx context.Context, userID string) (err error) {
+	query := `
+		UPDATE notifications
+		SET readAt = now()
+		WHERE userId = $1 AND readAt IS NULL
+	`
+
+	_, err = r.db.Exec(ctx, query, userID)
+	if err != nil {
+		err = fmt.Errorf("notification.repository.MarkAllRead: failed to mark all notification as read: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetOptOut(ctx context.Context, userID string) (optOut []string, err error) {
+	query := `
+		SELECT notificationOptOut
+		FROM users
+		WHERE id = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, userID).Scan(&optOut)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrUserNotFound
+		}
+
+		err = fmt.Errorf("notification.repository.GetOptOut: failed to get notification opt out: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) UpdateOptOut(ctx context.Context, userID string, optOut []string) (err error) {
+	query := `
+		UPDATE users
+		SET notificationOptOut = $1
+		WHERE id = $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, optOut, userID)
+	if err != nil {
+		err = fmt.Errorf("notification.repository.UpdateOptOut: failed to update notification opt out: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("notification.repository.UpdateOptOut: failed to update notification opt out: %w", constant.ErrUserNotFound)
+		return
+	}
+
+	return
+}
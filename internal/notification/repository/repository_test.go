@@ -0,0 +1,54 @@
+package notificationrepo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	notificationrepo "github.com/arfan21/project-sprint-social-media-api/internal/notification/repository"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres/postgrestest"
+	"github.com/google/uuid"
+)
+
+func TestCreateSkipsBlockedUsers(t *testing.T) {
+	ctx := context.Background()
+	tx := postgrestest.BeginTx(ctx, t)
+	repo := notificationrepo.New(tx)
+
+	recipientID := postgrestest.CreateUser(ctx, t, tx, "test notification recipient")
+	actorID := postgrestest.CreateUser(ctx, t, tx, "test notification actor")
+	blockedID := postgrestest.CreateUser(ctx, t, tx, "test notification blocked")
+	postgrestest.Block(ctx, t, tx, blockedID, recipientID)
+
+	for _, id := range []string{actorID, blockedID} {
+		err := repo.Create(ctx, entity.Notification{
+			ID:      uuid.New(),
+			UserID:  uuid.MustParse(recipientID),
+			ActorID: uuid.MustParse(id),
+			Type:    entity.NotificationTypeMention,
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `SELECT actorId::TEXT FROM notifications WHERE userId = $1`, recipientID)
+	if err != nil {
+		t.Fatalf("failed to get notifications: %v", err)
+	}
+	defer rows.Close()
+
+	actorIDs := []string{}
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			t.Fatalf("failed to scan notification: %v", err)
+		}
+		actorIDs = append(actorIDs, id)
+	}
+
+	if len(actorIDs) != 1 || actorIDs[0] != actorID {
+		t.Errorf("notifications of the recipient are from %v, want only from %s", actorIDs, actorID)
+	}
+}
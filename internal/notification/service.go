@@ -0,0 +1,16 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+)
+
+type Service interface {
+	Notify(ctx context.Context, req model.NotificationCreateRequest) (err error)
+	GetList(ctx context.Context, req model.NotificationGetListRequest) (res model.NotificationListResponse, count int, err error)
+	MarkRead(ctx context.Context, req model.NotificationMarkReadRequest) (err error)
+	MarkAllRead(ctx context.Context, userID string) (err error)
+	GetPreference(ctx context.Context, userID string) (res model.NotificationPreferenceResponse, err error)
+	UpdatePreference(ctx context.Context, req model.NotificationPreferenceRequest) (err error)
+}
@@ -0,0 +1,206 @@
+package notificationsvc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/notification"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo notification.Repository
+}
+
+func New(repo notification.Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Notify stores a notification for the recipient, users are never notified of their own actions
+func (s Service) Notify(ctx context.Context, req model.NotificationCreateRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("notification.service.Notify: failed to validate request: %w", err)
+		return
+	}
+
+	if req.UserID == req.ActorID {
+		return
+	}
+
+	userIdUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.Notify: failed to parse user id: %w", err)
+		return
+	}
+
+	actorIdUUID, err := uuid.Parse(req.ActorID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.Notify: failed to parse actor id: %w", err)
+		return
+	}
+
+	postID, err := parseNullUUID(req.PostID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.Notify: failed to parse post id: %w", err)
+		return
+	}
+
+	commentID, err := parseNullUUID(req.CommentID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.Notify: failed to parse comment id: %w", err)
+		return
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		err = fmt.Errorf("notification.service.Notify: failed to generate notification id: %w", err)
+		return
+	}
+
+	err = s.repo.Create(ctx, entity.Notification{
+		ID:        id,
+		UserID:    userIdUUID,
+		ActorID:   actorIdUUID,
+		Type:      req.Type,
+		PostID:    postID,
+		CommentID: commentID,
+	})
+	if err != nil {
+		err = fmt.Errorf("notification.service.Notify: failed to create notification: %w", err)
+		return
+	}
+
+	return
+}
+
+func parseNullUUID(s string) (id uuid.NullUUID, err error) {
+	if s == "" {
+		return
+	}
+
+	parsed, err := uuid.Parse(s)
+	if err != nil {
+		return
+	}
+
+	return uuid.NullUUID{UUID: parsed, Valid: true}, nil
+}
+
+func (s Service) GetList(ctx context.Context, req model.NotificationGetListRequest) (res model.NotificationListResponse, count int, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("notification.service.GetList: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetList(ctx, req)
+	if err != nil {
+		err = fmt.Errorf("notification.service.GetList: failed to get list of notification: %w", err)
+		return
+	}
+
+	res.UnreadCount, err = s.repo.GetUnreadCount(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.GetList: failed to get unread count: %w", err)
+		return
+	}
+
+	res.Notifications = make([]model.NotificationResponse, len(data))
+	for i, v := range data {
+		res.Notifications[i] = model.NotificationResponse{
+			NotificationID: v.ID.String(),
+			Type:           v.Type,
+			Actor: model.UserResponse{
+				UserID:   v.Actor.ID.String(),
+				Name:     v.Actor.Name,
+				ImageUrl: v.Actor.ImageUrl.String,
+			},
+			Read:      v.ReadAt.Valid,
+			CreatedAt: v.CreatedAt.Format(constant.TimeISO8601Format),
+		}
+
+		if v.PostID.Valid {
+			postID := v.PostID.UUID.String()
+			res.Notifications[i].PostID = &postID
+		}
+
+		if v.CommentID.Valid {
+			commentID := v.CommentID.UUID.String()
+			res.Notifications[i].CommentID = &commentID
+		}
+	}
+
+	if len(data) > 0 {
+		count = data[0].Total
+	}
+
+	return
+}
+
+func (s Service) MarkRead(ctx context.Context, req model.NotificationMarkReadRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("notification.service.MarkRead: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.repo.MarkRead(ctx, req.NotificationID, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.MarkRead: failed to mark notification as read: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) MarkAllRead(ctx context.Context, userID string) (err error) {
+	err = s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.MarkAllRead: failed to mark all notification as read: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) GetPreference(ctx context.Context, userID string) (res model.NotificationPreferenceResponse, err error) {
+	res.OptOut, err = s.repo.GetOptOut(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("notification.service.GetPreference: failed to get notification opt out: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) UpdatePreference(ctx context.Context, req model.NotificationPreferenceRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("notification.service.UpdatePreference: failed to validate request: %w", err)
+		return
+	}
+
+	optOut := []string{}
+	seen := make(map[string]struct{})
+	for _, v := range req.OptOut {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+
+		seen[v] = struct{}{}
+		optOut = append(optOut, v)
+	}
+
+	err = s.repo.UpdateOptOut(ctx, req.UserID, optOut)
+	if err != nil {
+		err = fmt.Errorf("notification.service.UpdatePreference: failed to update notification opt out: %w", err)
+		return
+	}
+
+	return
+}
@@ -20,7 +20,7 @@ func New(svc post.Service) *ControllerHTTP {
 }
 
 // @Summary Create post
-// @Description Create post
+// @Description Create post, visibility is one of public, friends or only_me and defaults to friends. Tags are lower cased without the leading #. Mentions like @name of the creator or their friends are linked to the user profile
 // @Tags post
 // @Accept json
 // @Produce json
@@ -54,7 +54,7 @@ func (ctrl ControllerHTTP) Create(c *fiber.Ctx) error {
 }
 
 // @Summary Create comment
-// @Description Create comment
+// @Description Create comment, mentions like @name of the post creator or their friends are linked to the user profile
 // @Tags post
 // @Accept json
 // @Produce json
@@ -62,6 +62,7 @@ func (ctrl ControllerHTTP) Create(c *fiber.Ctx) error {
 // @Param body body model.PostCommentRequest true "Payload post comment request"
 // @Success 200 {object} pkgutil.HTTPResponse
 // @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse "One of the users blocked the other"
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/post/comment [post]
 func (ctrl ControllerHTTP) CreateComment(c *fiber.Ctx) error {
@@ -88,16 +89,19 @@ func (ctrl ControllerHTTP) CreateComment(c *fiber.Ctx) error {
 }
 
 // @Summary Get list post
-// @Description Get list post
+// @Description Get list post, pass nextCursor or prevCursor of the meta as cursor to use keyset pagination, offset and total are not used for keyset pages
 // @Tags post
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "With the bearer started"
 // @Param limit query int false "Limit data"
 // @Param offset query int false "Offset data"
-// @Param search query string false "Search data"
+// @Param cursor query string false "Cursor of the next or previous page"
+// @Param search query string false "Full-text search, quote words for a phrase and end a word with * for a prefix"
 // @Param searchTag query string false "Search tag data"
-// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.PostListResponse}
+// @Param mentioned query bool false "Only posts where the user is mentioned in the post or its comments"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.PostListResponse,meta=pkgutil.MetaResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/post [get]
 func (ctrl ControllerHTTP) GetList(c *fiber.Ctx) error {
@@ -122,15 +126,490 @@ func (ctrl ControllerHTTP) GetList(c *fiber.Ctx) error {
 		req.Limit = 5
 	}
 
-	data, count, err := ctrl.svc.GetList(c.UserContext(), req)
+	data, nextCursor, prevCursor, count, err := ctrl.svc.GetList(c.UserContext(), req)
 	exception.PanicIfNeeded(err)
 
 	return c.JSON(pkgutil.HTTPResponse{
 		Data: data,
 		Meta: pkgutil.MetaResponse{
-			Offset: req.Offset,
-			Limit:  req.Limit,
-			Total:  count,
+			Offset:     req.Offset,
+			Limit:      req.Limit,
+			Total:      count,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
 		},
 	})
 }
+
+// @Summary Get list public post
+// @Description Get list public post of every user. Pagination works the same as the list post
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param limit query int false "Limit data"
+// @Param offset query int false "Offset data"
+// @Param cursor query string false "Cursor of the next or previous page"
+// @Param search query string false "Full-text search, quote words for a phrase and end a word with * for a prefix"
+// @Param searchTag query string false "Search tag data"
+// @Param mentioned query bool false "Only posts where the user is mentioned in the post or its comments"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.PostListResponse,meta=pkgutil.MetaResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/discover [get]
+func (ctrl ControllerHTTP) Discover(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.PostGetListRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	data, nextCursor, prevCursor, count, err := ctrl.svc.Discover(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: data,
+		Meta: pkgutil.MetaResponse{
+			Offset:     req.Offset,
+			Limit:      req.Limit,
+			Total:      count,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+		},
+	})
+}
+
+// @Summary Get list post of user
+// @Description Get list post created by the user, friends of the user also see friends posts and the user also sees only_me posts. Pagination works the same as the list post
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "User id"
+// @Param limit query int false "Limit data"
+// @Param offset query int false "Offset data"
+// @Param cursor query string false "Cursor of the next or previous page"
+// @Param search query string false "Full-text search, quote words for a phrase and end a word with * for a prefix"
+// @Param searchTag query string false "Search tag data"
+// @Param mentioned query bool false "Only posts where the user is mentioned in the post or its comments"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.PostListResponse,meta=pkgutil.MetaResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/{id}/post [get]
+func (ctrl ControllerHTTP) GetListByUser(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.PostGetListRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+	req.AuthorID = c.Params("id")
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	data, nextCursor, prevCursor, count, err := ctrl.svc.GetListByUser(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: data,
+		Meta: pkgutil.MetaResponse{
+			Offset:     req.Offset,
+			Limit:      req.Limit,
+			Total:      count,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+		},
+	})
+}
+
+// @Summary Get list comment of post
+// @Description Get list comment of post ordered from the oldest, use parentCommentId to get the replies of a comment
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Post id"
+// @Param limit query int false "Limit data"
+// @Param cursor query string false "Cursor of the next or previous page"
+// @Param parentCommentId query string false "Parent comment id, list the replies of the comment"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.PostCommentResponse,meta=pkgutil.MetaResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/{id}/comments [get]
+func (ctrl ControllerHTTP) GetCommentList(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.PostCommentGetListRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.PostID = c.Params("id")
+	req.UserID = claims.UserID
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	data, nextCursor, prevCursor, count, err := ctrl.svc.GetCommentList(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: data,
+		Meta: pkgutil.MetaResponse{
+			Limit:      req.Limit,
+			Total:      count,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+		},
+	})
+}
+
+// @Summary Get post by id
+// @Description Get post by id, public posts can be seen by everyone, friends posts by the creator and their friends and only_me posts by the creator
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Post id"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.PostListResponse}
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/{id} [get]
+func (ctrl ControllerHTTP) GetByID(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	res, err := ctrl.svc.GetByID(c.UserContext(), model.PostGetByIDRequest{
+		PostID: c.Params("id"),
+		UserID: claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: res,
+	})
+}
+
+// @Summary Update post
+// @Description Update post, only the creator can update the post. Mentions of an edited post are linked again and the newly mentioned users are notified
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Post id"
+// @Param body body model.PostUpdateRequest true "Payload post update request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/{id} [patch]
+func (ctrl ControllerHTTP) Update(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.PostUpdateRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.PostID = c.Params("id")
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.Update(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Post updated successfully",
+	})
+}
+
+// @Summary Delete post
+// @Description Delete post, only the creator can delete the post
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Post id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/{id} [delete]
+func (ctrl ControllerHTTP) Delete(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.Delete(c.UserContext(), model.PostDeleteRequest{
+		PostID: c.Params("id"),
+		UserID: claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Post deleted successfully",
+	})
+}
+
+// @Summary React to post
+// @Description React to post, reacting again replaces the previous reaction
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Post id"
+// @Param body body model.PostReactionRequest true "Payload post reaction request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/{id}/reaction [post]
+func (ctrl ControllerHTTP) React(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.PostReactionRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.PostID = c.Params("id")
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.React(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Reaction saved successfully",
+	})
+}
+
+// @Summary Delete post reaction
+// @Description Delete own reaction on post
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Post id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/{id}/reaction [delete]
+func (ctrl ControllerHTTP) DeleteReaction(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.DeleteReaction(c.UserContext(), model.PostReactionDeleteRequest{
+		PostID: c.Params("id"),
+		UserID: claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Reaction deleted successfully",
+	})
+}
+
+// @Summary Update comment
+// @Description Update comment, only the comment creator can update the comment. Mentions are linked again and the newly mentioned users are notified
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param commentId path string true "Comment id"
+// @Param body body model.PostCommentUpdateRequest true "Payload comment update request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/comment/{commentId} [patch]
+func (ctrl ControllerHTTP) UpdateComment(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.PostCommentUpdateRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.CommentID = c.Params("commentId")
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.UpdateComment(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Comment updated successfully",
+	})
+}
+
+// @Summary Delete comment
+// @Description Delete comment and its replies, only the comment creator or the post owner can delete the comment
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param commentId path string true "Comment id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/comment/{commentId} [delete]
+func (ctrl ControllerHTTP) DeleteComment(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.DeleteComment(c.UserContext(), model.PostCommentDeleteRequest{
+		CommentID: c.Params("commentId"),
+		UserID:    claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Comment deleted successfully",
+	})
+}
+
+// @Summary React to comment
+// @Description React to comment, reacting again replaces the previous reaction
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param commentId path string true "Comment id"
+// @Param body body model.PostCommentReactionRequest true "Payload comment reaction request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/comment/{commentId}/reaction [post]
+func (ctrl ControllerHTTP) ReactComment(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.PostCommentReactionRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.CommentID = c.Params("commentId")
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.ReactComment(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Reaction saved successfully",
+	})
+}
+
+// @Summary Delete comment reaction
+// @Description Delete own reaction on comment
+// @Tags post
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param commentId path string true "Comment id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/post/comment/{commentId}/reaction [delete]
+func (ctrl ControllerHTTP) DeleteCommentReaction(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.DeleteCommentReaction(c.UserContext(), model.PostCommentReactionDeleteRequest{
+		CommentID: c.Params("commentId"),
+		UserID:    claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Message: "Reaction deleted successfully",
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
 	postrepo "github.com/arfan21/project-sprint-social-media-api/internal/post/repository"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -15,7 +16,12 @@ type Repository interface {
 
 	Create(ctx context.Context, data entity.Post) (err error)
 	GetByID(ctx context.Context, id string) (data entity.Post, err error)
+	Update(ctx context.Context, data entity.Post) (err error)
+	SoftDelete(ctx context.Context, id string) (err error)
+	DecrementTags(ctx context.Context, names []string) (err error)
 	CreateComment(ctx context.Context, data entity.PostComment) (err error)
+	CreateMentions(ctx context.Context, postID uuid.UUID, commentID uuid.NullUUID, userIDs []string) (err error)
+	ReplaceMentions(ctx context.Context, postID uuid.UUID, commentID uuid.NullUUID, userIDs []string) (addedIDs []string, err error)
 	GetList(ctx context.Context, filter model.PostGetListRequest) (
 		res []entity.Post,
 		postIDs []string,
@@ -23,5 +29,26 @@ type Repository interface {
 		err error,
 	)
 	GetCountList(ctx context.Context, filter model.PostGetListRequest) (count int, err error)
-	GetCommentsByPostIDsMap(ctx context.Context, postIDs []string, userIDsUnique map[string]struct{}) (res map[string][]entity.PostComment, err error)
+	IncrementCommentCount(ctx context.Context, postID string) (err error)
+	IncrementReplyCount(ctx context.Context, commentID string) (err error)
+	DecrementCommentCount(ctx context.Context, postID string, count int) (err error)
+	DecrementReplyCount(ctx context.Context, commentID string) (err error)
+	CreateCommentRevision(ctx context.Context, data entity.PostCommentRevision) (err error)
+	UpdateComment(ctx context.Context, data entity.PostComment) (err error)
+	SoftDeleteComment(ctx context.Context, id string) (count int, err error)
+	GetCommentsByPostIDsMap(ctx context.Context, postIDs []string, limit int, userIDsUnique map[string]struct{}) (res map[string][]entity.PostComment, err error)
+	GetCommentList(ctx context.Context, filter model.PostCommentGetListRequest) (
+		res []entity.PostComment,
+		userIdUnique map[string]struct{},
+		err error,
+	)
+	GetCommentByID(ctx context.Context, id string) (data entity.PostComment, err error)
+
+	GetReactionType(ctx context.Context, target, targetID, userID string) (reactionType string, err error)
+	CreateReaction(ctx context.Context, target string, data entity.Reaction) (err error)
+	UpdateReaction(ctx context.Context, target string, data entity.Reaction) (err error)
+	DeleteReaction(ctx context.Context, target, targetID, userID string) (reactionType string, err error)
+	IncrementReactionCount(ctx context.Context, target, targetID, reactionType string) (err error)
+	DecrementReactionCount(ctx context.Context, target, targetID, reactionType string) (err error)
+	GetViewerReactionsMap(ctx context.Context, target string, targetIDs []string, userID string) (res map[string]string, err error)
 }
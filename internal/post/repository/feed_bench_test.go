@@ -0,0 +1,164 @@
+package postrepo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	postrepo "github.com/arfan21/project-sprint-social-media-api/internal/post/repository"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres/postgrestest"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	feedPostsPerUser = 20
+	feedStrangers    = 2000
+	feedPageLimit    = 20
+	feedDeepOffset   = 1000
+)
+
+// friend counts of the viewers, the largest one is the case the feed query has to scale to
+var feedFriendCounts = []int{10, 100, 1000, 5000}
+
+// BenchmarkGetListFeed seeds viewers with an increasing number of friends and measures the home timeline query
+// for the first page, a keyset page and a deep offset page. It needs a migrated database configured through the
+// DB_ environment variables, everything runs in one transaction that is rolled back.
+//
+//	DB_HOST=127.0.0.1 ... go test -run '^$' -bench GetListFeed ./internal/post/repository/
+func BenchmarkGetListFeed(b *testing.B) {
+	ctx := context.Background()
+	tx := postgrestest.BeginTx(ctx, b)
+
+	// posts of users outside every feed, the query must not scan them
+	_, err := seedUsers(ctx, tx, feedStrangers)
+	if err != nil {
+		b.Fatalf("failed to seed strangers: %v", err)
+	}
+
+	repo := postrepo.New(tx)
+	for _, friendCount := range feedFriendCounts {
+		viewerID, err := seedViewer(ctx, tx, friendCount)
+		if err != nil {
+			b.Fatalf("failed to seed viewer with %d friends: %v", friendCount, err)
+		}
+
+		_, err = tx.Exec(ctx, "ANALYZE users, friends, posts")
+		if err != nil {
+			b.Fatalf("failed to analyze tables: %v", err)
+		}
+
+		filter := model.PostGetListRequest{UserID: viewerID, Limit: feedPageLimit}
+
+		// the feed must hold every post of the viewer and the friends exactly once before it is worth timing
+		data, _, _, err := repo.GetList(ctx, filter)
+		if err != nil {
+			b.Fatalf("failed to get feed: %v", err)
+		}
+
+		if len(data) == 0 {
+			b.Fatalf("feed of viewer with %d friends is empty", friendCount)
+		}
+
+		if want := (friendCount + 1) * feedPostsPerUser; data[0].Total != want {
+			b.Fatalf("feed of viewer with %d friends has %d posts, want %d", friendCount, data[0].Total, want)
+		}
+
+		seen := make(map[uuid.UUID]struct{}, len(data))
+		for _, v := range data {
+			if _, ok := seen[v.ID]; ok {
+				b.Fatalf("feed of viewer with %d friends repeats post %s", friendCount, v.ID)
+			}
+			seen[v.ID] = struct{}{}
+		}
+
+		keysetFilter := filter
+		keysetFilter.CursorID = data[len(data)-1].ID.String()
+
+		offsetFilter := filter
+		offsetFilter.Offset = feedDeepOffset
+
+		pages := []struct {
+			name   string
+			filter model.PostGetListRequest
+		}{
+			{name: "first_page", filter: filter},
+			{name: "keyset_page", filter: keysetFilter},
+			{name: "offset_page", filter: offsetFilter},
+		}
+
+		for _, page := range pages {
+			b.Run(fmt.Sprintf("friends=%d/%s", friendCount, page.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_, _, _, err := repo.GetList(ctx, page.filter)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// seedUsers creates users with feedPostsPerUser posts each and returns their ids
+func seedUsers(ctx context.Context, tx pgx.Tx, count int) (ids []uuid.UUID, err error) {
+	rows, err := tx.Query(ctx, `
+		INSERT INTO users (name, password)
+		SELECT 'bench user ' || i, '-' FROM generate_series(1, $1) i
+		RETURNING id
+	`, count)
+	if err != nil {
+		return
+	}
+
+	ids, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return
+	}
+
+	// ids are generated here to keep the time order of uuid v7 like the api does
+	postIDs := make([]uuid.UUID, 0, len(ids)*feedPostsPerUser)
+	postUserIDs := make([]uuid.UUID, 0, len(ids)*feedPostsPerUser)
+	for i := 0; i < feedPostsPerUser; i++ {
+		for _, id := range ids {
+			postID, err := uuid.NewV7()
+			if err != nil {
+				return nil, err
+			}
+
+			postIDs = append(postIDs, postID)
+			postUserIDs = append(postUserIDs, id)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO posts (id, userId, body, tags)
+		SELECT id, userId, '<p>bench post of ' || userId || '</p>', '{bench}'
+		FROM unnest($1::UUID[], $2::UUID[]) AS t (id, userId)
+	`, postIDs, postUserIDs)
+	return
+}
+
+// seedViewer creates a user with friendCount friends, half of the friendships are added by the viewer
+// and half by the friend so both sides of the friends table are exercised
+func seedViewer(ctx context.Context, tx pgx.Tx, friendCount int) (viewerID string, err error) {
+	ids, err := seedUsers(ctx, tx, friendCount+1)
+	if err != nil {
+		return
+	}
+
+	viewer, friends := ids[0], ids[1:]
+	_, err = tx.Exec(ctx, `
+		INSERT INTO friends (userIdAdder, userIdAdded)
+		SELECT
+			CASE WHEN t.n % 2 = 0 THEN $1 ELSE t.id END,
+			CASE WHEN t.n % 2 = 0 THEN t.id ELSE $1 END
+		FROM unnest($2::UUID[]) WITH ORDINALITY AS t (id, n)
+	`, viewer, friends)
+	if err != nil {
+		return
+	}
+
+	return viewer.String(), nil
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
 	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -35,11 +35,11 @@ func (r Repository) WithTx(tx pgx.Tx) *Repository {
 
 func (r Repository) Create(ctx context.Context, data entity.Post) (err error) {
 	query := `
-		INSERT INTO posts (id, userId, body, tags)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO posts (id, userId, body, bodyOriginal, tags, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
-	_, err = r.db.Exec(ctx, query, data.ID, data.UserID, data.Body, data.Tags)
+	_, err = r.db.Exec(ctx, query, data.ID, data.UserID, data.Body, data.BodyOriginal, data.Tags, data.Visibility)
 	if err != nil {
 		err = fmt.Errorf("post.repository.Create: failed to create post: %w", err)
 		return
@@ -50,12 +50,18 @@ func (r Repository) Create(ctx context.Context, data entity.Post) (err error) {
 
 func (r Repository) GetByID(ctx context.Context, id string) (data entity.Post, err error) {
 	query := `
-		SELECT id, userId, body, tags, createdAt, updatedAt
+		SELECT
+			id, userId, body, tags, visibility, createdAt, updatedAt, commentCount,
+			likeCount, loveCount, hahaCount, wowCount, sadCount, angryCount
 		FROM posts
-		WHERE id = $1
+		WHERE id = $1 AND deletedAt IS NULL
 	`
 
-	err = r.db.QueryRow(ctx, query, id).Scan(&data.ID, &data.UserID, &data.Body, &data.Tags, &data.CreatedAt, &data.UpdatedAt)
+	err = r.db.QueryRow(ctx, query, id).Scan(
+		&data.ID, &data.UserID, &data.Body, &data.Tags, &data.Visibility, &data.CreatedAt, &data.UpdatedAt, &data.CommentCount,
+		&data.Reactions.Like, &data.Reactions.Love, &data.Reactions.Haha,
+		&data.Reactions.Wow, &data.Reactions.Sad, &data.Reactions.Angry,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			err = constant.ErrPostNotFound
@@ -75,13 +81,114 @@ func (r Repository) GetByID(ctx context.Context, id string) (data entity.Post, e
 	return
 }
 
+func (r Repository) Update(ctx context.Context, data entity.Post) (err error) {
+	query := `
+		UPDATE posts
+	`
+
+	arrArgs := []interface{}{}
+	comma := ", "
+	setQuery := ""
+
+	if data.Body != "" {
+		arrArgs = append(arrArgs, data.Body)
+		setQuery += fmt.Sprintf("body = $%d%s", len(arrArgs), comma)
+
+		arrArgs = append(arrArgs, data.BodyOriginal)
+		setQuery += fmt.Sprintf("bodyOriginal = $%d%s", len(arrArgs), comma)
+	}
+
+	if data.Tags != nil {
+		arrArgs = append(arrArgs, data.Tags)
+		setQuery += fmt.Sprintf("tags = $%d%s", len(arrArgs), comma)
+	}
+
+	if data.Visibility != "" {
+		arrArgs = append(arrArgs, data.Visibility)
+		setQuery += fmt.Sprintf("visibility = $%d%s", len(arrArgs), comma)
+	}
+
+	if len(arrArgs) == 0 {
+		return
+	}
+
+	setQuery = "SET " + setQuery[:len(setQuery)-len(comma)] + " "
+
+	query += setQuery
+	arrArgs = append(arrArgs, data.ID)
+	query += fmt.Sprintf("WHERE id = $%d AND deletedAt IS NULL", len(arrArgs))
+
+	cmd, err := r.db.Exec(ctx, query, arrArgs...)
+	if err != nil {
+		err = fmt.Errorf("post.repository.Update: failed to update post: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("post.repository.Update: failed to update post: %w", constant.ErrPostNotFound)
+		return
+	}
+
+	return
+}
+
+// SoftDelete only marks the post as deleted so the comments are kept
+func (r Repository) SoftDelete(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE posts
+		SET deletedAt = now()
+		WHERE id = $1 AND deletedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("post.repository.SoftDelete: failed to delete post: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("post.repository.SoftDelete: failed to delete post: %w", constant.ErrPostNotFound)
+		return
+	}
+
+	return
+}
+
+// DecrementTags takes one use of each tag out of the autocomplete, the tags left without a use are removed
+func (r Repository) DecrementTags(ctx context.Context, names []string) (err error) {
+	query := `
+		UPDATE tags
+		SET useCount = GREATEST(useCount - 1, 0)
+		WHERE name = ANY($1)
+	`
+
+	_, err = r.db.Exec(ctx, query, names)
+	if err != nil {
+		err = fmt.Errorf("post.repository.DecrementTags: failed to decrement tags: %w", err)
+		return
+	}
+
+	query = `
+		DELETE FROM tags
+		WHERE name = ANY($1) AND useCount = 0
+	`
+
+	_, err = r.db.Exec(ctx, query, names)
+	if err != nil {
+		err = fmt.Errorf("post.repository.DecrementTags: failed to delete unused tags: %w", err)
+		return
+	}
+
+	return
+}
+
 func (r Repository) CreateComment(ctx context.Context, data entity.PostComment) (err error) {
 	query := `
-		INSERT INTO post_comments (id, postId, userId, comment)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO post_comments (id, postId, parentCommentId, userId, comment, commentOriginal)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
-	_, err = r.db.Exec(ctx, query, data.ID, data.PostID, data.UserID, data.Comment)
+	_, err = r.db.Exec(ctx, query, data.ID, data.PostID, data.ParentCommentID, data.UserID, data.Comment, data.CommentOriginal)
 	if err != nil {
 		err = fmt.Errorf("post.repository.CreateComment: failed to create comment: %w", err)
 		return
@@ -90,17 +197,230 @@ func (r Repository) CreateComment(ctx context.Context, data entity.PostComment)
 	return
 }
 
+func (r Repository) CreateMentions(ctx context.Context, postID uuid.UUID, commentID uuid.NullUUID, userIDs []string) (err error) {
+	query := `
+		INSERT INTO post_mentions (postId, commentId, userId)
+		SELECT $1, $2, unnest($3::UUID[])
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err = r.db.Exec(ctx, query, postID, commentID, userIDs)
+	if err != nil {
+		err = fmt.Errorf("post.repository.CreateMentions: failed to create mentions: %w", err)
+		return
+	}
+
+	return
+}
+
+// ReplaceMentions sets the mentions of the post, or of the comment when commentID is valid, to the users,
+// addedIDs are the users that were not mentioned before
+func (r Repository) ReplaceMentions(ctx context.Context, postID uuid.UUID, commentID uuid.NullUUID, userIDs []string) (addedIDs []string, err error) {
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+
+	query := `
+		WITH deleted AS (
+			DELETE FROM post_mentions
+			WHERE postId = $1 AND commentId IS NOT DISTINCT FROM $2 AND NOT (userId = ANY($3::UUID[]))
+		)
+		INSERT INTO post_mentions (postId, commentId, userId)
+		SELECT $1, $2, unnest($3::UUID[])
+		ON CONFLICT DO NOTHING
+		RETURNING userId
+	`
+
+	rows, err := r.db.Query(ctx, query, postID, commentID, userIDs)
+	if err != nil {
+		err = fmt.Errorf("post.repository.ReplaceMentions: failed to replace mentions: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		err = rows.Scan(&userID)
+		if err != nil {
+			err = fmt.Errorf("post.repository.ReplaceMentions: failed to scan user id: %w", err)
+			return
+		}
+
+		addedIDs = append(addedIDs, userID.String())
+	}
+
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("post.repository.ReplaceMentions: failed to replace mentions: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) IncrementCommentCount(ctx context.Context, postID string) (err error) {
+	query := `
+		UPDATE posts
+		SET commentCount = commentCount + 1
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, postID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.IncrementCommentCount: failed to increment comment count: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) IncrementReplyCount(ctx context.Context, commentID string) (err error) {
+	query := `
+		UPDATE post_comments
+		SET replyCount = replyCount + 1
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, commentID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.IncrementReplyCount: failed to increment reply count: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) DecrementCommentCount(ctx context.Context, postID string, count int) (err error) {
+	query := `
+		UPDATE posts
+		SET commentCount = GREATEST(commentCount - $1, 0)
+		WHERE id = $2
+	`
+
+	_, err = r.db.Exec(ctx, query, count, postID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.DecrementCommentCount: failed to decrement comment count: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) DecrementReplyCount(ctx context.Context, commentID string) (err error) {
+	query := `
+		UPDATE post_comments
+		SET replyCount = GREATEST(replyCount - 1, 0)
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, commentID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.DecrementReplyCount: failed to decrement reply count: %w", err)
+		return
+	}
+
+	return
+}
+
+// CreateCommentRevision copies the current content of the comment into the revision history
+// and locks the comment row, it is expected to be called within a transaction before UpdateComment
+func (r Repository) CreateCommentRevision(ctx context.Context, data entity.PostCommentRevision) (err error) {
+	query := `
+		INSERT INTO post_comment_revisions (id, commentId, comment, commentOriginal)
+		SELECT $1, id, comment, commentOriginal
+		FROM post_comments
+		WHERE id = $2 AND deletedAt IS NULL
+		FOR UPDATE
+	`
+
+	cmd, err := r.db.Exec(ctx, query, data.ID, data.CommentID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.CreateCommentRevision: failed to create comment revision: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("post.repository.CreateCommentRevision: failed to create comment revision: %w", constant.ErrCommentNotFound)
+		return
+	}
+
+	return
+}
+
+func (r Repository) UpdateComment(ctx context.Context, data entity.PostComment) (err error) {
+	query := `
+		UPDATE post_comments
+		SET comment = $1, commentOriginal = $2, editedAt = now()
+		WHERE id = $3 AND deletedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, data.Comment, data.CommentOriginal, data.ID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.UpdateComment: failed to update comment: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("post.repository.UpdateComment: failed to update comment: %w", constant.ErrCommentNotFound)
+		return
+	}
+
+	return
+}
+
+// SoftDeleteComment marks the comment and all of its replies as deleted,
+// it returns the number of deleted comments so the comment count can be kept in sync
+func (r Repository) SoftDeleteComment(ctx context.Context, id string) (count int, err error) {
+	query := `
+		WITH RECURSIVE thread AS (
+			SELECT id
+			FROM post_comments
+			WHERE id = $1 AND deletedAt IS NULL
+			UNION ALL
+			SELECT c.id
+			FROM post_comments c
+			JOIN thread t ON c.parentCommentId = t.id
+			WHERE c.deletedAt IS NULL
+		)
+		UPDATE post_comments
+		SET deletedAt = now()
+		WHERE id IN (SELECT id FROM thread)
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("post.repository.SoftDeleteComment: failed to delete comment: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("post.repository.SoftDeleteComment: failed to delete comment: %w", constant.ErrCommentNotFound)
+		return
+	}
+
+	count = int(cmd.RowsAffected())
+	return
+}
+
 // queryGetListWithFilter is a helper function to get post of user with filter
-// the query is expected to be joined with post_comments and friends table
-// where table posts as p, and friends as f
+// the query is expected to select from table posts as p.
+// When the author id is set only posts of the author are returned and when discover is set posts of every user are returned,
+// the caller is expected to limit the visibilities for both.
+// Otherwise when the user id is set only posts of the user and their friends are returned,
+// the ids are collected once in the feed_users CTE so a post is never repeated per friendship row,
+// and only_me posts of the friends are left out.
+// When the search has searchable words the tsquery is always bound to $1,
+// so the select columns can use it for the highlight.
+// When the cursor id is set the page is a keyset page ordered by id only, the offset is ignored
 func (r Repository) queryGetListWithFilter(ctx context.Context, query string, filter model.PostGetListRequest) (rows pgx.Rows, err error) {
 	arrArgs := []interface{}{}
-	whereQuery := ""
 	andStatement := " AND "
+	whereQuery := "p.deletedAt IS NULL" + andStatement
 
-	if filter.Search != "" {
-		arrArgs = append(arrArgs, "%"+strings.ToLower(filter.Search)+"%")
-		whereQuery += fmt.Sprintf("(LOWER(p.body) LIKE $%d) %s", len(arrArgs), andStatement)
+	tsQuery := buildTSQuery(filter.Search)
+	if tsQuery != "" {
+		arrArgs = append(arrArgs, tsQuery)
+		whereQuery += fmt.Sprintf("p.bodySearch @@ to_tsquery('%s', $%d) %s", searchConfig, len(arrArgs), andStatement)
 	}
 
 	if len(filter.SearchTags) > 0 {
@@ -109,27 +429,96 @@ func (r Repository) queryGetListWithFilter(ctx context.Context, query string, fi
 	}
 
 	// only friend post or self post
+	withQuery := ""
+	if filter.AuthorID != "" {
+		arrArgs = append(arrArgs, filter.AuthorID)
+		whereQuery += fmt.Sprintf("p.userId = $%d %s", len(arrArgs), andStatement)
+	} else if !filter.Discover && filter.UserID != "" {
+		arrArgs = append(arrArgs, filter.UserID)
+		withQuery = fmt.Sprintf(`
+			WITH feed_users AS (
+				SELECT $%[1]d::UUID AS userId
+				UNION ALL
+				SELECT userIdAdded FROM friends WHERE userIdAdder = $%[1]d
+				UNION ALL
+				SELECT userIdAdder FROM friends WHERE userIdAdded = $%[1]d
+			)
+		`, len(arrArgs))
+		whereQuery += fmt.Sprintf("p.userId IN (SELECT userId FROM feed_users) %s", andStatement)
+		whereQuery += fmt.Sprintf("(p.userId = $%d OR p.visibility <> '%s') %s", len(arrArgs), entity.PostVisibilityOnlyMe, andStatement)
+	}
+
+	// mentioned in the post itself or in one of its comments that is not deleted
+	if filter.Mentioned && filter.UserID != "" {
+		arrArgs = append(arrArgs, filter.UserID)
+		whereQuery += fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM post_mentions m
+			LEFT JOIN post_comments c ON c.id = m.commentId
+			WHERE m.postId = p.id AND m.userId = $%d AND c.deletedAt IS NULL
+		) %s`, len(arrArgs), andStatement)
+	}
+
+	// posts of muted users are hidden from the feed and discover, the profile of the user still shows them
+	if filter.AuthorID == "" && filter.UserID != "" {
+		arrArgs = append(arrArgs, filter.UserID)
+		whereQuery += fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM user_mutes mu
+			WHERE mu.userIdMuter = $%d AND mu.userIdMuted = p.userId
+		) %s`, len(arrArgs), andStatement)
+	}
+
+	// posts of users blocked by the viewer or who blocked the viewer are never listed, not even on their profile
 	if filter.UserID != "" {
 		arrArgs = append(arrArgs, filter.UserID)
-		whereQuery += fmt.Sprintf("(f.useridadder = $%d OR f.useridadded = $%d ) %s", len(arrArgs), len(arrArgs), andStatement)
+		whereQuery += fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM user_blocks b
+			WHERE (b.userIdBlocker = $%[1]d AND b.userIdBlocked = p.userId) OR (b.userIdBlocker = p.userId AND b.userIdBlocked = $%[1]d)
+		) %s`, len(arrArgs), andStatement)
 	}
 
-	if lenArgs := len(arrArgs); lenArgs > 0 {
-		whereQuery = "WHERE " + whereQuery[:len(whereQuery)-len(andStatement)] + " "
+	if len(filter.Visibilities) > 0 {
+		arrArgs = append(arrArgs, filter.Visibilities)
+		whereQuery += fmt.Sprintf("p.visibility = ANY ($%d) %s", len(arrArgs), andStatement)
+	}
+
+	// newest first, so the previous page has greater ids
+	if filter.CursorID != "" {
+		operator := "<"
+		if filter.CursorPrev {
+			operator = ">"
+		}
+
+		arrArgs = append(arrArgs, filter.CursorID)
+		whereQuery += fmt.Sprintf("p.id %s $%d %s", operator, len(arrArgs), andStatement)
 	}
 
-	query += whereQuery
+	whereQuery = "WHERE " + whereQuery[:len(whereQuery)-len(andStatement)] + " "
+
+	query = withQuery + query + whereQuery
 
 	if !filter.DisableOrder {
-		query += "ORDER BY p.id DESC "
+		if filter.CursorID != "" {
+			// the previous page is read backward and reversed by the caller
+			orderBy := "DESC"
+			if filter.CursorPrev {
+				orderBy = "ASC"
+			}
+			query += fmt.Sprintf("ORDER BY p.id %s ", orderBy)
+		} else if tsQuery != "" {
+			query += fmt.Sprintf("ORDER BY ts_rank(p.bodySearch, to_tsquery('%s', $1)) DESC, p.id DESC ", searchConfig)
+		} else {
+			query += "ORDER BY p.id DESC "
+		}
 	}
 
 	if !filter.DisableOffset {
 		arrArgs = append(arrArgs, filter.Limit)
 		query += fmt.Sprintf("LIMIT $%d ", len(arrArgs))
 
-		arrArgs = append(arrArgs, filter.Offset)
-		query += fmt.Sprintf("OFFSET $%d ", len(arrArgs))
+		if filter.CursorID == "" {
+			arrArgs = append(arrArgs, filter.Offset)
+			query += fmt.Sprintf("OFFSET $%d ", len(arrArgs))
+		}
 	}
 
 	return r.db.Query(ctx, query, arrArgs...)
@@ -141,11 +530,27 @@ func (r Repository) GetList(ctx context.Context, filter model.PostGetListRequest
 	userIdUnique map[string]struct{},
 	err error,
 ) {
+	highlightColumn := "NULL::TEXT"
+	if buildTSQuery(filter.Search) != "" {
+		highlightColumn = fmt.Sprintf(
+			"ts_headline('%s', regexp_replace(p.body, '<[^>]*>', ' ', 'g'), to_tsquery('%s', $1), '%s')",
+			searchConfig, searchConfig, headlineOptions,
+		)
+	}
+
+	// counting every matching row is what makes deep pages slow, keyset pages skip it
+	totalColumn := "COUNT(*) OVER()"
+	if filter.CursorID != "" {
+		totalColumn = "0"
+	}
+
 	query := `
 		SELECT
-			p.id, p.userId, p.body, p.tags, p.createdAt, COUNT(*) OVER() AS total_count
+			p.id, p.userId, p.body, p.tags, p.visibility, p.createdAt, p.commentCount,
+			p.likeCount, p.loveCount, p.hahaCount, p.wowCount, p.sadCount, p.angryCount,
+			` + highlightColumn + ` AS highlight,
+			` + totalColumn + ` AS total_count
 		FROM posts p
-		LEFT JOIN friends f ON (f.useridadder = p.userId OR f.useridadded = p.userId)
 	`
 
 	rows, err := r.queryGetListWithFilter(ctx, query, filter)
@@ -159,7 +564,13 @@ func (r Repository) GetList(ctx context.Context, filter model.PostGetListRequest
 	for rows.Next() {
 		var post entity.Post
 
-		err = rows.Scan(&post.ID, &post.UserID, &post.Body, &post.Tags, &post.CreatedAt, &post.Total)
+		err = rows.Scan(
+			&post.ID, &post.UserID, &post.Body, &post.Tags, &post.Visibility, &post.CreatedAt, &post.CommentCount,
+			&post.Reactions.Like, &post.Reactions.Love, &post.Reactions.Haha,
+			&post.Reactions.Wow, &post.Reactions.Sad, &post.Reactions.Angry,
+			&post.Highlight,
+			&post.Total,
+		)
 		if err != nil {
 			err = fmt.Errorf("post.repository.GetList: failed to scan rows: %w", err)
 			return
@@ -176,9 +587,8 @@ func (r Repository) GetList(ctx context.Context, filter model.PostGetListRequest
 
 func (r Repository) GetCountList(ctx context.Context, filter model.PostGetListRequest) (count int, err error) {
 	query := `
-		SELECT COUNT(DISTINCT(p.id))
+		SELECT COUNT(*)
 		FROM posts p
-		LEFT JOIN friends f ON (p.userId = f.useridadder OR p.userId = f.useridadded)
 	`
 	filter.DisableOffset = true
 	filter.DisableOrder = true
@@ -199,35 +609,381 @@ func (r Repository) GetCountList(ctx context.Context, filter model.PostGetListRe
 	return
 }
 
-func (r Repository) GetCommentsByPostIDsMap(ctx context.Context, postIDs []string, userIDsUnique map[string]struct{}) (res map[string][]entity.PostComment, err error) {
+// GetCommentsByPostIDsMap returns the latest top level comments of each post, at most limit comments per post
+func (r Repository) GetCommentsByPostIDsMap(ctx context.Context, postIDs []string, limit int, userIDsUnique map[string]struct{}) (res map[string][]entity.PostComment, err error) {
 	query := `
-		SELECT id, postId, userId, comment, createdAt
-		FROM post_comments
-		WHERE postId = ANY($1)
+		SELECT
+			c.id, c.postId, c.parentCommentId, c.userId, c.comment, c.replyCount, c.createdAt, c.editedAt,
+			c.likeCount, c.loveCount, c.hahaCount, c.wowCount, c.sadCount, c.angryCount
+		FROM unnest($1::uuid[]) AS p(id)
+		CROSS JOIN LATERAL (
+			SELECT *
+			FROM post_comments pc
+			WHERE pc.postId = p.id AND pc.parentCommentId IS NULL AND pc.deletedAt IS NULL
+			ORDER BY pc.id DESC
+			LIMIT $2
+		) c
+		ORDER BY c.id ASC
 	`
-	rows, err := r.db.Query(ctx, query, postIDs)
+	rows, err := r.db.Query(ctx, query, postIDs, limit)
 	if err != nil {
 		err = fmt.Errorf("post.repository.GetCommentsByPostIDsMap: failed to get comments by post ids: %w", err)
 		return
 	}
+	defer rows.Close()
 
 	res = make(map[string][]entity.PostComment)
 	for rows.Next() {
 		var comment entity.PostComment
 
-		err = rows.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Comment, &comment.CreatedAt)
+		err = rows.Scan(
+			&comment.ID, &comment.PostID, &comment.ParentCommentID, &comment.UserID, &comment.Comment, &comment.ReplyCount, &comment.CreatedAt, &comment.EditedAt,
+			&comment.Reactions.Like, &comment.Reactions.Love, &comment.Reactions.Haha,
+			&comment.Reactions.Wow, &comment.Reactions.Sad, &comment.Reactions.Angry,
+		)
 		if err != nil {
 			err = fmt.Errorf("post.repository.GetCommentsByPostIDsMap: failed to scan rows: %w", err)
 			return
 		}
 
-		if _, ok := res[comment.PostID.String()]; !ok {
-			res[comment.PostID.String()] = []entity.PostComment{}
-		}
-
 		res[comment.PostID.String()] = append(res[comment.PostID.String()], comment)
 		userIDsUnique[comment.UserID.String()] = struct{}{}
 	}
 
 	return
 }
+
+// GetCommentList returns comments of a post ordered from the oldest, only top level comments
+// are returned unless the parent comment id is set.
+// The page starts after the cursor id, or ends before it when the cursor points to the previous page
+func (r Repository) GetCommentList(ctx context.Context, filter model.PostCommentGetListRequest) (
+	res []entity.PostComment,
+	userIdUnique map[string]struct{},
+	err error,
+) {
+	query := `
+		SELECT
+			id, postId, parentCommentId, userId, comment, replyCount, createdAt, editedAt,
+			likeCount, loveCount, hahaCount, wowCount, sadCount, angryCount
+		FROM post_comments
+		WHERE postId = $1 AND deletedAt IS NULL
+	`
+	arrArgs := []interface{}{filter.PostID}
+
+	if filter.ParentCommentID != "" {
+		arrArgs = append(arrArgs, filter.ParentCommentID)
+		query += fmt.Sprintf("AND parentCommentId = $%d ", len(arrArgs))
+	} else {
+		query += "AND parentCommentId IS NULL "
+	}
+
+	orderBy := "ASC"
+	if filter.CursorID != "" {
+		operator := ">"
+		if filter.CursorPrev {
+			operator = "<"
+			// the previous page is read backward and reversed by the caller
+			orderBy = "DESC"
+		}
+
+		arrArgs = append(arrArgs, filter.CursorID)
+		query += fmt.Sprintf("AND id %s $%d ", operator, len(arrArgs))
+	}
+
+	arrArgs = append(arrArgs, filter.Limit)
+	query += fmt.Sprintf("ORDER BY id %s LIMIT $%d", orderBy, len(arrArgs))
+
+	rows, err := r.db.Query(ctx, query, arrArgs...)
+	if err != nil {
+		err = fmt.Errorf("post.repository.GetCommentList: failed to get list comment: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	userIdUnique = make(map[string]struct{})
+	for rows.Next() {
+		var comment entity.PostComment
+
+		err = rows.Scan(
+			&comment.ID, &comment.PostID, &comment.ParentCommentID, &comment.UserID, &comment.Comment, &comment.ReplyCount, &comment.CreatedAt, &comment.EditedAt,
+			&comment.Reactions.Like, &comment.Reactions.Love, &comment.Reactions.Haha,
+			&comment.Reactions.Wow, &comment.Reactions.Sad, &comment.Reactions.Angry,
+		)
+		if err != nil {
+			err = fmt.Errorf("post.repository.GetCommentList: failed to scan rows: %w", err)
+			return
+		}
+
+		res = append(res, comment)
+		userIdUnique[comment.UserID.String()] = struct{}{}
+	}
+
+	return
+}
+
+func (r Repository) GetCommentByID(ctx context.Context, id string) (data entity.PostComment, err error) {
+	query := `
+		SELECT id, postId, parentCommentId, userId, comment, replyCount, createdAt, updatedAt, editedAt
+		FROM post_comments
+		WHERE id = $1 AND deletedAt IS NULL
+	`
+
+	err = r.db.QueryRow(ctx, query, id).Scan(
+		&data.ID, &data.PostID, &data.ParentCommentID, &data.UserID, &data.Comment, &data.ReplyCount, &data.CreatedAt, &data.UpdatedAt, &data.EditedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrCommentNotFound
+		}
+
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				err = constant.ErrCommentNotFound
+			}
+		}
+
+		err = fmt.Errorf("post.repository.GetCommentByID: failed to get comment by id: %w", err)
+		return
+	}
+
+	return
+}
+
+// reactionTable holds the tables used by a reaction target,
+// table stores the reactions and countTable stores the denormalized counts
+type reactionTable struct {
+	table      string
+	idColumn   string
+	countTable string
+}
+
+var reactionTables = map[string]reactionTable{
+	entity.ReactionTargetPost:    {table: "post_reactions", idColumn: "postId", countTable: "posts"},
+	entity.ReactionTargetComment: {table: "post_comment_reactions", idColumn: "commentId", countTable: "post_comments"},
+}
+
+var reactionCountColumns = map[string]string{
+	entity.ReactionTypeLike:  "likeCount",
+	entity.ReactionTypeLove:  "loveCount",
+	entity.ReactionTypeHaha:  "hahaCount",
+	entity.ReactionTypeWow:   "wowCount",
+	entity.ReactionTypeSad:   "sadCount",
+	entity.ReactionTypeAngry: "angryCount",
+}
+
+func getReactionTable(target string) (t reactionTable, err error) {
+	t, ok := reactionTables[target]
+	if !ok {
+		err = fmt.Errorf("unknown reaction target %q", target)
+		return
+	}
+
+	return
+}
+
+func getReactionCountColumn(reactionType string) (column string, err error) {
+	column, ok := reactionCountColumns[reactionType]
+	if !ok {
+		err = fmt.Errorf("unknown reaction type %q", reactionType)
+		return
+	}
+
+	return
+}
+
+// GetReactionType returns the reaction type of user on the target and locks the row,
+// it is expected to be called within a transaction
+func (r Repository) GetReactionType(ctx context.Context, target, targetID, userID string) (reactionType string, err error) {
+	t, err := getReactionTable(target)
+	if err != nil {
+		err = fmt.Errorf("post.repository.GetReactionType: %w", err)
+		return
+	}
+
+	query := fmt.Sprintf(`
+		SELECT type
+		FROM %s
+		WHERE %s = $1 AND userId = $2
+		FOR UPDATE
+	`, t.table, t.idColumn)
+
+	err = r.db.QueryRow(ctx, query, targetID, userID).Scan(&reactionType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrReactionNotFound
+		}
+
+		err = fmt.Errorf("post.repository.GetReactionType: failed to get reaction: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) CreateReaction(ctx context.Context, target string, data entity.Reaction) (err error) {
+	t, err := getReactionTable(target)
+	if err != nil {
+		err = fmt.Errorf("post.repository.CreateReaction: %w", err)
+		return
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s, userId, type)
+		VALUES ($1, $2, $3)
+	`, t.table, t.idColumn)
+
+	_, err = r.db.Exec(ctx, query, data.TargetID, data.UserID, data.Type)
+	if err != nil {
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLUniqueViolation {
+				err = constant.ErrReactionAlreadyExists
+			}
+		}
+
+		err = fmt.Errorf("post.repository.CreateReaction: failed to create reaction: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) UpdateReaction(ctx context.Context, target string, data entity.Reaction) (err error) {
+	t, err := getReactionTable(target)
+	if err != nil {
+		err = fmt.Errorf("post.repository.UpdateReaction: %w", err)
+		return
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET type = $1
+		WHERE %s = $2 AND userId = $3
+	`, t.table, t.idColumn)
+
+	cmd, err := r.db.Exec(ctx, query, data.Type, data.TargetID, data.UserID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.UpdateReaction: failed to update reaction: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("post.repository.UpdateReaction: failed to update reaction: %w", constant.ErrReactionNotFound)
+		return
+	}
+
+	return
+}
+
+// DeleteReaction deletes the reaction of user on the target and returns the deleted reaction type
+func (r Repository) DeleteReaction(ctx context.Context, target, targetID, userID string) (reactionType string, err error) {
+	t, err := getReactionTable(target)
+	if err != nil {
+		err = fmt.Errorf("post.repository.DeleteReaction: %w", err)
+		return
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE %s = $1 AND userId = $2
+		RETURNING type
+	`, t.table, t.idColumn)
+
+	err = r.db.QueryRow(ctx, query, targetID, userID).Scan(&reactionType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrReactionNotFound
+		}
+
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				err = constant.ErrReactionNotFound
+			}
+		}
+
+		err = fmt.Errorf("post.repository.DeleteReaction: failed to delete reaction: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) IncrementReactionCount(ctx context.Context, target, targetID, reactionType string) (err error) {
+	err = r.addReactionCount(ctx, target, targetID, reactionType, 1)
+	if err != nil {
+		err = fmt.Errorf("post.repository.IncrementReactionCount: failed to increment reaction count: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) DecrementReactionCount(ctx context.Context, target, targetID, reactionType string) (err error) {
+	err = r.addReactionCount(ctx, target, targetID, reactionType, -1)
+	if err != nil {
+		err = fmt.Errorf("post.repository.DecrementReactionCount: failed to decrement reaction count: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) addReactionCount(ctx context.Context, target, targetID, reactionType string, delta int) (err error) {
+	t, err := getReactionTable(target)
+	if err != nil {
+		return
+	}
+
+	column, err := getReactionCountColumn(reactionType)
+	if err != nil {
+		return
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET %s = GREATEST(%s + $1, 0)
+		WHERE id = $2
+	`, t.countTable, column, column)
+
+	_, err = r.db.Exec(ctx, query, delta, targetID)
+	return
+}
+
+// GetViewerReactionsMap returns the reaction type of user on each target keyed by target id
+func (r Repository) GetViewerReactionsMap(ctx context.Context, target string, targetIDs []string, userID string) (res map[string]string, err error) {
+	t, err := getReactionTable(target)
+	if err != nil {
+		err = fmt.Errorf("post.repository.GetViewerReactionsMap: %w", err)
+		return
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, type
+		FROM %s
+		WHERE %s = ANY($1) AND userId = $2
+	`, t.idColumn, t.table, t.idColumn)
+
+	rows, err := r.db.Query(ctx, query, targetIDs, userID)
+	if err != nil {
+		err = fmt.Errorf("post.repository.GetViewerReactionsMap: failed to get reactions: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	res = make(map[string]string)
+	for rows.Next() {
+		var reaction entity.Reaction
+
+		err = rows.Scan(&reaction.TargetID, &reaction.Type)
+		if err != nil {
+			err = fmt.Errorf("post.repository.GetViewerReactionsMap: failed to scan rows: %w", err)
+			return
+		}
+
+		res[reaction.TargetID.String()] = reaction.Type
+	}
+
+	return
+}
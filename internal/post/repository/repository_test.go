@@ -0,0 +1,132 @@
+package postrepo_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	postrepo "github.com/arfan21/project-sprint-social-media-api/internal/post/repository"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres/postgrestest"
+	"github.com/google/uuid"
+)
+
+func TestDecrementTags(t *testing.T) {
+	ctx := context.Background()
+	tx := postgrestest.BeginTx(ctx, t)
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO tags (name, useCount)
+		VALUES ('test-decrement-shared', 2), ('test-decrement-last', 1)
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed tags: %v", err)
+	}
+
+	err = postrepo.New(tx).DecrementTags(ctx, []string{"test-decrement-shared", "test-decrement-last", "test-decrement-unknown"})
+	if err != nil {
+		t.Fatalf("DecrementTags() error = %v", err)
+	}
+
+	counts := map[string]int{}
+	rows, err := tx.Query(ctx, `SELECT name, useCount FROM tags WHERE name LIKE 'test-decrement-%'`)
+	if err != nil {
+		t.Fatalf("failed to get tags: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var count int
+		err = rows.Scan(&name, &count)
+		if err != nil {
+			t.Fatalf("failed to scan tag: %v", err)
+		}
+		counts[name] = count
+	}
+
+	if len(counts) != 1 || counts["test-decrement-shared"] != 1 {
+		t.Errorf("tags after DecrementTags() = %v, want only test-decrement-shared with one use", counts)
+	}
+}
+
+func TestGetListHidesBlockedUsers(t *testing.T) {
+	ctx := context.Background()
+	tx := postgrestest.BeginTx(ctx, t)
+	repo := postrepo.New(tx)
+
+	ownerID := postgrestest.CreateUser(ctx, t, tx, "test block owner")
+	viewerID := postgrestest.CreateUser(ctx, t, tx, "test block viewer")
+	strangerID := postgrestest.CreateUser(ctx, t, tx, "test block stranger")
+	postgrestest.Block(ctx, t, tx, ownerID, viewerID)
+
+	// the tag keeps the discover list to the posts of the test
+	tag := "test-block-" + uuid.NewString()
+	postIDs := map[string]string{}
+	for _, userID := range []string{ownerID, strangerID} {
+		id := uuid.New()
+		err := repo.Create(ctx, entity.Post{
+			ID:         id,
+			UserID:     uuid.MustParse(userID),
+			Body:       "post",
+			Tags:       []string{tag},
+			Visibility: entity.PostVisibilityPublic,
+		})
+		if err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+		postIDs[userID] = id.String()
+	}
+
+	tests := []struct {
+		name   string
+		filter model.PostGetListRequest
+		want   []string
+	}{
+		{
+			name:   "blocked viewer discovers the posts of the others only",
+			filter: model.PostGetListRequest{UserID: viewerID, Discover: true},
+			want:   []string{postIDs[strangerID]},
+		},
+		{
+			name:   "blocked viewer does not see the posts of the profile",
+			filter: model.PostGetListRequest{UserID: viewerID, AuthorID: ownerID},
+			want:   []string{},
+		},
+		{
+			name:   "blocker does not see the posts of the blocked profile either",
+			filter: model.PostGetListRequest{UserID: ownerID, AuthorID: viewerID},
+			want:   []string{},
+		},
+		{
+			name:   "stranger discovers every post",
+			filter: model.PostGetListRequest{UserID: strangerID, Discover: true},
+			want:   []string{postIDs[strangerID], postIDs[ownerID]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			filter.SearchTags = []string{tag}
+			filter.Visibilities = []string{entity.PostVisibilityPublic}
+			filter.Limit = 10
+
+			_, got, _, err := repo.GetList(ctx, filter)
+			if err != nil {
+				t.Fatalf("GetList() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetList() = %v, want %v", got, tt.want)
+			}
+
+			for _, id := range tt.want {
+				if !slices.Contains(got, id) {
+					t.Errorf("GetList() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package postrepo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// searchConfig is the text search configuration used by posts.bodySearch
+const searchConfig = "english"
+
+// headlineOptions marks the matched words of the search in the snippet
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+// buildTSQuery converts a user search into a to_tsquery expression,
+// "quoted words" are searched as a phrase, a word ending with * is searched as a prefix
+// and every other word must be present. Words are reduced to letters and digits
+// so the search can never produce an invalid tsquery.
+// An empty string is returned when the search has no searchable word.
+func buildTSQuery(search string) string {
+	terms := []string{}
+
+	parts := strings.Split(search, `"`)
+	for i, part := range parts {
+		// odd parts are inside quotes
+		if i%2 == 1 {
+			words := searchWords(part)
+			if len(words) > 0 {
+				terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+			}
+			continue
+		}
+
+		for _, field := range strings.Fields(part) {
+			isPrefix := strings.HasSuffix(field, "*")
+
+			words := searchWords(field)
+			if len(words) == 0 {
+				continue
+			}
+
+			if isPrefix {
+				words[len(words)-1] += ":*"
+			}
+
+			if len(words) > 1 {
+				terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+				continue
+			}
+
+			terms = append(terms, words[0])
+		}
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// searchWords splits the text into lower cased words of letters and digits
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -9,5 +9,17 @@ import (
 type Service interface {
 	Create(ctx context.Context, req model.PostRequest) (err error)
 	CreateComment(ctx context.Context, req model.PostCommentRequest) (err error)
-	GetList(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, count int, err error)
+	GetList(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, nextCursor, prevCursor string, count int, err error)
+	GetListByUser(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, nextCursor, prevCursor string, count int, err error)
+	Discover(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, nextCursor, prevCursor string, count int, err error)
+	UpdateComment(ctx context.Context, req model.PostCommentUpdateRequest) (err error)
+	DeleteComment(ctx context.Context, req model.PostCommentDeleteRequest) (err error)
+	GetCommentList(ctx context.Context, req model.PostCommentGetListRequest) (res []model.PostCommentResponse, nextCursor, prevCursor string, count int, err error)
+	GetByID(ctx context.Context, req model.PostGetByIDRequest) (res model.PostListResponse, err error)
+	Update(ctx context.Context, req model.PostUpdateRequest) (err error)
+	Delete(ctx context.Context, req model.PostDeleteRequest) (err error)
+	React(ctx context.Context, req model.PostReactionRequest) (err error)
+	DeleteReaction(ctx context.Context, req model.PostReactionDeleteRequest) (err error)
+	ReactComment(ctx context.Context, req model.PostCommentReactionRequest) (err error)
+	DeleteCommentReaction(ctx context.Context, req model.PostCommentReactionDeleteRequest) (err error)
 }
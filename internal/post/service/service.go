@@ -2,24 +2,133 @@ package postsvc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/arfan21/project-sprint-social-media-api/config"
 	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/notification"
 	"github.com/arfan21/project-sprint-social-media-api/internal/post"
+	"github.com/arfan21/project-sprint-social-media-api/internal/tag"
 	"github.com/arfan21/project-sprint-social-media-api/internal/user"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/cursor"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/mention"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/pubsub"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/sanitizer"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
 	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
 )
 
+// mentions link to the profile of the user
+const mentionHrefPrefix = "/v1/user/"
+
 type Service struct {
-	repo    post.Repository
-	userSvc user.Service
+	repo            post.Repository
+	userSvc         user.Service
+	notificationSvc notification.Service
+	tagSvc          tag.Service
+	publisher       pubsub.Publisher
+}
+
+func New(repo post.Repository, userSvc user.Service, notificationSvc notification.Service, tagSvc tag.Service, publisher pubsub.Publisher) *Service {
+	return &Service{repo: repo, userSvc: userSvc, notificationSvc: notificationSvc, tagSvc: tagSvc, publisher: publisher}
+}
+
+// notify sends the notification on a best effort basis,
+// a failure is only logged so it never fails the action of the user
+func (s Service) notify(ctx context.Context, req model.NotificationCreateRequest) {
+	err := s.notificationSvc.Notify(ctx, req)
+	if err != nil {
+		logger.Log(ctx).Error().Err(err).Str("type", req.Type).Msg("failed to send notification")
+	}
+}
+
+// registerTags records the tags for the autocomplete on a best effort basis,
+// the autocomplete is shown to everyone so only the tags of public posts are registered
+func (s Service) registerTags(ctx context.Context, visibility string, tags []string) {
+	if visibility != entity.PostVisibilityPublic {
+		return
+	}
+
+	err := s.tagSvc.Register(ctx, tags)
+	if err != nil {
+		logger.Log(ctx).Error().Err(err).Msg("failed to register tags")
+	}
+}
+
+// diffTags returns the tags the update adds to and removes from the autocomplete,
+// only public posts count so a post made public adds all its tags and a post made private removes them all
+func diffTags(oldVisibility string, oldTags []string, visibility string, tags []string) (added, removed []string) {
+	added, removed = []string{}, []string{}
+
+	if visibility == entity.PostVisibilityPublic {
+		for _, v := range tags {
+			if oldVisibility != entity.PostVisibilityPublic || !slices.Contains(oldTags, v) {
+				added = append(added, v)
+			}
+		}
+	}
+
+	if oldVisibility == entity.PostVisibilityPublic {
+		for _, v := range oldTags {
+			if visibility != entity.PostVisibilityPublic || !slices.Contains(tags, v) {
+				removed = append(removed, v)
+			}
+		}
+	}
+
+	return
+}
+
+// publish pushes the event to the stream of the users on a best effort basis
+func (s Service) publish(ctx context.Context, eventType string, userIDs []string, data any) {
+	msg, err := pubsub.NewMessage(eventType, userIDs, data)
+	if err == nil {
+		err = s.publisher.Publish(ctx, msg)
+	}
+
+	if err != nil {
+		logger.Log(ctx).Error().Err(err).Str("type", eventType).Msg("failed to publish event")
+	}
 }
 
-func New(repo post.Repository, userSvc user.Service) *Service {
-	return &Service{repo: repo, userSvc: userSvc}
+// linkMentions links the @handles of the body to the profile of the mentioned users.
+// Only the creator of the post and their friends can be mentioned, nobody else for only_me posts,
+// and never a user blocked by the author of the body or who blocked them
+func (s Service) linkMentions(ctx context.Context, post entity.Post, authorID, body string) (linked string, userIDs []string, err error) {
+	handles := mention.Parse(body)
+	if len(handles) == 0 {
+		return body, nil, nil
+	}
+
+	resolved, err := s.userSvc.ResolveMentions(ctx, model.MentionResolveRequest{
+		OwnerID:        post.UserID.String(),
+		AuthorID:       authorID,
+		Handles:        handles,
+		IncludeFriends: post.Visibility != entity.PostVisibilityOnlyMe,
+	})
+	if err != nil {
+		err = fmt.Errorf("post.service.linkMentions: failed to resolve mentions: %w", err)
+		return
+	}
+
+	hrefs := make(map[string]string, len(resolved))
+	for _, handle := range handles {
+		userID, ok := resolved[handle]
+		if !ok {
+			continue
+		}
+
+		hrefs[handle] = mentionHrefPrefix + userID
+		userIDs = append(userIDs, userID)
+	}
+
+	return mention.Link(body, hrefs), userIDs, nil
 }
 
 func (s Service) Create(ctx context.Context, req model.PostRequest) (err error) {
@@ -29,6 +138,14 @@ func (s Service) Create(ctx context.Context, req model.PostRequest) (err error)
 		return
 	}
 
+	req.Tags = s.tagSvc.Normalize(req.Tags)
+
+	body, err := sanitizer.Sanitize("postInHtml", req.PostInHtml)
+	if err != nil {
+		err = fmt.Errorf("post.service.Create: failed to sanitize post: %w", err)
+		return
+	}
+
 	userIdUUID, err := uuid.Parse(req.UserID)
 	if err != nil {
 		err = fmt.Errorf("post.service.Create: failed to parse user id: %w", err)
@@ -41,19 +158,121 @@ func (s Service) Create(ctx context.Context, req model.PostRequest) (err error)
 		return
 	}
 
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = entity.PostVisibilityFriends
+	}
+
 	data := entity.Post{
-		ID:     id,
-		UserID: userIdUUID,
-		Body:   req.PostInHtml,
-		Tags:   req.Tags,
+		ID:         id,
+		UserID:     userIdUUID,
+		Tags:       req.Tags,
+		Visibility: visibility,
+	}
+
+	body, mentionedIDs, err := s.linkMentions(ctx, data, req.UserID, body)
+	if err != nil {
+		err = fmt.Errorf("post.service.Create: failed to link mentions: %w", err)
+		return
+	}
+
+	data.Body = body
+	data.BodyOriginal = null.NewString(req.PostInHtml, body != req.PostInHtml)
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		s.registerTags(ctx, data.Visibility, data.Tags)
+
+		for _, userID := range mentionedIDs {
+			s.notify(ctx, model.NotificationCreateRequest{
+				UserID:  userID,
+				ActorID: req.UserID,
+				Type:    entity.NotificationTypeMention,
+				PostID:  data.ID.String(),
+			})
+		}
+
+		if data.Visibility == entity.PostVisibilityOnlyMe {
+			return
+		}
+
+		friendIDs, errFriend := s.userSvc.GetFriendIDs(ctx, req.UserID)
+		if errFriend != nil {
+			logger.Log(ctx).Error().Err(errFriend).Msg("post.service.Create: failed to get friend ids to publish the post")
+			return
+		}
+
+		if len(friendIDs) > 0 {
+			s.publish(ctx, model.StreamEventPostCreated, friendIDs, model.StreamPostEvent{
+				PostID: data.ID.String(),
+				UserID: req.UserID,
+			})
+		}
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.Create: failed to begin transaction: %w", err)
+		return
 	}
 
-	err = s.repo.Create(ctx, data)
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.Create: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.Create: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	err = repo.Create(ctx, data)
 	if err != nil {
 		err = fmt.Errorf("post.service.Create: failed to create post: %w", err)
 		return
 	}
 
+	if len(mentionedIDs) > 0 {
+		err = repo.CreateMentions(ctx, data.ID, uuid.NullUUID{}, mentionedIDs)
+		if err != nil {
+			err = fmt.Errorf("post.service.Create: failed to create mentions: %w", err)
+			return
+		}
+	}
+
+	return
+}
+
+// checkNotBlocked returns ErrUserBlocked when one of the users blocked the other
+func (s Service) checkNotBlocked(ctx context.Context, userID, otherUserID string) (err error) {
+	if userID == otherUserID {
+		return
+	}
+
+	isBlocked, err := s.userSvc.IsBlocked(ctx, userID, otherUserID)
+	if err != nil {
+		err = fmt.Errorf("post.service.checkNotBlocked: failed to check is blocked: %w", err)
+		return
+	}
+
+	if isBlocked {
+		err = fmt.Errorf("post.service.checkNotBlocked: %w", constant.ErrUserBlocked)
+		return
+	}
+
 	return
 }
 
@@ -64,22 +283,51 @@ func (s Service) CreateComment(ctx context.Context, req model.PostCommentRequest
 		return
 	}
 
+	comment, err := sanitizer.Sanitize("comment", req.Comment)
+	if err != nil {
+		err = fmt.Errorf("post.service.CreateComment: failed to sanitize comment: %w", err)
+		return
+	}
+
 	postData, err := s.repo.GetByID(ctx, req.PostID)
 	if err != nil {
 		err = fmt.Errorf("post.service.CreateComment: failed to get post: %w", err)
 		return
 	}
-	if req.UserID != postData.UserID.String() {
-		isFriend, err := s.userSvc.IsFriend(ctx, req.UserID, postData.UserID.String())
+
+	canView, err := s.canView(ctx, req.UserID, postData)
+	if err != nil {
+		err = fmt.Errorf("post.service.CreateComment: failed to check post visibility: %w", err)
+		return
+	}
+
+	if !canView {
+		err = fmt.Errorf("post.service.CreateComment: user cannot see the post, %w", constant.ErrUserNotFriend)
+		return
+	}
+
+	var parentCommentID uuid.NullUUID
+	var parentUserID string
+	if req.ParentCommentID != "" {
+		parent, err := s.repo.GetCommentByID(ctx, req.ParentCommentID)
 		if err != nil {
-			err = fmt.Errorf("post.service.CreateComment: failed to check is friend: %w", err)
+			err = fmt.Errorf("post.service.CreateComment: failed to get parent comment: %w", err)
 			return err
 		}
 
-		if !isFriend {
-			err = fmt.Errorf("post.service.CreateComment: user is not friend with post owner, %w", constant.ErrUserNotFriend)
+		if parent.PostID != postData.ID {
+			err = fmt.Errorf("post.service.CreateComment: parent comment belongs to another post, %w", constant.ErrCommentNotFound)
+			return err
+		}
+
+		err = s.checkNotBlocked(ctx, req.UserID, parent.UserID.String())
+		if err != nil {
+			err = fmt.Errorf("post.service.CreateComment: %w", err)
 			return err
 		}
+
+		parentCommentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+		parentUserID = parent.UserID.String()
 	}
 
 	postIdUUID, err := uuid.Parse(req.PostID)
@@ -100,86 +348,1113 @@ func (s Service) CreateComment(ctx context.Context, req model.PostCommentRequest
 		return
 	}
 
+	comment, mentionedIDs, err := s.linkMentions(ctx, postData, req.UserID, comment)
+	if err != nil {
+		err = fmt.Errorf("post.service.CreateComment: failed to link mentions: %w", err)
+		return
+	}
+
 	data := entity.PostComment{
-		ID:      id,
-		PostID:  postIdUUID,
-		Comment: req.Comment,
-		UserID:  userIdUUID,
+		ID:              id,
+		PostID:          postIdUUID,
+		ParentCommentID: parentCommentID,
+		Comment:         comment,
+		CommentOriginal: null.NewString(req.Comment, comment != req.Comment),
+		UserID:          userIdUUID,
+	}
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		s.notify(ctx, model.NotificationCreateRequest{
+			UserID:    postData.UserID.String(),
+			ActorID:   req.UserID,
+			Type:      entity.NotificationTypeComment,
+			PostID:    postData.ID.String(),
+			CommentID: data.ID.String(),
+		})
+
+		if req.UserID != postData.UserID.String() {
+			s.publish(ctx, model.StreamEventCommentCreated, []string{postData.UserID.String()}, model.StreamCommentEvent{
+				PostID:    postData.ID.String(),
+				CommentID: data.ID.String(),
+				UserID:    req.UserID,
+			})
+		}
+
+		if parentUserID != "" && parentUserID != postData.UserID.String() {
+			s.notify(ctx, model.NotificationCreateRequest{
+				UserID:    parentUserID,
+				ActorID:   req.UserID,
+				Type:      entity.NotificationTypeReply,
+				PostID:    postData.ID.String(),
+				CommentID: data.ID.String(),
+			})
+		}
+
+		// the post creator and the parent comment creator are already notified of the comment
+		for _, userID := range mentionedIDs {
+			if userID == postData.UserID.String() || userID == parentUserID {
+				continue
+			}
+
+			s.notify(ctx, model.NotificationCreateRequest{
+				UserID:    userID,
+				ActorID:   req.UserID,
+				Type:      entity.NotificationTypeMention,
+				PostID:    postData.ID.String(),
+				CommentID: data.ID.String(),
+			})
+		}
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.CreateComment: failed to begin transaction: %w", err)
+		return
 	}
 
-	err = s.repo.CreateComment(ctx, data)
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.CreateComment: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.CreateComment: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	err = repo.CreateComment(ctx, data)
 	if err != nil {
 		err = fmt.Errorf("post.service.CreateComment: failed to create comment: %w", err)
 		return
 	}
 
+	err = repo.IncrementCommentCount(ctx, data.PostID.String())
+	if err != nil {
+		err = fmt.Errorf("post.service.CreateComment: failed to increment comment count: %w", err)
+		return
+	}
+
+	if data.ParentCommentID.Valid {
+		err = repo.IncrementReplyCount(ctx, data.ParentCommentID.UUID.String())
+		if err != nil {
+			err = fmt.Errorf("post.service.CreateComment: failed to increment reply count: %w", err)
+			return
+		}
+	}
+
+	if len(mentionedIDs) > 0 {
+		err = repo.CreateMentions(ctx, data.PostID, uuid.NullUUID{UUID: data.ID, Valid: true}, mentionedIDs)
+		if err != nil {
+			err = fmt.Errorf("post.service.CreateComment: failed to create mentions: %w", err)
+			return
+		}
+	}
+
 	return
 }
 
-func (s Service) GetList(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, count int, err error) {
+// UpdateComment lets the comment creator edit the comment, the previous content is kept as a revision
+func (s Service) UpdateComment(ctx context.Context, req model.PostCommentUpdateRequest) (err error) {
 	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("post.service.CreateComment: failed to validate request: %w", err)
+		err = fmt.Errorf("post.service.UpdateComment: failed to validate request: %w", err)
 		return
 	}
 
-	data, postIDs, userIDsUnique, err := s.repo.GetList(ctx, req)
+	comment, err := sanitizer.Sanitize("comment", req.Comment)
 	if err != nil {
-		err = fmt.Errorf("post.service.GetList: failed to get list of post: %w", err)
+		err = fmt.Errorf("post.service.UpdateComment: failed to sanitize comment: %w", err)
 		return
 	}
-	commentsMap, err := s.repo.GetCommentsByPostIDsMap(ctx, postIDs, userIDsUnique)
+
+	data, err := s.repo.GetCommentByID(ctx, req.CommentID)
 	if err != nil {
-		err = fmt.Errorf("post.service.GetList: failed to get comments by post ids: %w", err)
+		err = fmt.Errorf("post.service.UpdateComment: failed to get comment: %w", err)
 		return
 	}
-	userIDs := make([]string, len(userIDsUnique))
-	i := 0
-	for k := range userIDsUnique {
-		userIDs[i] = k
-		i++
+
+	if req.UserID != data.UserID.String() {
+		err = fmt.Errorf("post.service.UpdateComment: user is not the comment creator, %w", constant.ErrAccessForbidden)
+		return
 	}
 
-	userMap, err := s.userSvc.GetListMap(ctx, model.UserGetListRequest{
-		UserIDs:       userIDs,
-		DisableOffset: true,
-		DisableOrder:  true,
+	postData, err := s.repo.GetByID(ctx, data.PostID.String())
+	if err != nil {
+		err = fmt.Errorf("post.service.UpdateComment: failed to get post: %w", err)
+		return
+	}
+
+	comment, mentionedIDs, err := s.linkMentions(ctx, postData, req.UserID, comment)
+	if err != nil {
+		err = fmt.Errorf("post.service.UpdateComment: failed to link mentions: %w", err)
+		return
+	}
+
+	var parentUserID string
+	if data.ParentCommentID.Valid {
+		parent, err := s.repo.GetCommentByID(ctx, data.ParentCommentID.UUID.String())
+		if err != nil {
+			err = fmt.Errorf("post.service.UpdateComment: failed to get parent comment: %w", err)
+			return err
+		}
+
+		parentUserID = parent.UserID.String()
+	}
+
+	revisionID, err := uuid.NewV7()
+	if err != nil {
+		err = fmt.Errorf("post.service.UpdateComment: failed to generate revision id: %w", err)
+		return
+	}
+
+	var addedMentionIDs []string
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		// the post creator and the parent comment creator are already notified of the comment
+		// and users that were already mentioned are not notified again
+		for _, userID := range addedMentionIDs {
+			if userID == postData.UserID.String() || userID == parentUserID {
+				continue
+			}
+
+			s.notify(ctx, model.NotificationCreateRequest{
+				UserID:    userID,
+				ActorID:   req.UserID,
+				Type:      entity.NotificationTypeMention,
+				PostID:    postData.ID.String(),
+				CommentID: data.ID.String(),
+			})
+		}
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.UpdateComment: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.UpdateComment: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.UpdateComment: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	err = repo.CreateCommentRevision(ctx, entity.PostCommentRevision{
+		ID:        revisionID,
+		CommentID: data.ID,
 	})
+	if err != nil {
+		err = fmt.Errorf("post.service.UpdateComment: failed to create comment revision: %w", err)
+		return
+	}
 
+	err = repo.UpdateComment(ctx, entity.PostComment{
+		ID:              data.ID,
+		Comment:         comment,
+		CommentOriginal: null.NewString(req.Comment, comment != req.Comment),
+	})
 	if err != nil {
-		err = fmt.Errorf("post.service.GetList: failed to get list of user: %w", err)
+		err = fmt.Errorf("post.service.UpdateComment: failed to update comment: %w", err)
 		return
 	}
 
-	// count, err = s.repo.GetCountList(ctx, req)
-	// if err != nil {
-	// 	err = fmt.Errorf("post.service.GetList: failed to get count list of post: %w", err)
-	// 	return
-	// }
+	addedMentionIDs, err = repo.ReplaceMentions(ctx, data.PostID, uuid.NullUUID{UUID: data.ID, Valid: true}, mentionedIDs)
+	if err != nil {
+		err = fmt.Errorf("post.service.UpdateComment: failed to replace mentions: %w", err)
+		return
+	}
 
-	res = make([]model.PostListResponse, len(data))
+	return
+}
 
-	for i, v := range data {
-		count = v.Total
-		res[i] = model.PostListResponse{
-			PostID: v.ID.String(),
-			Post: model.PostResponse{
-				PostInHtml: v.Body,
-				Tags:       v.Tags,
-				CreatedAt:  v.CreatedAt.Format(constant.TimeISO8601Format),
-			},
-			Creator: userMap[v.UserID.String()],
+// DeleteComment lets the comment creator or the post owner delete the comment together with its replies
+func (s Service) DeleteComment(ctx context.Context, req model.PostCommentDeleteRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteComment: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetCommentByID(ctx, req.CommentID)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteComment: failed to get comment: %w", err)
+		return
+	}
+
+	if req.UserID != data.UserID.String() {
+		postData, err := s.repo.GetByID(ctx, data.PostID.String())
+		if err != nil {
+			err = fmt.Errorf("post.service.DeleteComment: failed to get post: %w", err)
+			return err
 		}
 
-		comments := commentsMap[v.ID.String()]
-		res[i].Comments = make([]model.PostCommentResponse, len(comments))
-		for j, comment := range comments {
-			res[i].Comments[j] = model.PostCommentResponse{
-				Comment:   comment.Comment,
-				CreatedAt: comment.CreatedAt.Format(constant.TimeISO8601Format),
-				Creator:   userMap[comment.UserID.String()],
+		if req.UserID != postData.UserID.String() {
+			err = fmt.Errorf("post.service.DeleteComment: user is neither the comment creator nor the post owner, %w", constant.ErrAccessForbidden)
+			return err
+		}
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteComment: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.DeleteComment: failed  to rollback: %w", errRb)
+				return
 			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.DeleteComment: failed  to commit: %w", err)
+			return
 		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	count, err := repo.SoftDeleteComment(ctx, data.ID.String())
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteComment: failed to delete comment: %w", err)
+		return
+	}
+
+	err = repo.DecrementCommentCount(ctx, data.PostID.String(), count)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteComment: failed to decrement comment count: %w", err)
+		return
+	}
+
+	if data.ParentCommentID.Valid {
+		err = repo.DecrementReplyCount(ctx, data.ParentCommentID.UUID.String())
+		if err != nil {
+			err = fmt.Errorf("post.service.DeleteComment: failed to decrement reply count: %w", err)
+			return
+		}
+	}
+
+	return
+}
+
+func (s Service) GetCommentList(ctx context.Context, req model.PostCommentGetListRequest) (res []model.PostCommentResponse, nextCursor, prevCursor string, count int, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetCommentList: failed to validate request: %w", err)
+		return
+	}
+
+	postData, err := s.repo.GetByID(ctx, req.PostID)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetCommentList: failed to get post: %w", err)
+		return
+	}
+
+	canView, err := s.canView(ctx, req.UserID, postData)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetCommentList: failed to check post visibility: %w", err)
+		return
+	}
+
+	if !canView {
+		err = fmt.Errorf("post.service.GetCommentList: user cannot see the post, %w", constant.ErrAccessForbidden)
+		return
+	}
+
+	count = postData.CommentCount
+	if req.ParentCommentID != "" {
+		parent, err := s.repo.GetCommentByID(ctx, req.ParentCommentID)
+		if err != nil {
+			err = fmt.Errorf("post.service.GetCommentList: failed to get parent comment: %w", err)
+			return res, nextCursor, prevCursor, count, err
+		}
+
+		if parent.PostID != postData.ID {
+			err = fmt.Errorf("post.service.GetCommentList: parent comment belongs to another post, %w", constant.ErrCommentNotFound)
+			return res, nextCursor, prevCursor, count, err
+		}
+
+		count = parent.ReplyCount
+	}
+
+	current, err := cursor.Decode(cursor.ScopeComment, req.Cursor)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetCommentList: failed to decode cursor: %w", err)
+		return
+	}
+
+	// fetch one more comment to know whether there is another page
+	filter := req
+	filter.Limit = req.Limit + 1
+	filter.CursorID, filter.CursorPrev = current.ID, current.Prev
+	data, userIDsUnique, err := s.repo.GetCommentList(ctx, filter)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetCommentList: failed to get list of comment: %w", err)
+		return
+	}
+
+	hasMore := len(data) > req.Limit
+	if hasMore {
+		data = data[:req.Limit]
+	}
+
+	if current.Prev {
+		slices.Reverse(data)
+	}
+
+	commentIDs := make([]string, len(data))
+	for i, comment := range data {
+		commentIDs[i] = comment.ID.String()
+	}
+
+	nextCursor, prevCursor = cursor.Paginate(cursor.ScopeComment, commentIDs, current, hasMore)
+
+	userMap, err := s.getUserMap(ctx, userIDsUnique)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetCommentList: failed to get list of user: %w", err)
+		return
+	}
+
+	reactionsMap, err := s.repo.GetViewerReactionsMap(ctx, entity.ReactionTargetComment, commentIDs, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetCommentList: failed to get viewer comment reactions: %w", err)
+		return
+	}
+
+	res = make([]model.PostCommentResponse, len(data))
+	for i, comment := range data {
+		res[i] = toCommentResponse(comment, userMap, reactionsMap)
+	}
+
+	return
+}
+
+// GetList returns the feed of the user, a cursor from a previous page switches the list to keyset pagination.
+// The first page returns a next cursor as well unless the posts are ordered by search rank
+func (s Service) GetList(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, nextCursor, prevCursor string, count int, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.CreateComment: failed to validate request: %w", err)
+		return
+	}
+
+	current, err := cursor.Decode(cursor.ScopePost, req.Cursor)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetList: failed to decode cursor: %w", err)
+		return
+	}
+
+	req.SearchTags = s.tagSvc.Normalize(req.SearchTags)
+
+	// fetch one more post to know whether there is another page
+	filter := req
+	filter.CursorID, filter.CursorPrev = current.ID, current.Prev
+	orderedByID := current.ID != "" || req.Search == ""
+	if orderedByID {
+		filter.Limit = req.Limit + 1
+	}
+
+	data, postIDs, userIDsUnique, err := s.repo.GetList(ctx, filter)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetList: failed to get list of post: %w", err)
+		return
+	}
+
+	hasMore := orderedByID && len(data) > req.Limit
+	if hasMore {
+		data, postIDs = data[:req.Limit], postIDs[:req.Limit]
+	}
+
+	if current.Prev {
+		slices.Reverse(data)
+		slices.Reverse(postIDs)
+	}
+
+	if orderedByID {
+		nextCursor, prevCursor = cursor.Paginate(cursor.ScopePost, postIDs, current, hasMore)
+	}
+
+	// count, err = s.repo.GetCountList(ctx, req)
+	// if err != nil {
+	// 	err = fmt.Errorf("post.service.GetList: failed to get count list of post: %w", err)
+	// 	return
+	// }
+
+	res, err = s.toListResponse(ctx, req.UserID, data, postIDs, userIDsUnique)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetList: failed to build list response: %w", err)
+		return
+	}
+
+	if len(data) > 0 {
+		count = data[0].Total
+	}
+
+	return
+}
+
+// GetListByUser returns posts of the author the viewer can see,
+// the author sees every post, friends see public and friends posts and everyone else sees public posts
+func (s Service) GetListByUser(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, nextCursor, prevCursor string, count int, err error) {
+	profile, err := s.userSvc.GetProfile(ctx, model.UserProfileRequest{
+		UserID:   req.AuthorID,
+		ViewerID: req.UserID,
+	})
+	if err != nil {
+		err = fmt.Errorf("post.service.GetListByUser: failed to get author: %w", err)
+		return
+	}
+
+	switch profile.Relationship {
+	case entity.RelationshipSelf:
+		req.Visibilities = nil
+	case entity.RelationshipFriend:
+		req.Visibilities = []string{entity.PostVisibilityPublic, entity.PostVisibilityFriends}
+	default:
+		req.Visibilities = []string{entity.PostVisibilityPublic}
+	}
+
+	return s.GetList(ctx, req)
+}
+
+// Discover returns public posts of every user
+func (s Service) Discover(ctx context.Context, req model.PostGetListRequest) (res []model.PostListResponse, nextCursor, prevCursor string, count int, err error) {
+	req.Discover = true
+	req.Visibilities = []string{entity.PostVisibilityPublic}
+
+	return s.GetList(ctx, req)
+}
+
+// canView reports whether the viewer is in the audience of the post,
+// the owner can see every post and friends of the owner can see friends posts.
+// It fails with ErrUserBlocked when one of the viewer and the owner blocked the other
+func (s Service) canView(ctx context.Context, viewerID string, data entity.Post) (ok bool, err error) {
+	if viewerID == data.UserID.String() {
+		return true, nil
+	}
+
+	err = s.checkNotBlocked(ctx, viewerID, data.UserID.String())
+	if err != nil {
+		err = fmt.Errorf("post.service.canView: %w", err)
+		return
+	}
+
+	switch data.Visibility {
+	case entity.PostVisibilityPublic:
+		return true, nil
+	case entity.PostVisibilityOnlyMe:
+		return false, nil
+	}
+
+	ok, err = s.userSvc.IsFriend(ctx, viewerID, data.UserID.String())
+	if err != nil {
+		err = fmt.Errorf("post.service.canView: failed to check is friend: %w", err)
+		return
+	}
+
+	return
+}
+
+// toListResponse loads the comments, creators and viewer reactions of the given posts and maps them into responses
+func (s Service) toListResponse(ctx context.Context, viewerID string, data []entity.Post, postIDs []string, userIDsUnique map[string]struct{}) (res []model.PostListResponse, err error) {
+	commentsMap, err := s.repo.GetCommentsByPostIDsMap(ctx, postIDs, config.Get().Post.FeedCommentLimit, userIDsUnique)
+	if err != nil {
+		err = fmt.Errorf("post.service.toListResponse: failed to get comments by post ids: %w", err)
+		return
+	}
+
+	commentIDs := []string{}
+	for _, comments := range commentsMap {
+		for _, comment := range comments {
+			commentIDs = append(commentIDs, comment.ID.String())
+		}
+	}
+
+	postReactionsMap, err := s.repo.GetViewerReactionsMap(ctx, entity.ReactionTargetPost, postIDs, viewerID)
+	if err != nil {
+		err = fmt.Errorf("post.service.toListResponse: failed to get viewer post reactions: %w", err)
+		return
+	}
+
+	commentReactionsMap, err := s.repo.GetViewerReactionsMap(ctx, entity.ReactionTargetComment, commentIDs, viewerID)
+	if err != nil {
+		err = fmt.Errorf("post.service.toListResponse: failed to get viewer comment reactions: %w", err)
+		return
+	}
+	userMap, err := s.getUserMap(ctx, userIDsUnique)
+	if err != nil {
+		err = fmt.Errorf("post.service.toListResponse: failed to get list of user: %w", err)
+		return
+	}
+
+	res = make([]model.PostListResponse, len(data))
+
+	for i, v := range data {
+		res[i] = model.PostListResponse{
+			PostID: v.ID.String(),
+			Post: model.PostResponse{
+				PostInHtml: v.Body,
+				Tags:       v.Tags,
+				Visibility: v.Visibility,
+				CreatedAt:  v.CreatedAt.Format(constant.TimeISO8601Format),
+			},
+			Creator:        userMap[v.UserID.String()],
+			CommentCount:   v.CommentCount,
+			Highlight:      v.Highlight.String,
+			Reactions:      toReactionCountResponse(v.Reactions),
+			ViewerReaction: viewerReaction(postReactionsMap, v.ID.String()),
+		}
+
+		comments := commentsMap[v.ID.String()]
+		res[i].Comments = make([]model.PostCommentResponse, len(comments))
+		for j, comment := range comments {
+			res[i].Comments[j] = toCommentResponse(comment, userMap, commentReactionsMap)
+		}
+	}
+
+	return
+}
+
+func (s Service) getUserMap(ctx context.Context, userIDsUnique map[string]struct{}) (userMap map[string]model.UserResponse, err error) {
+	userIDs := make([]string, 0, len(userIDsUnique))
+	for k := range userIDsUnique {
+		userIDs = append(userIDs, k)
+	}
+
+	return s.userSvc.GetListMap(ctx, model.UserGetListRequest{
+		UserIDs:       userIDs,
+		DisableOffset: true,
+		DisableOrder:  true,
+	})
+}
+
+func toCommentResponse(comment entity.PostComment, userMap map[string]model.UserResponse, reactionsMap map[string]string) model.PostCommentResponse {
+	res := model.PostCommentResponse{
+		CommentID:      comment.ID.String(),
+		Comment:        comment.Comment,
+		ReplyCount:     comment.ReplyCount,
+		CreatedAt:      comment.CreatedAt.Format(constant.TimeISO8601Format),
+		Creator:        userMap[comment.UserID.String()],
+		Reactions:      toReactionCountResponse(comment.Reactions),
+		ViewerReaction: viewerReaction(reactionsMap, comment.ID.String()),
+	}
+
+	if comment.ParentCommentID.Valid {
+		parentCommentID := comment.ParentCommentID.UUID.String()
+		res.ParentCommentID = &parentCommentID
+	}
+
+	if comment.EditedAt.Valid {
+		editedAt := comment.EditedAt.Time.Format(constant.TimeISO8601Format)
+		res.Edited = true
+		res.EditedAt = &editedAt
+	}
+
+	return res
+}
+
+func toReactionCountResponse(data entity.ReactionCount) model.ReactionCountResponse {
+	return model.ReactionCountResponse{
+		Like:  data.Like,
+		Love:  data.Love,
+		Haha:  data.Haha,
+		Wow:   data.Wow,
+		Sad:   data.Sad,
+		Angry: data.Angry,
+	}
+}
+
+func viewerReaction(reactionsMap map[string]string, targetID string) *string {
+	reactionType, ok := reactionsMap[targetID]
+	if !ok {
+		return nil
+	}
+
+	return &reactionType
+}
+
+func (s Service) GetByID(ctx context.Context, req model.PostGetByIDRequest) (res model.PostListResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetByID: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, req.PostID)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetByID: failed to get post: %w", err)
+		return
+	}
+
+	canView, err := s.canView(ctx, req.UserID, data)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetByID: failed to check post visibility: %w", err)
+		return
+	}
+
+	if !canView {
+		err = fmt.Errorf("post.service.GetByID: user cannot see the post, %w", constant.ErrAccessForbidden)
+		return
+	}
+
+	userIDsUnique := map[string]struct{}{data.UserID.String(): {}}
+	resList, err := s.toListResponse(ctx, req.UserID, []entity.Post{data}, []string{data.ID.String()}, userIDsUnique)
+	if err != nil {
+		err = fmt.Errorf("post.service.GetByID: failed to build response: %w", err)
+		return
+	}
+
+	return resList[0], nil
+}
+
+func (s Service) Update(ctx context.Context, req model.PostUpdateRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.Update: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, req.PostID)
+	if err != nil {
+		err = fmt.Errorf("post.service.Update: failed to get post: %w", err)
+		return
+	}
+
+	if req.UserID != data.UserID.String() {
+		err = fmt.Errorf("post.service.Update: user is not the post owner, %w", constant.ErrAccessForbidden)
+		return
+	}
+
+	updateData := entity.Post{
+		ID:         data.ID,
+		Tags:       s.tagSvc.Normalize(req.Tags),
+		Visibility: req.Visibility,
+	}
+
+	visibility := data.Visibility
+	if updateData.Visibility != "" {
+		visibility = updateData.Visibility
+	}
+
+	var mentionedIDs []string
+	if req.PostInHtml != "" {
+		updateData.Body, err = sanitizer.Sanitize("postInHtml", req.PostInHtml)
+		if err != nil {
+			err = fmt.Errorf("post.service.Update: failed to sanitize post: %w", err)
+			return
+		}
+
+		// the mentions are resolved with the visibility the post has after the update
+		updateData.Body, mentionedIDs, err = s.linkMentions(ctx, entity.Post{UserID: data.UserID, Visibility: visibility}, req.UserID, updateData.Body)
+		if err != nil {
+			err = fmt.Errorf("post.service.Update: failed to link mentions: %w", err)
+			return
+		}
+		updateData.BodyOriginal = null.NewString(req.PostInHtml, updateData.Body != req.PostInHtml)
+	}
+
+	tags := data.Tags
+	if updateData.Tags != nil {
+		tags = updateData.Tags
+	}
+
+	newTags, removedTags := diffTags(data.Visibility, data.Tags, visibility, tags)
+
+	var addedMentionIDs []string
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		s.registerTags(ctx, visibility, newTags)
+
+		// users that were already mentioned are not notified again
+		for _, userID := range addedMentionIDs {
+			s.notify(ctx, model.NotificationCreateRequest{
+				UserID:  userID,
+				ActorID: req.UserID,
+				Type:    entity.NotificationTypeMention,
+				PostID:  data.ID.String(),
+			})
+		}
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.Update: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.Update: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.Update: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	err = repo.Update(ctx, updateData)
+	if err != nil {
+		err = fmt.Errorf("post.service.Update: failed to update post: %w", err)
+		return
+	}
+
+	if len(removedTags) > 0 {
+		err = repo.DecrementTags(ctx, removedTags)
+		if err != nil {
+			err = fmt.Errorf("post.service.Update: failed to decrement tags: %w", err)
+			return
+		}
+	}
+
+	if updateData.Body != "" {
+		addedMentionIDs, err = repo.ReplaceMentions(ctx, data.ID, uuid.NullUUID{}, mentionedIDs)
+		if err != nil {
+			err = fmt.Errorf("post.service.Update: failed to replace mentions: %w", err)
+			return
+		}
+	}
+
+	return
+}
+
+func (s Service) Delete(ctx context.Context, req model.PostDeleteRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.Delete: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, req.PostID)
+	if err != nil {
+		err = fmt.Errorf("post.service.Delete: failed to get post: %w", err)
+		return
+	}
+
+	if req.UserID != data.UserID.String() {
+		err = fmt.Errorf("post.service.Delete: user is not the post owner, %w", constant.ErrAccessForbidden)
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.Delete: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.Delete: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.Delete: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	err = repo.SoftDelete(ctx, data.ID.String())
+	if err != nil {
+		err = fmt.Errorf("post.service.Delete: failed to delete post: %w", err)
+		return
+	}
+
+	// a deleted post no longer counts for the tags of the autocomplete
+	_, removedTags := diffTags(data.Visibility, data.Tags, "", nil)
+	if len(removedTags) > 0 {
+		err = repo.DecrementTags(ctx, removedTags)
+		if err != nil {
+			err = fmt.Errorf("post.service.Delete: failed to decrement tags: %w", err)
+			return
+		}
+	}
+
+	return
+}
+
+func (s Service) React(ctx context.Context, req model.PostReactionRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.React: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, req.PostID)
+	if err != nil {
+		err = fmt.Errorf("post.service.React: failed to get post: %w", err)
+		return
+	}
+
+	canView, err := s.canView(ctx, req.UserID, data)
+	if err != nil {
+		err = fmt.Errorf("post.service.React: failed to check post visibility: %w", err)
+		return
+	}
+
+	if !canView {
+		err = fmt.Errorf("post.service.React: user cannot see the post, %w", constant.ErrAccessForbidden)
+		return
+	}
+
+	err = s.react(ctx, entity.ReactionTargetPost, data.ID.String(), req.UserID, req.Type)
+	if err != nil {
+		err = fmt.Errorf("post.service.React: failed to react to post: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) DeleteReaction(ctx context.Context, req model.PostReactionDeleteRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteReaction: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.unreact(ctx, entity.ReactionTargetPost, req.PostID, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteReaction: failed to delete post reaction: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) ReactComment(ctx context.Context, req model.PostCommentReactionRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.ReactComment: failed to validate request: %w", err)
+		return
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, req.CommentID)
+	if err != nil {
+		err = fmt.Errorf("post.service.ReactComment: failed to get comment: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, comment.PostID.String())
+	if err != nil {
+		err = fmt.Errorf("post.service.ReactComment: failed to get post: %w", err)
+		return
+	}
+
+	canView, err := s.canView(ctx, req.UserID, data)
+	if err != nil {
+		err = fmt.Errorf("post.service.ReactComment: failed to check post visibility: %w", err)
+		return
+	}
+
+	if !canView {
+		err = fmt.Errorf("post.service.ReactComment: user cannot see the post, %w", constant.ErrAccessForbidden)
+		return
+	}
+
+	err = s.react(ctx, entity.ReactionTargetComment, comment.ID.String(), req.UserID, req.Type)
+	if err != nil {
+		err = fmt.Errorf("post.service.ReactComment: failed to react to comment: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) DeleteCommentReaction(ctx context.Context, req model.PostCommentReactionDeleteRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteCommentReaction: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.unreact(ctx, entity.ReactionTargetComment, req.CommentID, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("post.service.DeleteCommentReaction: failed to delete comment reaction: %w", err)
+		return
+	}
+
+	return
+}
+
+// react creates or replaces the reaction of user on the target and keeps the denormalized counts in sync
+func (s Service) react(ctx context.Context, target, targetID, userID, reactionType string) (err error) {
+	targetIdUUID, err := uuid.Parse(targetID)
+	if err != nil {
+		err = fmt.Errorf("post.service.react: failed to parse target id: %w", err)
+		return
+	}
+
+	userIdUUID, err := uuid.Parse(userID)
+	if err != nil {
+		err = fmt.Errorf("post.service.react: failed to parse user id: %w", err)
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.react: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.react: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.react: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+	data := entity.Reaction{
+		TargetID: targetIdUUID,
+		UserID:   userIdUUID,
+		Type:     reactionType,
+	}
+
+	oldType, err := repo.GetReactionType(ctx, target, targetID, userID)
+	isNew := errors.Is(err, constant.ErrReactionNotFound)
+	if err != nil && !isNew {
+		err = fmt.Errorf("post.service.react: failed to get reaction: %w", err)
+		return
+	}
+
+	if isNew {
+		err = repo.CreateReaction(ctx, target, data)
+		if err != nil {
+			err = fmt.Errorf("post.service.react: failed to create reaction: %w", err)
+			return
+		}
+	} else {
+		if oldType == reactionType {
+			return
+		}
+
+		err = repo.UpdateReaction(ctx, target, data)
+		if err != nil {
+			err = fmt.Errorf("post.service.react: failed to update reaction: %w", err)
+			return
+		}
+
+		err = repo.DecrementReactionCount(ctx, target, targetID, oldType)
+		if err != nil {
+			err = fmt.Errorf("post.service.react: failed to decrement reaction count: %w", err)
+			return
+		}
+	}
+
+	err = repo.IncrementReactionCount(ctx, target, targetID, reactionType)
+	if err != nil {
+		err = fmt.Errorf("post.service.react: failed to increment reaction count: %w", err)
+		return
+	}
+
+	return
+}
+
+// unreact deletes the reaction of user on the target and keeps the denormalized counts in sync
+func (s Service) unreact(ctx context.Context, target, targetID, userID string) (err error) {
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("post.service.unreact: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("post.service.unreact: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("post.service.unreact: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	reactionType, err := repo.DeleteReaction(ctx, target, targetID, userID)
+	if err != nil {
+		err = fmt.Errorf("post.service.unreact: failed to delete reaction: %w", err)
+		return
+	}
+
+	err = repo.DecrementReactionCount(ctx, target, targetID, reactionType)
+	if err != nil {
+		err = fmt.Errorf("post.service.unreact: failed to decrement reaction count: %w", err)
+		return
 	}
 
 	return
@@ -0,0 +1,181 @@
+package postsvc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/post"
+	"github.com/arfan21/project-sprint-social-media-api/internal/user"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/google/uuid"
+)
+
+const (
+	ownerID   = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e6f"
+	viewerID  = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e70"
+	postID    = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e71"
+	commentID = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e72"
+)
+
+// relationUserService answers the relationship of the viewer with the owner,
+// any other call panics on the nil embedded service
+type relationUserService struct {
+	user.Service
+	blocked bool
+	friend  bool
+}
+
+func (s relationUserService) IsBlocked(ctx context.Context, userIdA, userIdB string) (bool, error) {
+	return s.blocked, nil
+}
+
+func (s relationUserService) IsFriend(ctx context.Context, userIdAdder, userIdAdded string) (bool, error) {
+	return s.friend, nil
+}
+
+func (s relationUserService) GetProfile(ctx context.Context, req model.UserProfileRequest) (res model.UserProfileResponse, err error) {
+	if s.blocked {
+		return res, constant.ErrUserBlocked
+	}
+
+	res.Relationship = entity.RelationshipNone
+	if s.friend {
+		res.Relationship = entity.RelationshipFriend
+	}
+
+	return
+}
+
+// postRepository returns the post and a comment on it, any other call panics on the nil embedded repository
+type postRepository struct {
+	post.Repository
+	post entity.Post
+}
+
+func (r postRepository) GetByID(ctx context.Context, id string) (entity.Post, error) {
+	return r.post, nil
+}
+
+func (r postRepository) GetCommentByID(ctx context.Context, id string) (entity.PostComment, error) {
+	return entity.PostComment{ID: uuid.MustParse(commentID), PostID: r.post.ID, UserID: r.post.UserID}, nil
+}
+
+func TestDiffTags(t *testing.T) {
+	const (
+		public  = entity.PostVisibilityPublic
+		friends = entity.PostVisibilityFriends
+		onlyMe  = entity.PostVisibilityOnlyMe
+	)
+
+	tests := []struct {
+		name          string
+		oldVisibility string
+		oldTags       []string
+		visibility    string
+		tags          []string
+		wantAdded     []string
+		wantRemoved   []string
+	}{
+		{name: "public post unchanged", oldVisibility: public, oldTags: []string{"go"}, visibility: public, tags: []string{"go"}, wantAdded: []string{}, wantRemoved: []string{}},
+		{name: "tags replaced on a public post", oldVisibility: public, oldTags: []string{"go", "sql"}, visibility: public, tags: []string{"go", "rust"}, wantAdded: []string{"rust"}, wantRemoved: []string{"sql"}},
+		{name: "public post made friends only", oldVisibility: public, oldTags: []string{"go", "sql"}, visibility: friends, tags: []string{"go", "sql"}, wantAdded: []string{}, wantRemoved: []string{"go", "sql"}},
+		{name: "public post made private with new tags", oldVisibility: public, oldTags: []string{"go"}, visibility: onlyMe, tags: []string{"rust"}, wantAdded: []string{}, wantRemoved: []string{"go"}},
+		{name: "friends only post made public", oldVisibility: friends, oldTags: []string{"go"}, visibility: public, tags: []string{"go", "rust"}, wantAdded: []string{"go", "rust"}, wantRemoved: []string{}},
+		{name: "tags replaced on a private post", oldVisibility: onlyMe, oldTags: []string{"go"}, visibility: onlyMe, tags: []string{"rust"}, wantAdded: []string{}, wantRemoved: []string{}},
+		{name: "public post deleted", oldVisibility: public, oldTags: []string{"go", "sql"}, wantAdded: []string{}, wantRemoved: []string{"go", "sql"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := diffTags(tt.oldVisibility, tt.oldTags, tt.visibility, tt.tags)
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("diffTags() added = %v, want %v", added, tt.wantAdded)
+			}
+
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("diffTags() removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestCanView(t *testing.T) {
+	tests := []struct {
+		name       string
+		viewerID   string
+		visibility string
+		users      relationUserService
+		want       bool
+		wantErr    error
+	}{
+		{name: "owner sees an only me post", viewerID: ownerID, visibility: entity.PostVisibilityOnlyMe, want: true},
+		{name: "stranger sees a public post", viewerID: viewerID, visibility: entity.PostVisibilityPublic, want: true},
+		{name: "stranger does not see a friends post", viewerID: viewerID, visibility: entity.PostVisibilityFriends},
+		{name: "friend sees a friends post", viewerID: viewerID, visibility: entity.PostVisibilityFriends, users: relationUserService{friend: true}, want: true},
+		{name: "friend does not see an only me post", viewerID: viewerID, visibility: entity.PostVisibilityOnlyMe, users: relationUserService{friend: true}},
+		{name: "blocked user does not see a public post", viewerID: viewerID, visibility: entity.PostVisibilityPublic, users: relationUserService{blocked: true}, wantErr: constant.ErrUserBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{userSvc: tt.users}
+
+			got, err := s.canView(context.Background(), tt.viewerID, entity.Post{UserID: uuid.MustParse(ownerID), Visibility: tt.visibility})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("canView() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("canView() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockedUserCannotReachThePosts(t *testing.T) {
+	ctx := context.Background()
+	s := Service{
+		repo: postRepository{post: entity.Post{
+			ID:         uuid.MustParse(postID),
+			UserID:     uuid.MustParse(ownerID),
+			Visibility: entity.PostVisibilityPublic,
+		}},
+		userSvc: relationUserService{blocked: true},
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "get by id", call: func() error {
+			_, err := s.GetByID(ctx, model.PostGetByIDRequest{PostID: postID, UserID: viewerID})
+			return err
+		}},
+		{name: "react", call: func() error {
+			return s.React(ctx, model.PostReactionRequest{PostID: postID, UserID: viewerID, Type: entity.ReactionTypeLike})
+		}},
+		{name: "react to a comment", call: func() error {
+			return s.ReactComment(ctx, model.PostCommentReactionRequest{CommentID: commentID, UserID: viewerID, Type: entity.ReactionTypeLike})
+		}},
+		{name: "comment", call: func() error {
+			return s.CreateComment(ctx, model.PostCommentRequest{PostID: postID, Comment: "nice post", UserID: viewerID})
+		}},
+		{name: "list the posts of the profile", call: func() error {
+			_, _, _, _, err := s.GetListByUser(ctx, model.PostGetListRequest{UserID: viewerID, AuthorID: ownerID})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, constant.ErrUserBlocked) {
+				t.Errorf("error = %v, want %v", err, constant.ErrUserBlocked)
+			}
+		})
+	}
+}
@@ -1,15 +1,26 @@
 package server
 
 import (
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
 	fileuploaderctrl "github.com/arfan21/project-sprint-social-media-api/internal/fileuploader/controller"
 	fileuploadersvc "github.com/arfan21/project-sprint-social-media-api/internal/fileuploader/service"
+	notificationctrl "github.com/arfan21/project-sprint-social-media-api/internal/notification/controller"
+	notificationrepo "github.com/arfan21/project-sprint-social-media-api/internal/notification/repository"
+	notificationsvc "github.com/arfan21/project-sprint-social-media-api/internal/notification/service"
 	postctrl "github.com/arfan21/project-sprint-social-media-api/internal/post/controller"
 	postrepo "github.com/arfan21/project-sprint-social-media-api/internal/post/repository"
 	postsvc "github.com/arfan21/project-sprint-social-media-api/internal/post/service"
+	streamctrl "github.com/arfan21/project-sprint-social-media-api/internal/stream/controller"
+	tagctrl "github.com/arfan21/project-sprint-social-media-api/internal/tag/controller"
+	tagrepo "github.com/arfan21/project-sprint-social-media-api/internal/tag/repository"
+	tagsvc "github.com/arfan21/project-sprint-social-media-api/internal/tag/service"
 	userctrl "github.com/arfan21/project-sprint-social-media-api/internal/user/controller"
 	userrepo "github.com/arfan21/project-sprint-social-media-api/internal/user/repository"
 	usersvc "github.com/arfan21/project-sprint-social-media-api/internal/user/service"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/middleware"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/storage"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -18,48 +29,141 @@ func (s *Server) Routes() {
 	api := s.app.Group("")
 	api.Get("/health-check", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
 
+	notificationRepo := notificationrepo.New(s.db)
+	notificationSvc := notificationsvc.New(notificationRepo)
+	notificationCtrl := notificationctrl.New(notificationSvc)
+
 	userRepo := userrepo.New(s.db)
-	userSvc := usersvc.New(userRepo)
+	userSvc := usersvc.New(userRepo, notificationSvc, s.pubsub, s.notifier, s.loginGuard, s.jwtKeys, s.storage)
 	userCtrl := userctrl.New(userSvc)
 
-	fileUploaderSvc := fileuploadersvc.New()
+	deletionInterval := time.Duration(config.Get().AccountDeletion.Interval) * time.Second
+	go userSvc.RunDeletionWorker(s.ctx, deletionInterval)
+
+	s.jwtAuth = middleware.JWTAuth(s.jwtKeys, userSvc)
+
+	fileUploaderSvc := fileuploadersvc.New(s.storage)
 	fileUploaderCtrl := fileuploaderctrl.New(fileUploaderSvc)
 
+	tagRepo := tagrepo.New(s.db)
+	tagSvc := tagsvc.New(tagRepo)
+	tagCtrl := tagctrl.New(tagSvc)
+
+	refreshInterval := time.Duration(config.Get().Tag.TrendingRefreshInterval) * time.Second
+	go tagSvc.RunTrendingRefresher(s.ctx, refreshInterval)
+
 	postRepo := postrepo.New(s.db)
-	postSvc := postsvc.New(postRepo, userSvc)
+	postSvc := postsvc.New(postRepo, userSvc, notificationSvc, tagSvc, s.pubsub)
 	postCtrl := postctrl.New(postSvc)
 
+	heartbeat := time.Duration(config.Get().Stream.HeartbeatInterval) * time.Second
+	streamCtrl := streamctrl.New(s.ctx, s.pubsub, heartbeat)
+
 	s.RoutesCustomer(api, userCtrl)
 	s.RoutesFileUploader(api, fileUploaderCtrl)
 	s.RoutesPost(api, postCtrl)
+	s.RoutesNotification(api, notificationCtrl)
+	s.RoutesTag(api, tagCtrl)
+	s.RoutesStream(api, streamCtrl)
 }
 
 func (s Server) RoutesCustomer(route fiber.Router, ctrl *userctrl.ControllerHTTP) {
+	route.Get("/.well-known/jwks.json", ctrl.JWKS)
+
 	v1 := route.Group("/v1")
 	usersV1 := v1.Group("/user")
 	usersV1.Post("/register", ctrl.Register)
 	usersV1.Post("/login", ctrl.Login)
-	usersV1.Patch("", middleware.JWTAuth, ctrl.UpdateProfile)
+	usersV1.Post("/login/2fa", ctrl.LoginTwoFactor)
+	usersV1.Post("/refresh", ctrl.RefreshToken)
+	usersV1.Post("/logout", s.jwtAuth, ctrl.Logout)
+	usersV1.Post("/logout/all", s.jwtAuth, ctrl.LogoutAll)
+	usersV1.Get("/session", s.jwtAuth, ctrl.GetSessionList)
+	usersV1.Delete("/session/:id", s.jwtAuth, ctrl.RevokeSession)
+	usersV1.Patch("", s.jwtAuth, ctrl.UpdateProfile)
+	usersV1.Delete("", s.jwtAuth, ctrl.DeleteAccount)
+	usersV1.Post("/password", s.jwtAuth, ctrl.UpdatePassword)
+	usersV1.Post("/password/forgot", ctrl.ForgotPassword)
+	usersV1.Post("/password/reset", ctrl.ResetPassword)
+	usersV1.Post("/verify", s.jwtAuth, ctrl.VerifyCredential)
+	usersV1.Post("/verify/resend", s.jwtAuth, ctrl.ResendVerification)
+	usersV1.Post("/block", s.jwtAuth, ctrl.Block)
+	usersV1.Delete("/block", s.jwtAuth, ctrl.Unblock)
+	usersV1.Get("/block", s.jwtAuth, ctrl.GetBlockList)
+	usersV1.Post("/mute", s.jwtAuth, ctrl.Mute)
+	usersV1.Delete("/mute", s.jwtAuth, ctrl.Unmute)
+	usersV1.Get("/mute", s.jwtAuth, ctrl.GetMuteList)
+	usersV1.Get("/:id", s.jwtAuth, ctrl.GetProfile)
 
-	friend := v1.Group("/friend", middleware.JWTAuth)
+	friend := v1.Group("/friend", s.jwtAuth)
 	friend.Post("", ctrl.AddFriend)
 	friend.Delete("", ctrl.DeleteFriend)
 	friend.Get("", ctrl.GetList)
+	friend.Get("/request/incoming", ctrl.GetListIncomingFriendRequest)
+	friend.Get("/request/outgoing", ctrl.GetListOutgoingFriendRequest)
+	friend.Post("/request/:id/accept", ctrl.AcceptFriendRequest)
+	friend.Post("/request/:id/reject", ctrl.RejectFriendRequest)
+	friend.Delete("/request/:id", ctrl.CancelFriendRequest)
 
-	linkV1 := usersV1.Group("/link", middleware.JWTAuth)
+	twoFactorV1 := usersV1.Group("/2fa", s.jwtAuth)
+	twoFactorV1.Post("/enroll", ctrl.EnrollTwoFactor)
+	twoFactorV1.Post("/enable", ctrl.EnableTwoFactor)
+	twoFactorV1.Post("/disable", ctrl.DisableTwoFactor)
+
+	linkV1 := usersV1.Group("/link", s.jwtAuth)
 	linkV1.Post("/phone", ctrl.UpdatePhone)
 	linkV1.Post("/", ctrl.UpdateEmail)
 }
 func (s Server) RoutesFileUploader(route fiber.Router, ctrl *fileuploaderctrl.ControllerHTTP) {
 	v1 := route.Group("/v1")
-	fileUploaderV1 := v1.Group("/image", middleware.JWTAuth)
+	fileUploaderV1 := v1.Group("/image", s.jwtAuth)
 	fileUploaderV1.Post("", ctrl.UploadImage)
+
+	// files of the local driver are served by the api itself
+	if local, ok := s.storage.(*storage.Local); ok {
+		route.Static(storage.LocalURLPrefix, local.Dir())
+	}
 }
 
 func (s Server) RoutesPost(route fiber.Router, ctrl *postctrl.ControllerHTTP) {
 	v1 := route.Group("/v1")
-	postV1 := v1.Group("/post", middleware.JWTAuth)
+	postV1 := v1.Group("/post", s.jwtAuth)
 	postV1.Post("", ctrl.Create)
 	postV1.Post("/comment", ctrl.CreateComment)
+	postV1.Patch("/comment/:commentId", ctrl.UpdateComment)
+	postV1.Delete("/comment/:commentId", ctrl.DeleteComment)
+	postV1.Post("/comment/:commentId/reaction", ctrl.ReactComment)
+	postV1.Delete("/comment/:commentId/reaction", ctrl.DeleteCommentReaction)
 	postV1.Get("", ctrl.GetList)
+	postV1.Get("/discover", ctrl.Discover)
+	postV1.Get("/:id", ctrl.GetByID)
+	postV1.Get("/:id/comments", ctrl.GetCommentList)
+	postV1.Patch("/:id", ctrl.Update)
+	postV1.Delete("/:id", ctrl.Delete)
+	postV1.Post("/:id/reaction", ctrl.React)
+	postV1.Delete("/:id/reaction", ctrl.DeleteReaction)
+
+	v1.Get("/user/:id/post", s.jwtAuth, ctrl.GetListByUser)
+}
+
+func (s Server) RoutesNotification(route fiber.Router, ctrl *notificationctrl.ControllerHTTP) {
+	v1 := route.Group("/v1")
+	notificationV1 := v1.Group("/notification", s.jwtAuth)
+	notificationV1.Get("", ctrl.GetList)
+	notificationV1.Post("/read-all", ctrl.MarkAllRead)
+	notificationV1.Get("/preference", ctrl.GetPreference)
+	notificationV1.Put("/preference", ctrl.UpdatePreference)
+	notificationV1.Post("/:id/read", ctrl.MarkRead)
+}
+
+func (s Server) RoutesTag(route fiber.Router, ctrl *tagctrl.ControllerHTTP) {
+	v1 := route.Group("/v1")
+	tagV1 := v1.Group("/tag", s.jwtAuth)
+	tagV1.Get("", ctrl.GetList)
+	tagV1.Get("/trending", ctrl.GetTrending)
+}
+
+func (s Server) RoutesStream(route fiber.Router, ctrl *streamctrl.ControllerHTTP) {
+	v1 := route.Group("/v1")
+	v1.Get(StreamPath, s.jwtAuth, ctrl.Stream)
 }
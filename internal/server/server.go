@@ -11,9 +11,14 @@ import (
 	_ "github.com/arfan21/project-sprint-social-media-api/docs"
 	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/exception"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/jwtkey"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/loginguard"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/middleware"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/notifier"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/pkgutil"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/pubsub"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/storage"
 	"github.com/gofiber/contrib/fiberzerolog"
 	"github.com/gofiber/contrib/otelfiber"
 	"github.com/gofiber/fiber/v2"
@@ -28,18 +33,37 @@ import (
 
 const (
 	ctxTimeout = 5
+
+	// StreamPath is the long lived event stream, it is excluded from the request timeout
+	StreamPath = "/stream"
 )
 
 type Server struct {
-	app *fiber.App
-	db  dbpostgres.Queryer
+	app        *fiber.App
+	db         dbpostgres.Queryer
+	storage    storage.Storage
+	pubsub     pubsub.PubSub
+	notifier   notifier.Notifier
+	loginGuard *loginguard.Guard
+	jwtKeys    *jwtkey.KeySet
+	jwtAuth    fiber.Handler
+
+	// ctx lives as long as the server, it is cancelled on shutdown
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func New(
 	db dbpostgres.Queryer,
+	storage storage.Storage,
+	pubsub pubsub.PubSub,
+	notifier notifier.Notifier,
+	loginGuard *loginguard.Guard,
+	jwtKeys *jwtkey.KeySet,
 ) *Server {
 	app := fiber.New(fiber.Config{
 		ErrorHandler: exception.FiberErrorHandler,
+		ProxyHeader:  config.Get().ProxyHeader,
 	})
 
 	if config.Get().Otel.EnableMetrics {
@@ -47,7 +71,7 @@ func New(
 	}
 
 	timeout := time.Duration(config.Get().Service.Timeout) * time.Second
-	app.Use(middleware.Timeout(timeout))
+	app.Use(middleware.Timeout(timeout, middleware.WithExcludePaths("/v1"+StreamPath)))
 
 	app.Use(cors.New())
 	if config.Get().Otel.EnableMetrics || config.Get().Otel.EnableTracing {
@@ -70,9 +94,18 @@ func New(
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Server{
-		app: app,
-		db:  db,
+		app:        app,
+		db:         db,
+		storage:    storage,
+		pubsub:     pubsub,
+		notifier:   notifier,
+		loginGuard: loginGuard,
+		jwtKeys:    jwtKeys,
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 }
 
@@ -107,5 +140,8 @@ func (s *Server) Run() error {
 	defer shutdown()
 
 	logger.Log(ctx).Info().Msg("shutting down server")
+
+	// close the open streams first, shutdown waits for every connection to finish
+	s.cancel()
 	return s.app.Shutdown()
 }
@@ -0,0 +1,86 @@
+package streamctrl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/pubsub"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ControllerHTTP struct {
+	// ctx is cancelled when the server shuts down so the open streams are closed
+	ctx       context.Context
+	pubsub    pubsub.PubSub
+	heartbeat time.Duration
+}
+
+func New(ctx context.Context, pubsub pubsub.PubSub, heartbeat time.Duration) *ControllerHTTP {
+	return &ControllerHTTP{ctx: ctx, pubsub: pubsub, heartbeat: heartbeat}
+}
+
+// @Summary Stream events
+// @Description Server sent events stream of new friend posts, comments on own posts and friendship changes,
+// @Description each event has the event type as name and a json payload as data
+// @Tags stream
+// @Produce text/event-stream
+// @Param Authorization header string true "With the bearer started"
+// @Success 200 {string} string "text/event-stream"
+// @Router /v1/stream [get]
+func (ctrl ControllerHTTP) Stream(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	messages, unsubscribe := ctrl.pubsub.Subscribe(claims.UserID)
+	logCtx := c.UserContext()
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		ticker := time.NewTicker(ctrl.heartbeat)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, ": connected\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case <-ctrl.ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, msg.Data)
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			// a flush error means the client is gone
+			if err := w.Flush(); err != nil {
+				logger.Log(logCtx).Debug().Err(err).Str("user_id", claims.UserID).Msg("stream closed")
+				return
+			}
+		}
+	})
+
+	return nil
+}
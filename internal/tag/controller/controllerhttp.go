@@ -0,0 +1,83 @@
+package tagctrl
+
+import (
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/tag"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/exception"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/pkgutil"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ControllerHTTP struct {
+	svc tag.Service
+}
+
+func New(svc tag.Service) *ControllerHTTP {
+	return &ControllerHTTP{svc: svc}
+}
+
+// @Summary Autocomplete tag
+// @Description Get list tag starting with the prefix, the most used in public posts first. The prefix is normalized like the tags of a post
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param prefix query string true "Tag prefix"
+// @Param limit query int false "Limit data, max 50"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.TagResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/tag [get]
+func (ctrl ControllerHTTP) GetList(c *fiber.Ctx) error {
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.TagGetListRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	res, err := ctrl.svc.GetList(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: res,
+	})
+}
+
+// @Summary Get trending tag
+// @Description Get list tag with the highest time-decayed popularity in public posts over the trending window, the list is refreshed periodically
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param limit query int false "Limit data, max 50"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.TagTrendingResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/tag/trending [get]
+func (ctrl ControllerHTTP) GetTrending(c *fiber.Ctx) error {
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.TagTrendingRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	res, err := ctrl.svc.GetTrending(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.JSON(pkgutil.HTTPResponse{
+		Data: res,
+	})
+}
@@ -0,0 +1,21 @@
+package tag
+
+import (
+	"context"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	tagrepo "github.com/arfan21/project-sprint-social-media-api/internal/tag/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	Begin(ctx context.Context) (tx pgx.Tx, err error)
+	WithTx(tx pgx.Tx) *tagrepo.Repository
+
+	Upsert(ctx context.Context, names []string) (err error)
+	GetListByPrefix(ctx context.Context, prefix string, limit int) (data []entity.Tag, err error)
+	TryLockTrends(ctx context.Context) (locked bool, err error)
+	ReplaceTrends(ctx context.Context, window, halfLife time.Duration) (err error)
+	GetTrends(ctx context.Context, limit int) (data []entity.TagTrend, err error)
+}
@@ -0,0 +1,163 @@
+package tagrepo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// trendsLockKey is the advisory lock taken while the trends are refreshed,
+// so only one replica refreshes them at a time
+const trendsLockKey = "tag_trends"
+
+type Repository struct {
+	db dbpostgres.Queryer
+}
+
+func New(db dbpostgres.Queryer) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+func (r Repository) Begin(ctx context.Context) (tx pgx.Tx, err error) {
+	return r.db.Begin(ctx)
+}
+
+func (r Repository) WithTx(tx pgx.Tx) *Repository {
+	r.db = tx
+	return &r
+}
+
+// Upsert adds one use to each tag, unknown tags are created
+func (r Repository) Upsert(ctx context.Context, names []string) (err error) {
+	query := `
+		INSERT INTO tags (name, useCount, lastUsedAt)
+		SELECT name, 1, now()
+		FROM unnest($1::VARCHAR[]) AS name
+		ON CONFLICT (name) DO UPDATE
+		SET useCount = tags.useCount + 1, lastUsedAt = now()
+	`
+
+	_, err = r.db.Exec(ctx, query, names)
+	if err != nil {
+		err = fmt.Errorf("tag.repository.Upsert: failed to upsert tags: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetListByPrefix returns tags starting with the prefix, the most used first
+func (r Repository) GetListByPrefix(ctx context.Context, prefix string, limit int) (data []entity.Tag, err error) {
+	query := `
+		SELECT name, useCount, lastUsedAt
+		FROM tags
+		WHERE name LIKE $1
+		ORDER BY useCount DESC, name ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, escapeLike(prefix)+"%", limit)
+	if err != nil {
+		err = fmt.Errorf("tag.repository.GetListByPrefix: failed to get list tag: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag entity.Tag
+		err = rows.Scan(&tag.Name, &tag.UseCount, &tag.LastUsedAt)
+		if err != nil {
+			err = fmt.Errorf("tag.repository.GetListByPrefix: failed to scan tag: %w", err)
+			return
+		}
+
+		data = append(data, tag)
+	}
+
+	return
+}
+
+// TryLockTrends takes the trends lock until the end of the transaction,
+// false is returned when another transaction holds it
+func (r Repository) TryLockTrends(ctx context.Context) (locked bool, err error) {
+	query := `SELECT pg_try_advisory_xact_lock(hashtext($1))`
+
+	err = r.db.QueryRow(ctx, query, trendsLockKey).Scan(&locked)
+	if err != nil {
+		err = fmt.Errorf("tag.repository.TryLockTrends: failed to lock tag trends: %w", err)
+		return
+	}
+
+	return
+}
+
+// ReplaceTrends recomputes the trends from the posts created inside the window,
+// each post adds a score that halves every half life, only public posts are counted since the trends are shown to everyone
+func (r Repository) ReplaceTrends(ctx context.Context, window, halfLife time.Duration) (err error) {
+	_, err = r.db.Exec(ctx, `DELETE FROM tag_trends`)
+	if err != nil {
+		err = fmt.Errorf("tag.repository.ReplaceTrends: failed to delete tag trends: %w", err)
+		return
+	}
+
+	query := `
+		INSERT INTO tag_trends (name, score, postCount, refreshedAt)
+		SELECT
+			tag,
+			SUM(exp(-ln(2) * EXTRACT(EPOCH FROM (now() - p.createdAt)) / $2)),
+			COUNT(*),
+			now()
+		FROM posts p, unnest(p.tags) AS tag
+		WHERE p.deletedAt IS NULL AND p.visibility = $3 AND p.createdAt >= now() - make_interval(secs => $1)
+		GROUP BY tag
+	`
+
+	_, err = r.db.Exec(ctx, query, window.Seconds(), halfLife.Seconds(), entity.PostVisibilityPublic)
+	if err != nil {
+		err = fmt.Errorf("tag.repository.ReplaceTrends: failed to create tag trends: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetTrends(ctx context.Context, limit int) (data []entity.TagTrend, err error) {
+	query := `
+		SELECT name, score, postCount, refreshedAt
+		FROM tag_trends
+		ORDER BY score DESC, name ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		err = fmt.Errorf("tag.repository.GetTrends: failed to get tag trends: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var trend entity.TagTrend
+		err = rows.Scan(&trend.Name, &trend.Score, &trend.PostCount, &trend.RefreshedAt)
+		if err != nil {
+			err = fmt.Errorf("tag.repository.GetTrends: failed to scan tag trend: %w", err)
+			return
+		}
+
+		data = append(data, trend)
+	}
+
+	return
+}
+
+// escapeLike escapes the wildcards of LIKE so the prefix is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,15 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+)
+
+type Service interface {
+	Normalize(tags []string) (res []string)
+	Register(ctx context.Context, tags []string) (err error)
+	GetList(ctx context.Context, req model.TagGetListRequest) (res []model.TagResponse, err error)
+	GetTrending(ctx context.Context, req model.TagTrendingRequest) (res []model.TagTrendingResponse, err error)
+	RefreshTrending(ctx context.Context) (err error)
+}
@@ -0,0 +1,196 @@
+package tagsvc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/tag"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+)
+
+type Service struct {
+	repo tag.Repository
+}
+
+func New(repo tag.Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Normalize trims the tags, lower cases them and removes the leading #,
+// so "Go" and "#go" are the same tag. Empty and duplicate tags are removed
+func (s Service) Normalize(tags []string) (res []string) {
+	if tags == nil {
+		return nil
+	}
+
+	res = make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, v := range tags {
+		name := normalize(v)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+
+		seen[name] = struct{}{}
+		res = append(res, name)
+	}
+
+	return
+}
+
+func normalize(tag string) string {
+	return strings.ToLower(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+}
+
+// Register records a use of each tag for the autocomplete, the tags are expected to be normalized
+func (s Service) Register(ctx context.Context, tags []string) (err error) {
+	if len(tags) == 0 {
+		return
+	}
+
+	err = s.repo.Upsert(ctx, tags)
+	if err != nil {
+		err = fmt.Errorf("tag.service.Register: failed to upsert tags: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetList returns the tags starting with the prefix, the most used first
+func (s Service) GetList(ctx context.Context, req model.TagGetListRequest) (res []model.TagResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("tag.service.GetList: failed to validate request: %w", err)
+		return
+	}
+
+	res = []model.TagResponse{}
+
+	prefix := normalize(req.Prefix)
+	if prefix == "" {
+		return
+	}
+
+	data, err := s.repo.GetListByPrefix(ctx, prefix, req.Limit)
+	if err != nil {
+		err = fmt.Errorf("tag.service.GetList: failed to get list of tag: %w", err)
+		return
+	}
+
+	for _, v := range data {
+		res = append(res, model.TagResponse{
+			Tag:      v.Name,
+			UseCount: v.UseCount,
+		})
+	}
+
+	return
+}
+
+// GetTrending returns the tags with the highest score of the last refresh
+func (s Service) GetTrending(ctx context.Context, req model.TagTrendingRequest) (res []model.TagTrendingResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("tag.service.GetTrending: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetTrends(ctx, req.Limit)
+	if err != nil {
+		err = fmt.Errorf("tag.service.GetTrending: failed to get tag trends: %w", err)
+		return
+	}
+
+	res = make([]model.TagTrendingResponse, len(data))
+	for i, v := range data {
+		res[i] = model.TagTrendingResponse{
+			Tag:         v.Name,
+			Score:       v.Score,
+			PostCount:   v.PostCount,
+			RefreshedAt: v.RefreshedAt.Format(constant.TimeISO8601Format),
+		}
+	}
+
+	return
+}
+
+// RefreshTrending recomputes the trending tags over the configured window,
+// it does nothing when another replica is already refreshing them
+func (s Service) RefreshTrending(ctx context.Context) (err error) {
+	cfg := config.Get().Tag
+	window := time.Duration(cfg.TrendingWindow) * time.Hour
+	halfLife := time.Duration(cfg.TrendingHalfLife) * time.Hour
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("tag.service.RefreshTrending: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("tag.service.RefreshTrending: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("tag.service.RefreshTrending: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	locked, err := repo.TryLockTrends(ctx)
+	if err != nil {
+		err = fmt.Errorf("tag.service.RefreshTrending: failed to lock tag trends: %w", err)
+		return
+	}
+
+	if !locked {
+		return
+	}
+
+	err = repo.ReplaceTrends(ctx, window, halfLife)
+	if err != nil {
+		err = fmt.Errorf("tag.service.RefreshTrending: failed to replace tag trends: %w", err)
+		return
+	}
+
+	return
+}
+
+// RunTrendingRefresher refreshes the trending tags right away and then every interval until the context is done
+func (s Service) RunTrendingRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.RefreshTrending(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Log(ctx).Error().Err(err).Msg("tag.service.RunTrendingRefresher: failed to refresh trending tags")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
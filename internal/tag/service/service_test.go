@@ -0,0 +1,31 @@
+package tagsvc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{name: "nil", input: nil, want: nil},
+		{name: "empty", input: []string{}, want: []string{}},
+		{name: "lower cased", input: []string{"Go", "PostgreSQL"}, want: []string{"go", "postgresql"}},
+		{name: "leading hash is removed", input: []string{"#go", "##rust"}, want: []string{"go", "rust"}},
+		{name: "spaces are trimmed", input: []string{"  go ", " #rust\t"}, want: []string{"go", "rust"}},
+		{name: "inner hash is kept", input: []string{"c#"}, want: []string{"c#"}},
+		{name: "empty tags are removed", input: []string{"", "  ", "#", "go"}, want: []string{"go"}},
+		{name: "duplicates are removed in order", input: []string{"Go", "rust", "#go", "GO"}, want: []string{"go", "rust"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Service{}).Normalize(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
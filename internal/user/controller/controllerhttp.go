@@ -1,10 +1,14 @@
 package userctrl
 
 import (
+	"context"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
 	"github.com/arfan21/project-sprint-social-media-api/internal/user"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/exception"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/jwtkey"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/pkgutil"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
@@ -19,14 +23,25 @@ func New(svc user.Service) *ControllerHTTP {
 	return &ControllerHTTP{svc: svc}
 }
 
+// sessionClient describes the client a session is created for, apps may name the device in the X-Device-Name header
+func sessionClient(c *fiber.Ctx) model.SessionClient {
+	return model.SessionClient{
+		Device:    c.Get("X-Device-Name"),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IP:        c.IP(),
+	}
+}
+
 // @Summary Register user
-// @Description Register user
+// @Description Register user, an otp is sent to the credential and the credential is attached to the user once verified with /v1/user/verify using the returned access token
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param body body model.UserRegisterRequest true "Payload user Register Request"
 // @Success 201 {object} pkgutil.HTTPResponse{data=model.UserLoginResponse}
 // @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 409 {object} pkgutil.HTTPResponse
+// @Failure 429 {object} pkgutil.HTTPResponse "Otp requested too soon or too many otp sent to the credential"
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/user/register [post]
 func (ctrl ControllerHTTP) Register(c *fiber.Ctx) error {
@@ -34,6 +49,8 @@ func (ctrl ControllerHTTP) Register(c *fiber.Ctx) error {
 	err := c.BodyParser(&req)
 	exception.PanicIfNeeded(err)
 
+	req.Client = sessionClient(c)
+
 	res, err := ctrl.svc.Register(c.UserContext(), req)
 	exception.PanicIfNeeded(err)
 
@@ -44,13 +61,16 @@ func (ctrl ControllerHTTP) Register(c *fiber.Ctx) error {
 }
 
 // @Summary Login user
-// @Description Login user
+// @Description Login user, the credential must be verified with the otp sent on register, it is only attached to the user once verified so until then the login fails as for an unknown credential.
+// @Description When two factor authentication is enabled only a challenge token is returned, it is exchanged for the tokens at /v1/user/login/2fa
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param body body model.UserLoginRequest true "Payload user Login Request"
 // @Success 200 {object} pkgutil.HTTPResponse{data=model.UserLoginResponse}
 // @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse "Credential not verified"
+// @Failure 429 {object} pkgutil.HTTPResponse "Too many failed attempts, the Retry-After header holds the seconds to wait"
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/user/login [post]
 func (ctrl ControllerHTTP) Login(c *fiber.Ctx) error {
@@ -58,24 +78,179 @@ func (ctrl ControllerHTTP) Login(c *fiber.Ctx) error {
 	err := c.BodyParser(&req)
 	exception.PanicIfNeeded(err)
 
+	req.Client = sessionClient(c)
+
 	res, err := ctrl.svc.Login(c.UserContext(), req)
 	exception.PanicIfNeeded(err)
 
+	message := "User login successfully"
+	if res.TwoFactorRequired {
+		message = "Two factor authentication required"
+	}
+
 	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
-		Message: "User login successfully",
+		Message: message,
+		Data:    res,
+	})
+}
+
+// @Summary Refresh token
+// @Description Exchange a refresh token for a new access and refresh token pair, the used refresh token is invalidated
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param body body model.UserRefreshTokenRequest true "Payload user refresh token request"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.UserLoginResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 401 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/refresh [post]
+func (ctrl ControllerHTTP) RefreshToken(c *fiber.Ctx) error {
+	var req model.UserRefreshTokenRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	res, err := ctrl.svc.RefreshToken(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Token refreshed successfully",
 		Data:    res,
 	})
 }
 
+// @Summary Logout user
+// @Description Revoke the session of the current access token
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 401 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/logout [post]
+func (ctrl ControllerHTTP) Logout(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.Logout(c.UserContext(), model.UserLogoutRequest{
+		UserID:    claims.UserID,
+		SessionID: claims.ID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "User logout successfully",
+	})
+}
+
+// @Summary Logout everywhere
+// @Description Revoke every session of the user, the current one included
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 401 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/logout/all [post]
+func (ctrl ControllerHTTP) LogoutAll(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.LogoutAll(c.UserContext(), model.UserLogoutRequest{
+		UserID:    claims.UserID,
+		SessionID: claims.ID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "User logout from all sessions successfully",
+	})
+}
+
+// @Summary Get list session
+// @Description Get the active sessions of the user, most recently seen first
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.UserSessionResponse}
+// @Failure 401 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/session [get]
+func (ctrl ControllerHTTP) GetSessionList(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	res, err := ctrl.svc.GetSessionList(c.UserContext(), model.UserSessionGetListRequest{
+		UserID:    claims.UserID,
+		SessionID: claims.ID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Data: res,
+	})
+}
+
+// @Summary Revoke session
+// @Description Log out one of the sessions of the user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Session ID"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 401 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse "Session not found"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/session/{id} [delete]
+func (ctrl ControllerHTTP) RevokeSession(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.RevokeSession(c.UserContext(), model.UserSessionRevokeRequest{
+		UserID: claims.UserID,
+		ID:     c.Params("id"),
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Session revoked successfully",
+	})
+}
+
 // @Summary Add friend
-// @Description Add friend
+// @Description Send a friend request, if the user already sent a request to us it is accepted instead
 // @Tags friend
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "With the bearer started"
 // @Param body body model.FriendRequest true "Payload friend request"
-// @Success 200 {object} pkgutil.HTTPResponse
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.FriendRequestResponse}
 // @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 403 {object} pkgutil.HTTPResponse "One of the users blocked the other"
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/friend [post]
 func (ctrl ControllerHTTP) AddFriend(c *fiber.Ctx) error {
@@ -93,11 +268,181 @@ func (ctrl ControllerHTTP) AddFriend(c *fiber.Ctx) error {
 
 	req.UserIDAdder = claims.UserID
 
-	err = ctrl.svc.AddFriend(c.UserContext(), req)
+	res, err := ctrl.svc.AddFriend(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	message := "Friend request sent successfully"
+	if res.Status == entity.FriendRequestStatusAccepted {
+		message = "Friend added successfully"
+	}
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: message,
+		Data:    res,
+	})
+}
+
+// @Summary Get list incoming friend request
+// @Description Get list of pending friend request sent to the user
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param limit query int false "Limit data"
+// @Param offset query int false "Offset data"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.FriendRequestResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/friend/request/incoming [get]
+func (ctrl ControllerHTTP) GetListIncomingFriendRequest(c *fiber.Ctx) error {
+	return ctrl.getListFriendRequest(c, true)
+}
+
+// @Summary Get list outgoing friend request
+// @Description Get list of pending friend request sent by the user
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param limit query int false "Limit data"
+// @Param offset query int false "Offset data"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.FriendRequestResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/friend/request/outgoing [get]
+func (ctrl ControllerHTTP) GetListOutgoingFriendRequest(c *fiber.Ctx) error {
+	return ctrl.getListFriendRequest(c, false)
+}
+
+func (ctrl ControllerHTTP) getListFriendRequest(c *fiber.Ctx, incoming bool) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.FriendRequestGetListRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+	req.Incoming = incoming
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	res, count, err := ctrl.svc.GetFriendRequestList(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Data: res,
+		Meta: pkgutil.MetaResponse{
+			Offset: req.Offset,
+			Limit:  req.Limit,
+			Total:  count,
+		},
+	})
+}
+
+// @Summary Accept friend request
+// @Description Accept a pending friend request sent to the user
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Friend request id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/friend/request/{id}/accept [post]
+func (ctrl ControllerHTTP) AcceptFriendRequest(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.AcceptFriendRequest(c.UserContext(), model.FriendRequestActionRequest{
+		FriendRequestID: c.Params("id"),
+		UserID:          claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Friend request accepted successfully",
+	})
+}
+
+// @Summary Reject friend request
+// @Description Reject a pending friend request sent to the user
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Friend request id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/friend/request/{id}/reject [post]
+func (ctrl ControllerHTTP) RejectFriendRequest(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.RejectFriendRequest(c.UserContext(), model.FriendRequestActionRequest{
+		FriendRequestID: c.Params("id"),
+		UserID:          claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Friend request rejected successfully",
+	})
+}
+
+// @Summary Cancel friend request
+// @Description Cancel a pending friend request sent by the user
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "Friend request id"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/friend/request/{id} [delete]
+func (ctrl ControllerHTTP) CancelFriendRequest(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	err := ctrl.svc.CancelFriendRequest(c.UserContext(), model.FriendRequestActionRequest{
+		FriendRequestID: c.Params("id"),
+		UserID:          claims.UserID,
+	})
 	exception.PanicIfNeeded(err)
 
 	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
-		Message: "Friend added successfully",
+		Message: "Friend request cancelled successfully",
 	})
 }
 
@@ -136,18 +481,19 @@ func (ctrl ControllerHTTP) DeleteFriend(c *fiber.Ctx) error {
 }
 
 // @Summary Get list user
-// @Description Get list user
+// @Description Get list user, pass nextCursor or prevCursor of the meta as cursor to use keyset pagination, offset and total are not used for keyset pages and sortBy friendCount cannot be used with a cursor. Blocked users are not listed
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "With the bearer started"
 // @Param limit query int false "Limit data"
 // @Param offset query int false "Offset data"
+// @Param cursor query string false "Cursor of the next or previous page"
 // @Param search query string false "Search data"
 // @Param sortBy query string false "Sort by data"
 // @Param orderBy query string false "Order by data"
 // @Param onlyFriend query bool false "Only friend data"
-// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.UserResponse}
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.UserResponse,meta=pkgutil.MetaResponse}
 // @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/friend [get]
@@ -173,21 +519,57 @@ func (ctrl ControllerHTTP) GetList(c *fiber.Ctx) error {
 		req.Limit = 5
 	}
 
-	res, count, err := ctrl.svc.GetList(c.UserContext(), req)
+	res, nextCursor, prevCursor, count, err := ctrl.svc.GetList(c.UserContext(), req)
 	exception.PanicIfNeeded(err)
 
 	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
 		Data: res,
 		Meta: pkgutil.MetaResponse{
-			Offset: req.Offset,
-			Limit:  req.Limit,
-			Total:  count,
+			Offset:     req.Offset,
+			Limit:      req.Limit,
+			Total:      count,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
 		},
 	})
 }
 
+// @Summary Get user profile
+// @Description Get user profile with the relationship of the user with the viewer, the relationship is one of self, friend, pending or none
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param id path string true "User id"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.UserProfileResponse}
+// @Failure 403 {object} pkgutil.HTTPResponse "One of the users blocked the other"
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/{id} [get]
+func (ctrl ControllerHTTP) GetProfile(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	req := model.UserProfileRequest{
+		UserID:   c.Params("id"),
+		ViewerID: claims.UserID,
+	}
+
+	res, err := ctrl.svc.GetProfile(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Data: res,
+	})
+}
+
 // @Summary Update Phone
-// @Description Update Phone
+// @Description Send an otp to the phone, it is linked to the user once verified with /v1/user/verify
 // @Tags user
 // @Accept json
 // @Produce json
@@ -195,6 +577,8 @@ func (ctrl ControllerHTTP) GetList(c *fiber.Ctx) error {
 // @Param body body model.UserPhoneUpdateRequest true "Payload user update phone request"
 // @Success 200 {object} pkgutil.HTTPResponse
 // @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 409 {object} pkgutil.HTTPResponse
+// @Failure 429 {object} pkgutil.HTTPResponse "Otp requested too soon or too many otp sent to the phone"
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/user/link/phone [post]
 func (ctrl ControllerHTTP) UpdatePhone(c *fiber.Ctx) error {
@@ -216,12 +600,12 @@ func (ctrl ControllerHTTP) UpdatePhone(c *fiber.Ctx) error {
 	exception.PanicIfNeeded(err)
 
 	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
-		Message: "Phone updated successfully",
+		Message: "Verification code sent to the phone",
 	})
 }
 
 // @Summary Update Email
-// @Description Update Email
+// @Description Send an otp to the email, it is linked to the user once verified with /v1/user/verify
 // @Tags user
 // @Accept json
 // @Produce json
@@ -229,6 +613,8 @@ func (ctrl ControllerHTTP) UpdatePhone(c *fiber.Ctx) error {
 // @Param body body model.UserEmailUpdateRequest true "Payload user update email request"
 // @Success 200 {object} pkgutil.HTTPResponse
 // @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 409 {object} pkgutil.HTTPResponse
+// @Failure 429 {object} pkgutil.HTTPResponse "Otp requested too soon or too many otp sent to the email"
 // @Failure 500 {object} pkgutil.HTTPResponse
 // @Router /v1/user/link/email [post]
 func (ctrl ControllerHTTP) UpdateEmail(c *fiber.Ctx) error {
@@ -250,7 +636,7 @@ func (ctrl ControllerHTTP) UpdateEmail(c *fiber.Ctx) error {
 	exception.PanicIfNeeded(err)
 
 	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
-		Message: "Email updated successfully",
+		Message: "Verification code sent to the email",
 	})
 }
 
@@ -287,3 +673,543 @@ func (ctrl ControllerHTTP) UpdateProfile(c *fiber.Ctx) error {
 		Message: "Profile updated successfully",
 	})
 }
+
+// @Summary Block user
+// @Description Block user, the friendship and the pending friend requests between the users are removed. Blocked users cannot add each other as friend, comment on each other or find each other in the list user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserBlockRequest true "Payload block request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/block [post]
+func (ctrl ControllerHTTP) Block(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserBlockRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserIDBlocker = claims.UserID
+
+	err = ctrl.svc.Block(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "User blocked successfully",
+	})
+}
+
+// @Summary Unblock user
+// @Description Unblock user, the removed friendship is not restored
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserBlockRequest true "Payload block request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/block [delete]
+func (ctrl ControllerHTTP) Unblock(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserBlockRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserIDBlocker = claims.UserID
+
+	err = ctrl.svc.Unblock(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "User unblocked successfully",
+	})
+}
+
+// @Summary Get list blocked user
+// @Description Get list of user blocked by the user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param limit query int false "Limit data"
+// @Param offset query int false "Offset data"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.UserResponse,meta=pkgutil.MetaResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/block [get]
+func (ctrl ControllerHTTP) GetBlockList(c *fiber.Ctx) error {
+	return ctrl.getListRestriction(c, ctrl.svc.GetBlockList)
+}
+
+// @Summary Mute user
+// @Description Mute user, the posts of the user are hidden from the list post and the discover list. The muted user is not told about it
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserMuteRequest true "Payload mute request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 404 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/mute [post]
+func (ctrl ControllerHTTP) Mute(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserMuteRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserIDMuter = claims.UserID
+
+	err = ctrl.svc.Mute(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "User muted successfully",
+	})
+}
+
+// @Summary Unmute user
+// @Description Unmute user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserMuteRequest true "Payload mute request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/mute [delete]
+func (ctrl ControllerHTTP) Unmute(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserMuteRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserIDMuter = claims.UserID
+
+	err = ctrl.svc.Unmute(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "User unmuted successfully",
+	})
+}
+
+// @Summary Get list muted user
+// @Description Get list of user muted by the user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param limit query int false "Limit data"
+// @Param offset query int false "Offset data"
+// @Success 200 {object} pkgutil.HTTPResponse{data=[]model.UserResponse,meta=pkgutil.MetaResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/mute [get]
+func (ctrl ControllerHTTP) GetMuteList(c *fiber.Ctx) error {
+	return ctrl.getListRestriction(c, ctrl.svc.GetMuteList)
+}
+
+func (ctrl ControllerHTTP) getListRestriction(
+	c *fiber.Ctx,
+	getList func(ctx context.Context, req model.UserRestrictionGetListRequest) ([]model.UserResponse, int, error),
+) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	mapQuery := c.Queries()
+	err := validation.ValidateQuery(mapQuery)
+	exception.PanicIfNeeded(err)
+
+	var req model.UserRestrictionGetListRequest
+	err = c.QueryParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	res, count, err := getList(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Data: res,
+		Meta: pkgutil.MetaResponse{
+			Offset: req.Offset,
+			Limit:  req.Limit,
+			Total:  count,
+		},
+	})
+}
+
+// @Summary Update password
+// @Description Change the password of the user, every other session of the user is logged out
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserPasswordUpdateRequest true "Payload user update password request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/password [post]
+func (ctrl ControllerHTTP) UpdatePassword(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserPasswordUpdateRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+	req.SessionID = claims.ID
+
+	err = ctrl.svc.UpdatePassword(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Password updated successfully",
+	})
+}
+
+// @Summary Delete account
+// @Description Schedule the deletion of the account and log out every session. The account is deleted once the grace period is over,
+// @Description logging in before cancels the deletion. Posts are deleted and comments are kept under an anonymous name
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserDeleteRequest true "Payload user delete request"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.UserDeleteResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user [delete]
+func (ctrl ControllerHTTP) DeleteAccount(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserDeleteRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+
+	res, err := ctrl.svc.DeleteAccount(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Account deletion scheduled successfully",
+		Data:    res,
+	})
+}
+
+// @Summary Forgot password
+// @Description Send an otp to reset the password to the email or phone, the response is the same whether the credential is registered or not. No new otp is sent within the resend interval of the last one or once the user reached the limit of resets in the window
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param body body model.UserPasswordForgotRequest true "Payload user forgot password request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/password/forgot [post]
+func (ctrl ControllerHTTP) ForgotPassword(c *fiber.Ctx) error {
+	var req model.UserPasswordForgotRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	err = ctrl.svc.ForgotPassword(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "If the credential is registered an otp has been sent",
+	})
+}
+
+// @Summary Reset password
+// @Description Set a new password with the otp sent by forgot password, every session of the user is logged out
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param body body model.UserPasswordResetRequest true "Payload user reset password request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 429 {object} pkgutil.HTTPResponse
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/password/reset [post]
+func (ctrl ControllerHTTP) ResetPassword(c *fiber.Ctx) error {
+	var req model.UserPasswordResetRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	err = ctrl.svc.ResetPassword(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Password reset successfully, please login again",
+	})
+}
+
+// @Summary Verify credential
+// @Description Verify the email or phone of the user with the otp sent on register or link, the credential is attached to the user once verified
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserVerifyRequest true "Payload user verify request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 404 {object} pkgutil.HTTPResponse "No verification pending"
+// @Failure 409 {object} pkgutil.HTTPResponse
+// @Failure 429 {object} pkgutil.HTTPResponse "Too many attempts"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/verify [post]
+func (ctrl ControllerHTTP) VerifyCredential(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserVerifyRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.VerifyCredential(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Credential verified successfully",
+	})
+}
+
+// @Summary Resend verification
+// @Description Send a new otp to the email or phone of the user waiting to be verified
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserVerificationResendRequest true "Payload user verification resend request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 404 {object} pkgutil.HTTPResponse "No verification pending"
+// @Failure 429 {object} pkgutil.HTTPResponse "Otp requested too soon or too many otp sent to the address"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/verify/resend [post]
+func (ctrl ControllerHTTP) ResendVerification(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserVerificationResendRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.ResendVerification(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Verification code sent",
+	})
+}
+
+// @Summary Login with two factor authentication
+// @Description Exchange the challenge token returned by login and a code of the authenticator app or a recovery code for the access and refresh token, wrong codes count as failed logins of the credential
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param body body model.UserLoginTwoFactorRequest true "Payload user login two factor request"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.UserLoginResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 401 {object} pkgutil.HTTPResponse "Invalid or expired challenge token"
+// @Failure 429 {object} pkgutil.HTTPResponse "Too many attempts, the Retry-After header holds the seconds to wait when the login is delayed"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/login/2fa [post]
+func (ctrl ControllerHTTP) LoginTwoFactor(c *fiber.Ctx) error {
+	var req model.UserLoginTwoFactorRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.Client = sessionClient(c)
+
+	res, err := ctrl.svc.LoginTwoFactor(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "User login successfully",
+		Data:    res,
+	})
+}
+
+// @Summary Enroll two factor authentication
+// @Description Create a secret for the authenticator app, the uri is usually shown as a qr code. Enrolling again replaces a secret that is not enabled yet
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.UserTwoFactorEnrollResponse}
+// @Failure 409 {object} pkgutil.HTTPResponse "Two factor authentication already enabled"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/2fa/enroll [post]
+func (ctrl ControllerHTTP) EnrollTwoFactor(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	res, err := ctrl.svc.EnrollTwoFactor(c.UserContext(), model.UserTwoFactorEnrollRequest{
+		UserID: claims.UserID,
+	})
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Two factor authentication enrolled, enable it with a code of the authenticator app",
+		Data:    res,
+	})
+}
+
+// @Summary Enable two factor authentication
+// @Description Enable the enrolled secret with a code of the authenticator app, the recovery codes are only shown once
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserTwoFactorEnableRequest true "Payload user enable two factor request"
+// @Success 200 {object} pkgutil.HTTPResponse{data=model.UserTwoFactorEnableResponse}
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 409 {object} pkgutil.HTTPResponse "Two factor authentication already enabled"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/2fa/enable [post]
+func (ctrl ControllerHTTP) EnableTwoFactor(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserTwoFactorEnableRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+
+	res, err := ctrl.svc.EnableTwoFactor(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Two factor authentication enabled",
+		Data:    res,
+	})
+}
+
+// @Summary Disable two factor authentication
+// @Description Disable two factor authentication with the password and a code of the authenticator app or a recovery code
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "With the bearer started"
+// @Param body body model.UserTwoFactorDisableRequest true "Payload user disable two factor request"
+// @Success 200 {object} pkgutil.HTTPResponse
+// @Failure 400 {object} pkgutil.HTTPResponse{data=[]pkgutil.ErrValidationResponse} "Error validation field"
+// @Failure 500 {object} pkgutil.HTTPResponse
+// @Router /v1/user/2fa/disable [post]
+func (ctrl ControllerHTTP) DisableTwoFactor(c *fiber.Ctx) error {
+	claims, ok := c.Locals(constant.JWTClaimsContextKey).(model.JWTClaims)
+	if !ok {
+		logger.Log(c.UserContext()).Error().Msg("cannot get claims from context")
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "invalid or expired token",
+		})
+	}
+
+	var req model.UserTwoFactorDisableRequest
+	err := c.BodyParser(&req)
+	exception.PanicIfNeeded(err)
+
+	req.UserID = claims.UserID
+
+	err = ctrl.svc.DisableTwoFactor(c.UserContext(), req)
+	exception.PanicIfNeeded(err)
+
+	return c.Status(fiber.StatusOK).JSON(pkgutil.HTTPResponse{
+		Message: "Two factor authentication disabled",
+	})
+}
+
+// @Summary JSON web key set
+// @Description Public keys that verify the access tokens, the kid header of a token names its key. Empty when the tokens are signed with a shared secret
+// @Tags user
+// @Produce json
+// @Success 200 {object} jwtkey.JWKS
+// @Router /.well-known/jwks.json [get]
+func (ctrl ControllerHTTP) JWKS(c *fiber.Ctx) error {
+	var res jwtkey.JWKS = ctrl.svc.JWKS()
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -2,11 +2,13 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
 	userrepo "github.com/arfan21/project-sprint-social-media-api/internal/user/repository"
 	"github.com/jackc/pgx/v5"
+	"gopkg.in/guregu/null.v4"
 )
 
 type Repository interface {
@@ -21,8 +23,70 @@ type Repository interface {
 	GetList(ctx context.Context, filter model.UserGetListRequest) (data []entity.User, err error)
 	GetCountList(ctx context.Context, filter model.UserGetListRequest) (count int, err error)
 	IsFriend(ctx context.Context, userIdAdder, userIdAdded string) (isFriend bool, err error)
+	GetFriendIDs(ctx context.Context, userID string) (ids []string, err error)
 	GetListMap(ctx context.Context, filter model.UserGetListRequest) (data map[string]entity.User, err error)
+	GetMentionCandidates(ctx context.Context, ownerID, authorID string, handles []string, includeFriends bool) (data []entity.User, err error)
 	UpdatePhone(ctx context.Context, userId, phone string) (err error)
 	UpdateEmail(ctx context.Context, userId, email string) (err error)
 	UpdateProfile(ctx context.Context, data entity.User) (err error)
+	IncrementFriendCount(ctx context.Context, userId string) (err error)
+	DecrementFriendCount(ctx context.Context, userId string) (err error)
+	CreateFriendRequest(ctx context.Context, data entity.FriendRequest) (err error)
+	GetFriendRequestByID(ctx context.Context, id string) (data entity.FriendRequest, err error)
+	GetPendingFriendRequest(ctx context.Context, userIdRequester, userIdTarget string) (data entity.FriendRequest, err error)
+	GetPendingFriendRequestBetween(ctx context.Context, userIdA, userIdB string) (data entity.FriendRequest, err error)
+	UpdateFriendRequestStatus(ctx context.Context, id, status string) (err error)
+	GetFriendRequestList(ctx context.Context, filter model.FriendRequestGetListRequest) (data []entity.FriendRequest, err error)
+	CreateSession(ctx context.Context, data entity.Session) (err error)
+	GetSessionByID(ctx context.Context, id string) (data entity.Session, err error)
+	RotateSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) (err error)
+	RevokeSession(ctx context.Context, id, userId string) (err error)
+	TouchSession(ctx context.Context, id string) (active bool, err error)
+	GetSessionList(ctx context.Context, userId string) (data []entity.Session, err error)
+	CreateBlock(ctx context.Context, userIdBlocker, userIdBlocked string) (err error)
+	DeleteBlock(ctx context.Context, userIdBlocker, userIdBlocked string) (err error)
+	IsBlocked(ctx context.Context, userIdA, userIdB string) (isBlocked bool, err error)
+	CancelPendingFriendRequestsBetween(ctx context.Context, userIdA, userIdB string) (err error)
+	GetBlockList(ctx context.Context, filter model.UserRestrictionGetListRequest) (data []entity.User, err error)
+	CreateMute(ctx context.Context, userIdMuter, userIdMuted string) (err error)
+	DeleteMute(ctx context.Context, userIdMuter, userIdMuted string) (err error)
+	GetMuteList(ctx context.Context, filter model.UserRestrictionGetListRequest) (data []entity.User, err error)
+	GetPasswordByID(ctx context.Context, id string) (password string, err error)
+	UpdatePassword(ctx context.Context, userId, password string) (err error)
+	RevokeUserSessions(ctx context.Context, userId, exceptID string) (ids []string, err error)
+	CreatePasswordReset(ctx context.Context, data entity.PasswordReset) (err error)
+	ExpirePasswordResets(ctx context.Context, userId string) (err error)
+	GetPasswordResetStats(ctx context.Context, userId string, since time.Time) (count int, latestAt null.Time, err error)
+	GetActivePasswordReset(ctx context.Context, userId string) (data entity.PasswordReset, err error)
+	ConsumePasswordResetAttempt(ctx context.Context, id string, maxAttempts int) (err error)
+	UsePasswordReset(ctx context.Context, id string) (err error)
+	CreateVerification(ctx context.Context, data entity.CredentialVerification) (err error)
+	GetLatestVerification(ctx context.Context, userId, channel string) (data entity.CredentialVerification, err error)
+	GetActiveVerification(ctx context.Context, userId, channel string) (data entity.CredentialVerification, err error)
+	GetVerificationStats(ctx context.Context, channel, value string, since time.Time) (count int, latestAt null.Time, err error)
+	ExpireVerifications(ctx context.Context, userId, channel string) (err error)
+	ConsumeVerificationAttempt(ctx context.Context, id string, maxAttempts int) (err error)
+	UseVerification(ctx context.Context, id string) (err error)
+	GetTOTP(ctx context.Context, userId string) (data entity.UserTOTP, err error)
+	UpsertTOTP(ctx context.Context, data entity.UserTOTP) (err error)
+	EnableTOTP(ctx context.Context, userId string, step int64) (err error)
+	UseTOTPStep(ctx context.Context, userId string, step int64) (err error)
+	DeleteTOTP(ctx context.Context, userId string) (err error)
+	ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) (err error)
+	DeleteRecoveryCodes(ctx context.Context, userId string) (err error)
+	UseRecoveryCode(ctx context.Context, userId, codeHash string) (err error)
+	CreateLoginChallenge(ctx context.Context, data entity.LoginChallenge) (err error)
+	GetLoginChallenge(ctx context.Context, id string) (data entity.LoginChallenge, err error)
+	ConsumeLoginChallengeAttempt(ctx context.Context, id string, maxAttempts int) (err error)
+	UseLoginChallenge(ctx context.Context, id string) (err error)
+	ScheduleDeletion(ctx context.Context, userId string, scheduledAt time.Time) (err error)
+	CancelDeletion(ctx context.Context, userId string) (err error)
+	GetDueDeletionIDs(ctx context.Context, now time.Time, limit int) (ids []string, err error)
+	LockDueDeletion(ctx context.Context, userId string, now time.Time) (imageUrl null.String, err error)
+	DeleteReactionsOfUser(ctx context.Context, userId string) (err error)
+	DeleteFriendsOfUser(ctx context.Context, userId string) (err error)
+	DecrementTagsOfUser(ctx context.Context, userId string) (err error)
+	DeletePostsOfUser(ctx context.Context, userId string) (err error)
+	DeleteUserData(ctx context.Context, userId string) (err error)
+	Anonymize(ctx context.Context, userId, name string) (err error)
 }
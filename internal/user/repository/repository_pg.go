@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
 	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"gopkg.in/guregu/null.v4"
 )
 
 type Repository struct {
@@ -55,13 +58,15 @@ func (r Repository) Create(ctx context.Context, data entity.User) (err error) {
 	return
 }
 
+// GetByCredential only finds verified credentials, the pending ones are kept in credential_verifications
 func (r Repository) GetByCredential(ctx context.Context, credentialType, credentialValue string) (data entity.User, err error) {
 	credType := "email"
 	if credentialType == "phone" {
 		credType = "phone"
 	}
 	query := `
-		SELECT id, name, password, email, phone
+		SELECT id, name, password, email, phone, emailVerifiedAt, phoneVerifiedAt, deletionScheduledAt,
+			(SELECT t.enabledAt FROM user_totps t WHERE t.userId = users.id)
 		FROM users
 		WHERE ` + credType + ` = $1
 	`
@@ -72,6 +77,10 @@ func (r Repository) GetByCredential(ctx context.Context, credentialType, credent
 		&data.Password,
 		&data.Email,
 		&data.Phone,
+		&data.EmailVerifiedAt,
+		&data.PhoneVerifiedAt,
+		&data.DeletionScheduledAt,
+		&data.TOTPEnabledAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -88,9 +97,9 @@ func (r Repository) GetByCredential(ctx context.Context, credentialType, credent
 
 func (r Repository) GetByID(ctx context.Context, id string) (data entity.User, err error) {
 	query := `
-		SELECT id, name, email, phone
+		SELECT id, name, email, phone, imageUrl, friendCount, createdAt, emailVerifiedAt, phoneVerifiedAt, deletionScheduledAt
 		FROM users
-		WHERE id = $1
+		WHERE id = $1 AND deletedAt IS NULL
 	`
 
 	err = r.db.QueryRow(ctx, query, id).Scan(
@@ -98,6 +107,12 @@ func (r Repository) GetByID(ctx context.Context, id string) (data entity.User, e
 		&data.Name,
 		&data.Email,
 		&data.Phone,
+		&data.ImageUrl,
+		&data.FriendCount,
+		&data.CreatedAt,
+		&data.EmailVerifiedAt,
+		&data.PhoneVerifiedAt,
+		&data.DeletionScheduledAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -163,7 +178,8 @@ func (r Repository) DeleteFriend(ctx context.Context, userIdAdder, userIdAdded s
 
 // queryGetListWithFilter is a helper function to get list of user with filter
 // the query is expected to be joined with friends table
-// where table users is alias as u and friends is alias as fr
+// where table users is alias as u and friends is alias as fr.
+// When the cursor id is set the page is a keyset page ordered by id only, the offset is ignored
 func (r Repository) queryGetListWithFilter(ctx context.Context, query string, groupByCols []string, filter model.UserGetListRequest) (rows pgx.Rows, err error) {
 	arrArgs := []interface{}{}
 	whereQuery := ""
@@ -179,6 +195,9 @@ func (r Repository) queryGetListWithFilter(ctx context.Context, query string, gr
 	if len(filter.UserIDs) > 0 {
 		arrArgs = append(arrArgs, filter.UserIDs)
 		whereQuery += fmt.Sprintf("u.id = ANY ($%d) %s", len(arrArgs), andStatement)
+	} else {
+		// deleted accounts are only kept as the author of their comments, they are never listed
+		whereQuery += "u.deletedAt IS NULL" + andStatement
 	}
 
 	if filter.OnlyFriend {
@@ -187,12 +206,45 @@ func (r Repository) queryGetListWithFilter(ctx context.Context, query string, gr
 		whereQuery += fmt.Sprintf("(fr.userIdAdder = $%d OR fr.userIdAdded = $%d) %s", len(arrArgs), len(arrArgs), andStatement)
 	}
 
+	// users blocked by the viewer or who blocked the viewer are never listed
+	if filter.UserID != "" {
+		arrArgs = append(arrArgs, filter.UserID)
+		whereQuery += fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM user_blocks b
+			WHERE (b.userIdBlocker = $%[1]d AND b.userIdBlocked = u.id) OR (b.userIdBlocker = u.id AND b.userIdBlocked = $%[1]d)
+		) %s`, len(arrArgs), andStatement)
+	}
+
 	if filter.Search != "" {
 		arrArgs = append(arrArgs, "%"+strings.ToLower(filter.Search)+"%")
 		whereQuery += fmt.Sprintf("(LOWER(u.name) LIKE $%d) %s", len(arrArgs), andStatement)
 	}
 
-	if lenArgs := len(arrArgs); lenArgs > 0 {
+	orderBy := "DESC"
+	if filter.OrderBy != "" && filter.OrderBy != "desc" {
+		orderBy = "ASC"
+	}
+
+	if filter.CursorID != "" {
+		// the previous page is read backward and reversed by the caller
+		if filter.CursorPrev {
+			if orderBy == "ASC" {
+				orderBy = "DESC"
+			} else {
+				orderBy = "ASC"
+			}
+		}
+
+		operator := ">"
+		if orderBy == "DESC" {
+			operator = "<"
+		}
+
+		arrArgs = append(arrArgs, filter.CursorID)
+		whereQuery += fmt.Sprintf("(u.id %s $%d) %s", operator, len(arrArgs), andStatement)
+	}
+
+	if whereQuery != "" {
 		whereQuery = "WHERE " + whereQuery[:len(whereQuery)-len(andStatement)] + " "
 	}
 	query += joinQuery
@@ -205,17 +257,12 @@ func (r Repository) queryGetListWithFilter(ctx context.Context, query string, gr
 
 	if !filter.DisableOrder {
 		sortBy := "id"
-		if filter.SortBy != "" && filter.SortBy != "createdAt" {
+		if filter.CursorID == "" && filter.SortBy != "" && filter.SortBy != "createdAt" {
 			sortBy = "friendCount"
 
 		}
 
 		query += fmt.Sprintf("ORDER BY %s ", sortBy)
-
-		orderBy := "DESC"
-		if filter.OrderBy != "" && filter.OrderBy != "desc" {
-			orderBy = "ASC"
-		}
 		query += fmt.Sprintf("%s ", orderBy)
 	}
 
@@ -223,15 +270,23 @@ func (r Repository) queryGetListWithFilter(ctx context.Context, query string, gr
 		arrArgs = append(arrArgs, filter.Limit)
 		query += fmt.Sprintf("LIMIT $%d ", len(arrArgs))
 
-		arrArgs = append(arrArgs, filter.Offset)
-		query += fmt.Sprintf("OFFSET $%d ", len(arrArgs))
+		if filter.CursorID == "" {
+			arrArgs = append(arrArgs, filter.Offset)
+			query += fmt.Sprintf("OFFSET $%d ", len(arrArgs))
+		}
 	}
 	return r.db.Query(ctx, query, arrArgs...)
 }
 
 func (r Repository) GetList(ctx context.Context, filter model.UserGetListRequest) (data []entity.User, err error) {
+	// keyset pages skip counting every matching row
+	totalColumn := "COUNT(*) OVER()"
+	if filter.CursorID != "" {
+		totalColumn = "0"
+	}
+
 	query := `
-		SELECT COUNT(*) OVER() AS total_count, u.id, u.name, u.imageurl, u.createdat, u.friendCount
+		SELECT ` + totalColumn + ` AS total_count, u.id, u.name, u.imageurl, u.createdat, u.friendCount
 		FROM users u
 	`
 
@@ -297,6 +352,77 @@ func (r Repository) IsFriend(ctx context.Context, userIdAdder, userIdAdded strin
 	return
 }
 
+func (r Repository) GetFriendIDs(ctx context.Context, userID string) (ids []string, err error) {
+	query := `
+		SELECT userIdAdded FROM friends WHERE userIdAdder = $1
+		UNION
+		SELECT userIdAdder FROM friends WHERE userIdAdded = $1
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetFriendIDs: failed to get friend ids: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	ids = []string{}
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			err = fmt.Errorf("user.repository.GetFriendIDs: failed to scan friend id: %w", err)
+			return
+		}
+
+		ids = append(ids, id.String())
+	}
+
+	return
+}
+
+// GetMentionCandidates returns the owner, and the friends of the owner when includeFriends is set,
+// whose name matches one of the normalized handles. Users blocked with the author are left out
+func (r Repository) GetMentionCandidates(ctx context.Context, ownerID, authorID string, handles []string, includeFriends bool) (data []entity.User, err error) {
+	query := `
+		WITH candidates AS (
+			SELECT $1::UUID AS id
+			UNION
+			SELECT userIdAdded FROM friends WHERE userIdAdder = $1 AND $3
+			UNION
+			SELECT userIdAdder FROM friends WHERE userIdAdded = $1 AND $3
+		)
+		SELECT u.id, u.name
+		FROM candidates c
+		JOIN users u ON u.id = c.id
+		WHERE lower(regexp_replace(u.name, '[^[:alnum:]_]', '', 'g')) = ANY ($2)
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.userIdBlocker = $4 AND b.userIdBlocked = u.id) OR (b.userIdBlocker = u.id AND b.userIdBlocked = $4)
+			)
+	`
+
+	rows, err := r.db.Query(ctx, query, ownerID, handles, includeFriends, authorID)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetMentionCandidates: failed to get mention candidates: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user entity.User
+		err = rows.Scan(&user.ID, &user.Name)
+		if err != nil {
+			err = fmt.Errorf("user.repository.GetMentionCandidates: failed to scan user: %w", err)
+			return
+		}
+
+		data = append(data, user)
+	}
+
+	return
+}
+
 func (r Repository) GetListMap(ctx context.Context, filter model.UserGetListRequest) (data map[string]entity.User, err error) {
 	query := `
 		SELECT u.id, u.name, u.imageurl, u.createdat, u.friendCount AS friendCount
@@ -326,10 +452,11 @@ func (r Repository) GetListMap(ctx context.Context, filter model.UserGetListRequ
 	return
 }
 
+// UpdatePhone links the phone as verified, it is only called once the ownership of the phone is proven
 func (r Repository) UpdatePhone(ctx context.Context, userId, phone string) (err error) {
 	query := `
 		UPDATE users
-		SET phone = $1
+		SET phone = $1, phoneVerifiedAt = now()
 		WHERE id = $2
 	`
 
@@ -349,10 +476,11 @@ func (r Repository) UpdatePhone(ctx context.Context, userId, phone string) (err
 	return
 }
 
+// UpdateEmail links the email as verified, it is only called once the ownership of the email is proven
 func (r Repository) UpdateEmail(ctx context.Context, userId, email string) (err error) {
 	query := `
 		UPDATE users
-		SET email = $1
+		SET email = $1, emailVerifiedAt = now()
 		WHERE id = $2
 	`
 
@@ -435,3 +563,1475 @@ func (r Repository) DecrementFriendCount(ctx context.Context, userId string) (er
 
 	return
 }
+
+func (r Repository) CreateSession(ctx context.Context, data entity.Session) (err error) {
+	query := `
+		INSERT INTO sessions (id, userId, refreshTokenHash, expiresAt, device, userAgent, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.Exec(ctx, query, data.ID, data.UserID, data.RefreshTokenHash, data.ExpiresAt, data.Device, data.UserAgent, data.IP)
+	if err != nil {
+		err = fmt.Errorf("user.repository.CreateSession: failed to create session: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetSessionByID(ctx context.Context, id string) (data entity.Session, err error) {
+	query := `
+		SELECT id, userId, refreshTokenHash, expiresAt, revokedAt, createdAt, updatedAt
+		FROM sessions
+		WHERE id = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, id).Scan(
+		&data.ID,
+		&data.UserID,
+		&data.RefreshTokenHash,
+		&data.ExpiresAt,
+		&data.RevokedAt,
+		&data.CreatedAt,
+		&data.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrSessionNotFound
+		}
+
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				err = constant.ErrSessionNotFound
+			}
+		}
+
+		err = fmt.Errorf("user.repository.GetSessionByID: failed to get session by id: %w", err)
+		return
+	}
+
+	return
+}
+
+// RotateSession swaps the refresh token hash of an active session,
+// it only succeeds when oldHash is still the current hash so two concurrent
+// refreshes with the same token cannot both win.
+func (r Repository) RotateSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) (err error) {
+	query := `
+		UPDATE sessions
+		SET refreshTokenHash = $1, expiresAt = $2, lastSeenAt = now()
+		WHERE id = $3 AND refreshTokenHash = $4 AND revokedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, newHash, expiresAt, id, oldHash)
+	if err != nil {
+		err = fmt.Errorf("user.repository.RotateSession: failed to rotate session: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.RotateSession: failed to rotate session: %w", constant.ErrRefreshTokenReused)
+		return
+	}
+
+	return
+}
+
+func (r Repository) RevokeSession(ctx context.Context, id, userId string) (err error) {
+	query := `
+		UPDATE sessions
+		SET revokedAt = now()
+		WHERE id = $1 AND userId = $2 AND revokedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id, userId)
+	if err != nil {
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				err = constant.ErrSessionNotFound
+			}
+		}
+
+		err = fmt.Errorf("user.repository.RevokeSession: failed to revoke session: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.RevokeSession: failed to revoke session: %w", constant.ErrSessionNotFound)
+		return
+	}
+
+	return
+}
+
+// TouchSession records that the session has just been used, active is false when the session is revoked or unknown
+func (r Repository) TouchSession(ctx context.Context, id string) (active bool, err error) {
+	query := `
+		UPDATE sessions
+		SET lastSeenAt = now()
+		WHERE id = $1 AND revokedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				return false, nil
+			}
+		}
+
+		err = fmt.Errorf("user.repository.TouchSession: failed to touch session: %w", err)
+		return
+	}
+
+	return cmd.RowsAffected() > 0, nil
+}
+
+// GetSessionList returns the sessions of the user that are neither revoked nor expired, the last used first
+func (r Repository) GetSessionList(ctx context.Context, userId string) (data []entity.Session, err error) {
+	query := `
+		SELECT id, userId, expiresAt, device, userAgent, ip, lastSeenAt, createdAt
+		FROM sessions
+		WHERE userId = $1 AND revokedAt IS NULL AND expiresAt > $2
+		ORDER BY lastSeenAt DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userId, time.Now())
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetSessionList: failed to get sessions: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session entity.Session
+		err = rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.ExpiresAt,
+			&session.Device,
+			&session.UserAgent,
+			&session.IP,
+			&session.LastSeenAt,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("user.repository.GetSessionList: failed to scan session: %w", err)
+			return
+		}
+
+		data = append(data, session)
+	}
+
+	return
+}
+
+func (r Repository) CreateFriendRequest(ctx context.Context, data entity.FriendRequest) (err error) {
+	query := `
+		INSERT INTO friend_requests (id, userIdRequester, userIdTarget, status)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err = r.db.Exec(ctx, query, data.ID, data.UserIDRequester, data.UserIDTarget, data.Status)
+	if err != nil {
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLUniqueViolation {
+				err = constant.ErrFriendRequestAlreadySent
+			}
+		}
+
+		err = fmt.Errorf("user.repository.CreateFriendRequest: failed to create friend request: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetFriendRequestByID locks the row, so it is expected to be called within a transaction
+func (r Repository) GetFriendRequestByID(ctx context.Context, id string) (data entity.FriendRequest, err error) {
+	query := `
+		SELECT id, userIdRequester, userIdTarget, status, createdAt, updatedAt
+		FROM friend_requests
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err = r.db.QueryRow(ctx, query, id).Scan(
+		&data.ID,
+		&data.UserIDRequester,
+		&data.UserIDTarget,
+		&data.Status,
+		&data.CreatedAt,
+		&data.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrFriendRequestNotFound
+		}
+
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				err = constant.ErrFriendRequestNotFound
+			}
+		}
+
+		err = fmt.Errorf("user.repository.GetFriendRequestByID: failed to get friend request by id: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetPendingFriendRequest(ctx context.Context, userIdRequester, userIdTarget string) (data entity.FriendRequest, err error) {
+	query := `
+		SELECT id, userIdRequester, userIdTarget, status, createdAt, updatedAt
+		FROM friend_requests
+		WHERE userIdRequester = $1 AND userIdTarget = $2 AND status = $3
+		FOR UPDATE
+	`
+
+	err = r.db.QueryRow(ctx, query, userIdRequester, userIdTarget, entity.FriendRequestStatusPending).Scan(
+		&data.ID,
+		&data.UserIDRequester,
+		&data.UserIDTarget,
+		&data.Status,
+		&data.CreatedAt,
+		&data.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrFriendRequestNotFound
+		}
+
+		err = fmt.Errorf("user.repository.GetPendingFriendRequest: failed to get pending friend request: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetPendingFriendRequestBetween returns the pending friend request sent by either user to the other one
+func (r Repository) GetPendingFriendRequestBetween(ctx context.Context, userIdA, userIdB string) (data entity.FriendRequest, err error) {
+	query := `
+		SELECT id, userIdRequester, userIdTarget, status, createdAt, updatedAt
+		FROM friend_requests
+		WHERE ((userIdRequester = $1 AND userIdTarget = $2) OR (userIdRequester = $2 AND userIdTarget = $1))
+			AND status = $3
+		LIMIT 1
+	`
+
+	err = r.db.QueryRow(ctx, query, userIdA, userIdB, entity.FriendRequestStatusPending).Scan(
+		&data.ID,
+		&data.UserIDRequester,
+		&data.UserIDTarget,
+		&data.Status,
+		&data.CreatedAt,
+		&data.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrFriendRequestNotFound
+		}
+
+		err = fmt.Errorf("user.repository.GetPendingFriendRequestBetween: failed to get pending friend request: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) UpdateFriendRequestStatus(ctx context.Context, id, status string) (err error) {
+	query := `
+		UPDATE friend_requests
+		SET status = $1
+		WHERE id = $2
+	`
+
+	_, err = r.db.Exec(ctx, query, status, id)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UpdateFriendRequestStatus: failed to update friend request status: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetFriendRequestList(ctx context.Context, filter model.FriendRequestGetListRequest) (data []entity.FriendRequest, err error) {
+	userColumn := "userIdRequester"
+	if filter.Incoming {
+		userColumn = "userIdTarget"
+	}
+
+	query := `
+		SELECT COUNT(*) OVER() AS total_count, id, userIdRequester, userIdTarget, status, createdAt, updatedAt
+		FROM friend_requests
+		WHERE ` + userColumn + ` = $1 AND status = $2
+		ORDER BY createdAt DESC
+		LIMIT $3
+		OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.UserID, entity.FriendRequestStatusPending, filter.Limit, filter.Offset)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetFriendRequestList: failed to get list of friend request: %w", err)
+		return
+	}
+
+	for rows.Next() {
+		var friendRequest entity.FriendRequest
+		err = rows.Scan(
+			&friendRequest.Total,
+			&friendRequest.ID,
+			&friendRequest.UserIDRequester,
+			&friendRequest.UserIDTarget,
+			&friendRequest.Status,
+			&friendRequest.CreatedAt,
+			&friendRequest.UpdatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("user.repository.GetFriendRequestList: failed to scan friend request: %w", err)
+			return
+		}
+
+		data = append(data, friendRequest)
+	}
+
+	return
+}
+
+func (r Repository) CreateBlock(ctx context.Context, userIdBlocker, userIdBlocked string) (err error) {
+	query := `
+		INSERT INTO user_blocks (userIdBlocker, userIdBlocked)
+		VALUES ($1, $2)
+	`
+
+	_, err = r.db.Exec(ctx, query, userIdBlocker, userIdBlocked)
+	if err != nil {
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLUniqueViolation {
+				err = constant.ErrUserAlreadyBlocked
+			}
+		}
+
+		err = fmt.Errorf("user.repository.CreateBlock: failed to block user: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) DeleteBlock(ctx context.Context, userIdBlocker, userIdBlocked string) (err error) {
+	query := `
+		DELETE FROM user_blocks
+		WHERE userIdBlocker = $1 AND userIdBlocked = $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, userIdBlocker, userIdBlocked)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DeleteBlock: failed to unblock user: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.DeleteBlock: failed to unblock user: %w", constant.ErrUserNotBlocked)
+		return
+	}
+
+	return
+}
+
+// IsBlocked reports whether one of the users blocked the other
+func (r Repository) IsBlocked(ctx context.Context, userIdA, userIdB string) (isBlocked bool, err error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_blocks
+			WHERE (userIdBlocker = $1 AND userIdBlocked = $2) OR (userIdBlocker = $2 AND userIdBlocked = $1)
+		)
+	`
+
+	err = r.db.QueryRow(ctx, query, userIdA, userIdB).Scan(&isBlocked)
+	if err != nil {
+		err = fmt.Errorf("user.repository.IsBlocked: failed to check is blocked: %w", err)
+		return
+	}
+
+	return
+}
+
+// CancelPendingFriendRequestsBetween cancels the pending friend requests of the users in both directions
+func (r Repository) CancelPendingFriendRequestsBetween(ctx context.Context, userIdA, userIdB string) (err error) {
+	query := `
+		UPDATE friend_requests
+		SET status = $3
+		WHERE status = $4
+			AND ((userIdRequester = $1 AND userIdTarget = $2) OR (userIdRequester = $2 AND userIdTarget = $1))
+	`
+
+	_, err = r.db.Exec(ctx, query, userIdA, userIdB, entity.FriendRequestStatusCancelled, entity.FriendRequestStatusPending)
+	if err != nil {
+		err = fmt.Errorf("user.repository.CancelPendingFriendRequestsBetween: failed to cancel friend requests: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetBlockList(ctx context.Context, filter model.UserRestrictionGetListRequest) (data []entity.User, err error) {
+	query := `
+		SELECT COUNT(*) OVER() AS total_count, u.id, u.name, u.imageUrl, u.friendCount, u.createdAt
+		FROM user_blocks b
+		JOIN users u ON u.id = b.userIdBlocked
+		WHERE b.userIdBlocker = $1
+		ORDER BY b.createdAt DESC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	data, err = r.getRestrictionList(ctx, query, filter)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetBlockList: failed to get list of blocked user: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) CreateMute(ctx context.Context, userIdMuter, userIdMuted string) (err error) {
+	query := `
+		INSERT INTO user_mutes (userIdMuter, userIdMuted)
+		VALUES ($1, $2)
+	`
+
+	_, err = r.db.Exec(ctx, query, userIdMuter, userIdMuted)
+	if err != nil {
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLUniqueViolation {
+				err = constant.ErrUserAlreadyMuted
+			}
+		}
+
+		err = fmt.Errorf("user.repository.CreateMute: failed to mute user: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) DeleteMute(ctx context.Context, userIdMuter, userIdMuted string) (err error) {
+	query := `
+		DELETE FROM user_mutes
+		WHERE userIdMuter = $1 AND userIdMuted = $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, userIdMuter, userIdMuted)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DeleteMute: failed to unmute user: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.DeleteMute: failed to unmute user: %w", constant.ErrUserNotMuted)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetMuteList(ctx context.Context, filter model.UserRestrictionGetListRequest) (data []entity.User, err error) {
+	query := `
+		SELECT COUNT(*) OVER() AS total_count, u.id, u.name, u.imageUrl, u.friendCount, u.createdAt
+		FROM user_mutes m
+		JOIN users u ON u.id = m.userIdMuted
+		WHERE m.userIdMuter = $1
+		ORDER BY m.createdAt DESC
+		LIMIT $2
+		OFFSET $3
+	`
+
+	data, err = r.getRestrictionList(ctx, query, filter)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetMuteList: failed to get list of muted user: %w", err)
+		return
+	}
+
+	return
+}
+
+// getRestrictionList is a helper function to scan the users of the block or mute list
+func (r Repository) getRestrictionList(ctx context.Context, query string, filter model.UserRestrictionGetListRequest) (data []entity.User, err error) {
+	rows, err := r.db.Query(ctx, query, filter.UserID, filter.Limit, filter.Offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user entity.User
+		err = rows.Scan(&user.Total, &user.ID, &user.Name, &user.ImageUrl, &user.FriendCount, &user.CreatedAt)
+		if err != nil {
+			return
+		}
+
+		data = append(data, user)
+	}
+
+	return
+}
+
+func (r Repository) GetPasswordByID(ctx context.Context, id string) (password string, err error) {
+	query := `
+		SELECT password
+		FROM users
+		WHERE id = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, id).Scan(&password)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrUserNotFound
+		}
+
+		err = fmt.Errorf("user.repository.GetPasswordByID: failed to get password: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) UpdatePassword(ctx context.Context, userId, password string) (err error) {
+	query := `
+		UPDATE users
+		SET password = $1
+		WHERE id = $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, password, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UpdatePassword: failed to update password: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.UpdatePassword: failed to update password: %w", constant.ErrUserNotFound)
+		return
+	}
+
+	return
+}
+
+// RevokeUserSessions revokes every active session of the user except exceptID and returns their ids,
+// an empty exceptID revokes all of them
+func (r Repository) RevokeUserSessions(ctx context.Context, userId, exceptID string) (ids []string, err error) {
+	query := `
+		UPDATE sessions
+		SET revokedAt = now()
+		WHERE userId = $1 AND revokedAt IS NULL
+	`
+	args := []interface{}{userId}
+
+	if exceptID != "" {
+		args = append(args, exceptID)
+		query += fmt.Sprintf("AND id <> $%d ", len(args))
+	}
+
+	query += "RETURNING id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("user.repository.RevokeUserSessions: failed to revoke sessions: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			err = fmt.Errorf("user.repository.RevokeUserSessions: failed to scan session id: %w", err)
+			return
+		}
+
+		ids = append(ids, id.String())
+	}
+
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("user.repository.RevokeUserSessions: failed to revoke sessions: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) CreatePasswordReset(ctx context.Context, data entity.PasswordReset) (err error) {
+	query := `
+		INSERT INTO password_resets (id, userId, channel, otpHash, expiresAt, createdAt)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = r.db.Exec(ctx, query, data.ID, data.UserID, data.Channel, data.OTPHash, data.ExpiresAt, data.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("user.repository.CreatePasswordReset: failed to create password reset: %w", err)
+		return
+	}
+
+	return
+}
+
+// ExpirePasswordResets marks the unused resets of the user as used so only a newer otp is accepted
+func (r Repository) ExpirePasswordResets(ctx context.Context, userId string) (err error) {
+	query := `
+		UPDATE password_resets
+		SET usedAt = now()
+		WHERE userId = $1 AND usedAt IS NULL
+	`
+
+	_, err = r.db.Exec(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ExpirePasswordResets: failed to expire password resets: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetPasswordResetStats returns how many resets of the user were created since the time and when the latest one was created
+func (r Repository) GetPasswordResetStats(ctx context.Context, userId string, since time.Time) (count int, latestAt null.Time, err error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE createdAt >= $2), MAX(createdAt)
+		FROM password_resets
+		WHERE userId = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, userId, since).Scan(&count, &latestAt)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetPasswordResetStats: failed to get password reset stats: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetActivePasswordReset(ctx context.Context, userId string) (data entity.PasswordReset, err error) {
+	query := `
+		SELECT id, userId, channel, otpHash, attempts, expiresAt, usedAt, createdAt
+		FROM password_resets
+		WHERE userId = $1 AND usedAt IS NULL
+		ORDER BY createdAt DESC
+		LIMIT 1
+	`
+
+	err = r.db.QueryRow(ctx, query, userId).Scan(
+		&data.ID,
+		&data.UserID,
+		&data.Channel,
+		&data.OTPHash,
+		&data.Attempts,
+		&data.ExpiresAt,
+		&data.UsedAt,
+		&data.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrOTPInvalid
+		}
+
+		err = fmt.Errorf("user.repository.GetActivePasswordReset: failed to get password reset: %w", err)
+		return
+	}
+
+	return
+}
+
+// ConsumePasswordResetAttempt counts an attempt before the otp is compared,
+// it fails once maxAttempts is reached so concurrent guesses cannot go over the limit
+func (r Repository) ConsumePasswordResetAttempt(ctx context.Context, id string, maxAttempts int) (err error) {
+	query := `
+		UPDATE password_resets
+		SET attempts = attempts + 1
+		WHERE id = $1 AND usedAt IS NULL AND attempts < $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id, maxAttempts)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ConsumePasswordResetAttempt: failed to count attempt: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.ConsumePasswordResetAttempt: failed to count attempt: %w", constant.ErrOTPTooManyAttempts)
+		return
+	}
+
+	return
+}
+
+// UsePasswordReset marks the reset as used, it fails when the reset was already used
+func (r Repository) UsePasswordReset(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE password_resets
+		SET usedAt = now()
+		WHERE id = $1 AND usedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UsePasswordReset: failed to use password reset: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.UsePasswordReset: failed to use password reset: %w", constant.ErrOTPInvalid)
+		return
+	}
+
+	return
+}
+
+func (r Repository) CreateVerification(ctx context.Context, data entity.CredentialVerification) (err error) {
+	query := `
+		INSERT INTO credential_verifications (id, userId, channel, value, otpHash, expiresAt, createdAt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.Exec(ctx, query, data.ID, data.UserID, data.Channel, data.Value, data.OTPHash, data.ExpiresAt, data.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("user.repository.CreateVerification: failed to create verification: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetLatestVerification returns the last code sent to the user through the channel, used or not
+func (r Repository) GetLatestVerification(ctx context.Context, userId, channel string) (data entity.CredentialVerification, err error) {
+	query := `
+		SELECT id, userId, channel, value, otpHash, attempts, expiresAt, usedAt, createdAt
+		FROM credential_verifications
+		WHERE userId = $1 AND channel = $2
+		ORDER BY createdAt DESC
+		LIMIT 1
+	`
+
+	data, err = r.scanVerification(r.db.QueryRow(ctx, query, userId, channel))
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetLatestVerification: failed to get verification: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetActiveVerification returns the last unused code sent to the user through the channel,
+// the codes are looked up by user so a code sent to the same address for another user cannot be used or replaced
+func (r Repository) GetActiveVerification(ctx context.Context, userId, channel string) (data entity.CredentialVerification, err error) {
+	query := `
+		SELECT id, userId, channel, value, otpHash, attempts, expiresAt, usedAt, createdAt
+		FROM credential_verifications
+		WHERE userId = $1 AND channel = $2 AND usedAt IS NULL
+		ORDER BY createdAt DESC
+		LIMIT 1
+	`
+
+	data, err = r.scanVerification(r.db.QueryRow(ctx, query, userId, channel))
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetActiveVerification: failed to get verification: %w", err)
+		return
+	}
+
+	return
+}
+
+// GetVerificationStats returns how many codes were sent to the address since the time, for any user,
+// and when the latest one was sent
+func (r Repository) GetVerificationStats(ctx context.Context, channel, value string, since time.Time) (count int, latestAt null.Time, err error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE createdAt >= $3), MAX(createdAt)
+		FROM credential_verifications
+		WHERE channel = $1 AND value = $2
+	`
+
+	err = r.db.QueryRow(ctx, query, channel, value, since).Scan(&count, &latestAt)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetVerificationStats: failed to get verification stats: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) scanVerification(row pgx.Row) (data entity.CredentialVerification, err error) {
+	err = row.Scan(
+		&data.ID,
+		&data.UserID,
+		&data.Channel,
+		&data.Value,
+		&data.OTPHash,
+		&data.Attempts,
+		&data.ExpiresAt,
+		&data.UsedAt,
+		&data.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = constant.ErrVerificationNotFound
+	}
+
+	return
+}
+
+// ExpireVerifications marks the unused codes of the user for the channel as used so only a newer code is accepted
+func (r Repository) ExpireVerifications(ctx context.Context, userId, channel string) (err error) {
+	query := `
+		UPDATE credential_verifications
+		SET usedAt = now()
+		WHERE userId = $1 AND channel = $2 AND usedAt IS NULL
+	`
+
+	_, err = r.db.Exec(ctx, query, userId, channel)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ExpireVerifications: failed to expire verifications: %w", err)
+		return
+	}
+
+	return
+}
+
+// ConsumeVerificationAttempt counts an attempt before the otp is compared,
+// it fails once maxAttempts is reached so concurrent guesses cannot go over the limit
+func (r Repository) ConsumeVerificationAttempt(ctx context.Context, id string, maxAttempts int) (err error) {
+	query := `
+		UPDATE credential_verifications
+		SET attempts = attempts + 1
+		WHERE id = $1 AND usedAt IS NULL AND attempts < $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id, maxAttempts)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ConsumeVerificationAttempt: failed to count attempt: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.ConsumeVerificationAttempt: failed to count attempt: %w", constant.ErrOTPTooManyAttempts)
+		return
+	}
+
+	return
+}
+
+// UseVerification marks the code as used, it fails when the code was already used
+func (r Repository) UseVerification(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE credential_verifications
+		SET usedAt = now()
+		WHERE id = $1 AND usedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UseVerification: failed to use verification: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.UseVerification: failed to use verification: %w", constant.ErrOTPInvalid)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetTOTP(ctx context.Context, userId string) (data entity.UserTOTP, err error) {
+	query := `
+		SELECT userId, secret, enabledAt, lastUsedStep, createdAt
+		FROM user_totps
+		WHERE userId = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, userId).Scan(
+		&data.UserID,
+		&data.Secret,
+		&data.EnabledAt,
+		&data.LastUsedStep,
+		&data.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrTwoFactorNotEnrolled
+		}
+
+		err = fmt.Errorf("user.repository.GetTOTP: failed to get totp: %w", err)
+		return
+	}
+
+	return
+}
+
+// UpsertTOTP stores a new secret for the user, an enabled secret is never replaced
+func (r Repository) UpsertTOTP(ctx context.Context, data entity.UserTOTP) (err error) {
+	query := `
+		INSERT INTO user_totps (userId, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (userId) DO UPDATE
+		SET secret = EXCLUDED.secret, lastUsedStep = 0, createdAt = now()
+		WHERE user_totps.enabledAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, data.UserID, data.Secret)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UpsertTOTP: failed to upsert totp: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.UpsertTOTP: failed to upsert totp: %w", constant.ErrTwoFactorAlreadyEnabled)
+		return
+	}
+
+	return
+}
+
+// EnableTOTP enables the secret of the user, step is the time step of the code that proved the secret works
+func (r Repository) EnableTOTP(ctx context.Context, userId string, step int64) (err error) {
+	query := `
+		UPDATE user_totps
+		SET enabledAt = now(), lastUsedStep = $2
+		WHERE userId = $1 AND enabledAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, userId, step)
+	if err != nil {
+		err = fmt.Errorf("user.repository.EnableTOTP: failed to enable totp: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.EnableTOTP: failed to enable totp: %w", constant.ErrTwoFactorAlreadyEnabled)
+		return
+	}
+
+	return
+}
+
+// UseTOTPStep records the time step of an accepted code,
+// it fails when a code of the same or a later step was already accepted so a code cannot be replayed
+func (r Repository) UseTOTPStep(ctx context.Context, userId string, step int64) (err error) {
+	query := `
+		UPDATE user_totps
+		SET lastUsedStep = $2
+		WHERE userId = $1 AND enabledAt IS NOT NULL AND lastUsedStep < $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, userId, step)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UseTOTPStep: failed to use totp step: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.UseTOTPStep: failed to use totp step: %w", constant.ErrTwoFactorCodeInvalid)
+		return
+	}
+
+	return
+}
+
+func (r Repository) DeleteTOTP(ctx context.Context, userId string) (err error) {
+	query := `
+		DELETE FROM user_totps
+		WHERE userId = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DeleteTOTP: failed to delete totp: %w", err)
+		return
+	}
+
+	return
+}
+
+// ReplaceRecoveryCodes removes every recovery code of the user before storing the new hashes
+func (r Repository) ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) (err error) {
+	err = r.DeleteRecoveryCodes(ctx, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ReplaceRecoveryCodes: %w", err)
+		return
+	}
+
+	query := `
+		INSERT INTO user_recovery_codes (userId, codeHash)
+		SELECT $1, unnest($2::TEXT[])
+	`
+
+	_, err = r.db.Exec(ctx, query, userId, codeHashes)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ReplaceRecoveryCodes: failed to create recovery codes: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) DeleteRecoveryCodes(ctx context.Context, userId string) (err error) {
+	query := `
+		DELETE FROM user_recovery_codes
+		WHERE userId = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DeleteRecoveryCodes: failed to delete recovery codes: %w", err)
+		return
+	}
+
+	return
+}
+
+// UseRecoveryCode marks the recovery code as used, it fails when the code is unknown or already used
+func (r Repository) UseRecoveryCode(ctx context.Context, userId, codeHash string) (err error) {
+	query := `
+		UPDATE user_recovery_codes
+		SET usedAt = now()
+		WHERE userId = $1 AND codeHash = $2 AND usedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, userId, codeHash)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UseRecoveryCode: failed to use recovery code: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.UseRecoveryCode: failed to use recovery code: %w", constant.ErrTwoFactorCodeInvalid)
+		return
+	}
+
+	return
+}
+
+func (r Repository) CreateLoginChallenge(ctx context.Context, data entity.LoginChallenge) (err error) {
+	query := `
+		INSERT INTO login_challenges (id, userId, credentialKey, expiresAt)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err = r.db.Exec(ctx, query, data.ID, data.UserID, data.CredentialKey, data.ExpiresAt)
+	if err != nil {
+		err = fmt.Errorf("user.repository.CreateLoginChallenge: failed to create login challenge: %w", err)
+		return
+	}
+
+	return
+}
+
+func (r Repository) GetLoginChallenge(ctx context.Context, id string) (data entity.LoginChallenge, err error) {
+	query := `
+		SELECT id, userId, credentialKey, attempts, expiresAt, usedAt, createdAt
+		FROM login_challenges
+		WHERE id = $1
+	`
+
+	err = r.db.QueryRow(ctx, query, id).Scan(
+		&data.ID,
+		&data.UserID,
+		&data.CredentialKey,
+		&data.Attempts,
+		&data.ExpiresAt,
+		&data.UsedAt,
+		&data.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrLoginChallengeInvalid
+		}
+
+		var pgxError *pgconn.PgError
+		if errors.As(err, &pgxError) {
+			if pgxError.Code == constant.ErrSQLInvalidUUID {
+				err = constant.ErrLoginChallengeInvalid
+			}
+		}
+
+		err = fmt.Errorf("user.repository.GetLoginChallenge: failed to get login challenge: %w", err)
+		return
+	}
+
+	return
+}
+
+// ConsumeLoginChallengeAttempt counts an attempt before the code is checked,
+// it fails once maxAttempts is reached so concurrent guesses cannot go over the limit
+func (r Repository) ConsumeLoginChallengeAttempt(ctx context.Context, id string, maxAttempts int) (err error) {
+	query := `
+		UPDATE login_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1 AND usedAt IS NULL AND attempts < $2
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id, maxAttempts)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ConsumeLoginChallengeAttempt: failed to count attempt: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.ConsumeLoginChallengeAttempt: failed to count attempt: %w", constant.ErrOTPTooManyAttempts)
+		return
+	}
+
+	return
+}
+
+// UseLoginChallenge marks the challenge as used, it fails when the challenge was already used
+func (r Repository) UseLoginChallenge(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE login_challenges
+		SET usedAt = now()
+		WHERE id = $1 AND usedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("user.repository.UseLoginChallenge: failed to use login challenge: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.UseLoginChallenge: failed to use login challenge: %w", constant.ErrLoginChallengeInvalid)
+		return
+	}
+
+	return
+}
+
+// ScheduleDeletion sets when the deletion worker deletes the account
+func (r Repository) ScheduleDeletion(ctx context.Context, userId string, scheduledAt time.Time) (err error) {
+	query := `
+		UPDATE users
+		SET deletionScheduledAt = $1
+		WHERE id = $2 AND deletedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, scheduledAt, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.ScheduleDeletion: failed to schedule deletion: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.ScheduleDeletion: failed to schedule deletion: %w", constant.ErrUserNotFound)
+		return
+	}
+
+	return
+}
+
+// CancelDeletion unschedules the deletion, it fails when the worker already deleted the account
+func (r Repository) CancelDeletion(ctx context.Context, userId string) (err error) {
+	query := `
+		UPDATE users
+		SET deletionScheduledAt = NULL
+		WHERE id = $1 AND deletedAt IS NULL
+	`
+
+	cmd, err := r.db.Exec(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.CancelDeletion: failed to cancel deletion: %w", err)
+		return
+	}
+
+	if cmd.RowsAffected() == 0 {
+		err = fmt.Errorf("user.repository.CancelDeletion: failed to cancel deletion: %w", constant.ErrUserNotFound)
+		return
+	}
+
+	return
+}
+
+// GetDueDeletionIDs returns the accounts whose deletion is scheduled at or before now, the oldest first
+func (r Repository) GetDueDeletionIDs(ctx context.Context, now time.Time, limit int) (ids []string, err error) {
+	query := `
+		SELECT id
+		FROM users
+		WHERE deletionScheduledAt <= $1 AND deletedAt IS NULL
+		ORDER BY deletionScheduledAt ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, now, limit)
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetDueDeletionIDs: failed to get due deletions: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	ids = []string{}
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			err = fmt.Errorf("user.repository.GetDueDeletionIDs: failed to scan user id: %w", err)
+			return
+		}
+
+		ids = append(ids, id.String())
+	}
+
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("user.repository.GetDueDeletionIDs: failed to get due deletions: %w", err)
+		return
+	}
+
+	return
+}
+
+// LockDueDeletion locks the account when its deletion is still due, it is expected to be called within a transaction.
+// Accounts locked by another worker are skipped as not found. The image url is only returned when no other user uses it,
+// so the caller can remove the file from the storage.
+func (r Repository) LockDueDeletion(ctx context.Context, userId string, now time.Time) (imageUrl null.String, err error) {
+	query := `
+		SELECT CASE
+			WHEN EXISTS (SELECT 1 FROM users o WHERE o.imageUrl = u.imageUrl AND o.id <> u.id) THEN NULL
+			ELSE u.imageUrl
+		END
+		FROM users u
+		WHERE u.id = $1 AND u.deletionScheduledAt <= $2 AND u.deletedAt IS NULL
+		FOR UPDATE SKIP LOCKED
+	`
+
+	err = r.db.QueryRow(ctx, query, userId, now).Scan(&imageUrl)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = constant.ErrUserNotFound
+		}
+
+		err = fmt.Errorf("user.repository.LockDueDeletion: failed to lock user: %w", err)
+		return
+	}
+
+	return
+}
+
+// DeleteReactionsOfUser removes the reactions of the user and takes them out of the denormalized counts
+func (r Repository) DeleteReactionsOfUser(ctx context.Context, userId string) (err error) {
+	// a user reacts at most once per post and per comment
+	queries := map[string]string{
+		"post": `
+			WITH removed AS (
+				DELETE FROM post_reactions
+				WHERE userId = $1
+				RETURNING postId, type
+			)
+			UPDATE posts p
+			SET likeCount = GREATEST(p.likeCount - (r.type = 'like')::INT, 0),
+				loveCount = GREATEST(p.loveCount - (r.type = 'love')::INT, 0),
+				hahaCount = GREATEST(p.hahaCount - (r.type = 'haha')::INT, 0),
+				wowCount = GREATEST(p.wowCount - (r.type = 'wow')::INT, 0),
+				sadCount = GREATEST(p.sadCount - (r.type = 'sad')::INT, 0),
+				angryCount = GREATEST(p.angryCount - (r.type = 'angry')::INT, 0)
+			FROM removed r
+			WHERE p.id = r.postId
+		`,
+		"comment": `
+			WITH removed AS (
+				DELETE FROM post_comment_reactions
+				WHERE userId = $1
+				RETURNING commentId, type
+			)
+			UPDATE post_comments c
+			SET likeCount = GREATEST(c.likeCount - (r.type = 'like')::INT, 0),
+				loveCount = GREATEST(c.loveCount - (r.type = 'love')::INT, 0),
+				hahaCount = GREATEST(c.hahaCount - (r.type = 'haha')::INT, 0),
+				wowCount = GREATEST(c.wowCount - (r.type = 'wow')::INT, 0),
+				sadCount = GREATEST(c.sadCount - (r.type = 'sad')::INT, 0),
+				angryCount = GREATEST(c.angryCount - (r.type = 'angry')::INT, 0)
+			FROM removed r
+			WHERE c.id = r.commentId
+		`,
+	}
+
+	for target, query := range queries {
+		_, err = r.db.Exec(ctx, query, userId)
+		if err != nil {
+			err = fmt.Errorf("user.repository.DeleteReactionsOfUser: failed to delete %s reactions: %w", target, err)
+			return
+		}
+	}
+
+	return
+}
+
+// DeleteFriendsOfUser removes every friendship of the user and decrements the friend count of the friends
+func (r Repository) DeleteFriendsOfUser(ctx context.Context, userId string) (err error) {
+	query := `
+		WITH removed AS (
+			DELETE FROM friends
+			WHERE userIdAdder = $1 OR userIdAdded = $1
+			RETURNING CASE WHEN userIdAdder = $1 THEN userIdAdded ELSE userIdAdder END AS friendId
+		)
+		UPDATE users
+		SET friendCount = GREATEST(friendCount - 1, 0)
+		WHERE id IN (SELECT friendId FROM removed)
+	`
+
+	_, err = r.db.Exec(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DeleteFriendsOfUser: failed to delete friends: %w", err)
+		return
+	}
+
+	return
+}
+
+// DecrementTagsOfUser takes the uses of the public posts of the user out of the tag autocomplete,
+// the tags left without a use are removed. It is expected to be called before the posts are deleted
+func (r Repository) DecrementTagsOfUser(ctx context.Context, userId string) (err error) {
+	query := `
+		WITH tag_uses AS (
+			SELECT tag, COUNT(*) AS uses
+			FROM posts, unnest(posts.tags) AS tag
+			WHERE posts.userId = $1 AND posts.visibility = $2
+			GROUP BY tag
+		), decremented AS (
+			UPDATE tags t
+			SET useCount = GREATEST(t.useCount - u.uses, 0)
+			FROM tag_uses u
+			WHERE t.name = u.tag
+			RETURNING t.name, t.useCount
+		)
+		SELECT name FROM decremented WHERE useCount = 0
+	`
+
+	rows, err := r.db.Query(ctx, query, userId, entity.PostVisibilityPublic)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DecrementTagsOfUser: failed to decrement tags: %w", err)
+		return
+	}
+	defer rows.Close()
+
+	unused := []string{}
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			err = fmt.Errorf("user.repository.DecrementTagsOfUser: failed to scan tag: %w", err)
+			return
+		}
+
+		unused = append(unused, name)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		err = fmt.Errorf("user.repository.DecrementTagsOfUser: failed to decrement tags: %w", err)
+		return
+	}
+
+	if len(unused) == 0 {
+		return
+	}
+
+	query = `
+		DELETE FROM tags
+		WHERE name = ANY($1) AND useCount = 0
+	`
+
+	_, err = r.db.Exec(ctx, query, unused)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DecrementTagsOfUser: failed to delete unused tags: %w", err)
+		return
+	}
+
+	return
+}
+
+// DeletePostsOfUser removes the posts of the user, their comments, reactions and mentions are removed with them
+func (r Repository) DeletePostsOfUser(ctx context.Context, userId string) (err error) {
+	query := `
+		DELETE FROM posts
+		WHERE userId = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DeletePostsOfUser: failed to delete posts: %w", err)
+		return
+	}
+
+	return
+}
+
+// DeleteUserData removes the rows only the user had a use for: sessions, credentials, friend requests,
+// restrictions, mentions and notifications sent to or caused by the user
+func (r Repository) DeleteUserData(ctx context.Context, userId string) (err error) {
+	query := `
+		WITH sessions_removed AS (
+			DELETE FROM sessions WHERE userId = $1
+		), totps_removed AS (
+			DELETE FROM user_totps WHERE userId = $1
+		), recovery_codes_removed AS (
+			DELETE FROM user_recovery_codes WHERE userId = $1
+		), login_challenges_removed AS (
+			DELETE FROM login_challenges WHERE userId = $1
+		), password_resets_removed AS (
+			DELETE FROM password_resets WHERE userId = $1
+		), verifications_removed AS (
+			DELETE FROM credential_verifications WHERE userId = $1
+		), friend_requests_removed AS (
+			DELETE FROM friend_requests WHERE userIdRequester = $1 OR userIdTarget = $1
+		), blocks_removed AS (
+			DELETE FROM user_blocks WHERE userIdBlocker = $1 OR userIdBlocked = $1
+		), mutes_removed AS (
+			DELETE FROM user_mutes WHERE userIdMuter = $1 OR userIdMuted = $1
+		), mentions_removed AS (
+			DELETE FROM post_mentions WHERE userId = $1
+		)
+		DELETE FROM notifications
+		WHERE userId = $1 OR actorId = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.DeleteUserData: failed to delete user data: %w", err)
+		return
+	}
+
+	return
+}
+
+// Anonymize clears the personal data of the user and marks it deleted, the row is kept as the author of its comments
+func (r Repository) Anonymize(ctx context.Context, userId, name string) (err error) {
+	query := `
+		UPDATE users
+		SET name = $1,
+			email = NULL,
+			phone = NULL,
+			password = '',
+			imageUrl = NULL,
+			emailVerifiedAt = NULL,
+			phoneVerifiedAt = NULL,
+			friendCount = 0,
+			notificationOptOut = '{}',
+			deletionScheduledAt = NULL,
+			deletedAt = now()
+		WHERE id = $2
+	`
+
+	_, err = r.db.Exec(ctx, query, name, userId)
+	if err != nil {
+		err = fmt.Errorf("user.repository.Anonymize: failed to anonymize user: %w", err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,49 @@
+package userrepo_test
+
+import (
+	"context"
+	"testing"
+
+	userrepo "github.com/arfan21/project-sprint-social-media-api/internal/user/repository"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres/postgrestest"
+)
+
+func TestGetMentionCandidatesLeavesBlockedUsersOut(t *testing.T) {
+	ctx := context.Background()
+	tx := postgrestest.BeginTx(ctx, t)
+	repo := userrepo.New(tx)
+
+	ownerID := postgrestest.CreateUser(ctx, t, tx, "test mention owner")
+	friendID := postgrestest.CreateUser(ctx, t, tx, "test mention friend")
+	commenterID := postgrestest.CreateUser(ctx, t, tx, "test mention commenter")
+
+	err := repo.AddFriend(ctx, ownerID, friendID)
+	if err != nil {
+		t.Fatalf("failed to add friend: %v", err)
+	}
+
+	// the friend of the owner blocked the user commenting on the post of the owner
+	postgrestest.Block(ctx, t, tx, friendID, commenterID)
+
+	tests := []struct {
+		name     string
+		authorID string
+		want     int
+	}{
+		{name: "owner mentions the friend", authorID: ownerID, want: 1},
+		{name: "blocked commenter cannot mention the friend", authorID: commenterID, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := repo.GetMentionCandidates(ctx, ownerID, tt.authorID, []string{"testmentionfriend"}, true)
+			if err != nil {
+				t.Fatalf("GetMentionCandidates() error = %v", err)
+			}
+
+			if len(data) != tt.want {
+				t.Errorf("GetMentionCandidates() returned %d users, want %d", len(data), tt.want)
+			}
+		})
+	}
+}
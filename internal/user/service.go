@@ -4,17 +4,50 @@ import (
 	"context"
 
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/jwtkey"
 )
 
 type Service interface {
 	Register(ctx context.Context, req model.UserRegisterRequest) (res model.UserLoginResponse, err error)
 	Login(ctx context.Context, req model.UserLoginRequest) (res model.UserLoginResponse, err error)
-	AddFriend(ctx context.Context, req model.FriendRequest) (err error)
+	AddFriend(ctx context.Context, req model.FriendRequest) (res model.FriendRequestResponse, err error)
 	DeleteFriend(ctx context.Context, req model.FriendRequest) (err error)
-	GetList(ctx context.Context, req model.UserGetListRequest) (res []model.UserResponse, count int, err error)
+	AcceptFriendRequest(ctx context.Context, req model.FriendRequestActionRequest) (err error)
+	RejectFriendRequest(ctx context.Context, req model.FriendRequestActionRequest) (err error)
+	CancelFriendRequest(ctx context.Context, req model.FriendRequestActionRequest) (err error)
+	GetFriendRequestList(ctx context.Context, req model.FriendRequestGetListRequest) (res []model.FriendRequestResponse, count int, err error)
+	GetProfile(ctx context.Context, req model.UserProfileRequest) (res model.UserProfileResponse, err error)
+	GetList(ctx context.Context, req model.UserGetListRequest) (res []model.UserResponse, nextCursor, prevCursor string, count int, err error)
 	IsFriend(ctx context.Context, userIdAdder, userIdAdded string) (isFriend bool, err error)
+	GetFriendIDs(ctx context.Context, userID string) (ids []string, err error)
 	GetListMap(ctx context.Context, req model.UserGetListRequest) (data map[string]model.UserResponse, err error)
+	ResolveMentions(ctx context.Context, req model.MentionResolveRequest) (res map[string]string, err error)
 	UpdatePhone(ctx context.Context, req model.UserPhoneUpdateRequest) (err error)
 	UpdateEmail(ctx context.Context, req model.UserEmailUpdateRequest) (err error)
 	UpdateProfile(ctx context.Context, req model.UserProfileUpdateRequest) (err error)
+	RefreshToken(ctx context.Context, req model.UserRefreshTokenRequest) (res model.UserLoginResponse, err error)
+	Logout(ctx context.Context, req model.UserLogoutRequest) (err error)
+	IsSessionRevoked(ctx context.Context, sessionID string) (isRevoked bool, err error)
+	Block(ctx context.Context, req model.UserBlockRequest) (err error)
+	Unblock(ctx context.Context, req model.UserBlockRequest) (err error)
+	IsBlocked(ctx context.Context, userIdA, userIdB string) (isBlocked bool, err error)
+	GetBlockList(ctx context.Context, req model.UserRestrictionGetListRequest) (res []model.UserResponse, count int, err error)
+	Mute(ctx context.Context, req model.UserMuteRequest) (err error)
+	Unmute(ctx context.Context, req model.UserMuteRequest) (err error)
+	GetMuteList(ctx context.Context, req model.UserRestrictionGetListRequest) (res []model.UserResponse, count int, err error)
+	UpdatePassword(ctx context.Context, req model.UserPasswordUpdateRequest) (err error)
+	ForgotPassword(ctx context.Context, req model.UserPasswordForgotRequest) (err error)
+	ResetPassword(ctx context.Context, req model.UserPasswordResetRequest) (err error)
+	VerifyCredential(ctx context.Context, req model.UserVerifyRequest) (err error)
+	ResendVerification(ctx context.Context, req model.UserVerificationResendRequest) (err error)
+	EnrollTwoFactor(ctx context.Context, req model.UserTwoFactorEnrollRequest) (res model.UserTwoFactorEnrollResponse, err error)
+	EnableTwoFactor(ctx context.Context, req model.UserTwoFactorEnableRequest) (res model.UserTwoFactorEnableResponse, err error)
+	DisableTwoFactor(ctx context.Context, req model.UserTwoFactorDisableRequest) (err error)
+	LoginTwoFactor(ctx context.Context, req model.UserLoginTwoFactorRequest) (res model.UserLoginResponse, err error)
+	JWKS() jwtkey.JWKS
+	GetSessionList(ctx context.Context, req model.UserSessionGetListRequest) (res []model.UserSessionResponse, err error)
+	RevokeSession(ctx context.Context, req model.UserSessionRevokeRequest) (err error)
+	LogoutAll(ctx context.Context, req model.UserLogoutRequest) (err error)
+	DeleteAccount(ctx context.Context, req model.UserDeleteRequest) (res model.UserDeleteResponse, err error)
+	DeleteDueAccounts(ctx context.Context) (err error)
 }
@@ -0,0 +1,234 @@
+package usersvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/guregu/null.v4"
+)
+
+// deletedUserName replaces the name of a deleted account, its comments on the posts of other users are kept under it
+const deletedUserName = "Deleted user"
+
+// DeleteAccount schedules the deletion of the account once the grace period is over and logs out every session,
+// logging in again during the grace period cancels the deletion
+func (s Service) DeleteAccount(ctx context.Context, req model.UserDeleteRequest) (res model.UserDeleteResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteAccount: failed to validate request: %w", err)
+		return
+	}
+
+	password, err := s.repo.GetPasswordByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteAccount: failed to get password: %w", err)
+		return
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(password), []byte(req.Password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			err = constant.ErrPasswordInvalid
+		}
+		err = fmt.Errorf("user.service.DeleteAccount: failed to compare password: %w", err)
+		return
+	}
+
+	var revokedIDs []string
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		s.sessions.forget(revokedIDs...)
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteAccount: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.DeleteAccount: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.DeleteAccount: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	scheduledAt := time.Now().Add(time.Duration(config.Get().AccountDeletion.GracePeriod) * time.Second)
+
+	err = s.repo.WithTx(tx).ScheduleDeletion(ctx, req.UserID, scheduledAt)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteAccount: failed to schedule deletion: %w", err)
+		return
+	}
+
+	revokedIDs, err = s.repo.WithTx(tx).RevokeUserSessions(ctx, req.UserID, "")
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteAccount: failed to revoke sessions: %w", err)
+		return
+	}
+
+	res.DeletionScheduledAt = scheduledAt.Format(constant.TimeISO8601Format)
+
+	return
+}
+
+// DeleteDueAccounts deletes a batch of the accounts whose grace period is over,
+// an account that fails is logged and retried on the next run
+func (s Service) DeleteDueAccounts(ctx context.Context) (err error) {
+	ids, err := s.repo.GetDueDeletionIDs(ctx, time.Now(), config.Get().AccountDeletion.BatchSize)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteDueAccounts: failed to get due deletions: %w", err)
+		return
+	}
+
+	for _, id := range ids {
+		errDelete := s.deleteAccount(ctx, id)
+		if errDelete != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			logger.Log(ctx).Error().Err(errDelete).Str("user_id", id).Msg("user.service.DeleteDueAccounts: failed to delete account")
+		}
+	}
+
+	return
+}
+
+// RunDeletionWorker deletes the due accounts right away and then every interval until the context is done
+func (s Service) RunDeletionWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.DeleteDueAccounts(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Log(ctx).Error().Err(err).Msg("user.service.RunDeletionWorker: failed to delete due accounts")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteAccount deletes the posts, with their tag uses, reactions, friendships and private data of the account and anonymizes the user row.
+// The comments on the posts of other users are kept so their threads stay intact, they are shown under deletedUserName.
+// The account is skipped when its deletion was cancelled meanwhile or another replica is deleting it.
+func (s Service) deleteAccount(ctx context.Context, userID string) (err error) {
+	var imageUrl null.String
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err == nil && imageUrl.Valid {
+			s.deleteImage(ctx, imageUrl.String)
+		}
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.deleteAccount: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.deleteAccount: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.deleteAccount: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	imageUrl, err = repo.LockDueDeletion(ctx, userID, time.Now())
+	if err != nil {
+		if errors.Is(err, constant.ErrUserNotFound) {
+			return nil
+		}
+
+		err = fmt.Errorf("user.service.deleteAccount: failed to lock user: %w", err)
+		return
+	}
+
+	err = repo.DeleteReactionsOfUser(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("user.service.deleteAccount: failed to delete reactions: %w", err)
+		return
+	}
+
+	err = repo.DeleteFriendsOfUser(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("user.service.deleteAccount: failed to delete friends: %w", err)
+		return
+	}
+
+	err = repo.DecrementTagsOfUser(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("user.service.deleteAccount: failed to decrement tags: %w", err)
+		return
+	}
+
+	err = repo.DeletePostsOfUser(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("user.service.deleteAccount: failed to delete posts: %w", err)
+		return
+	}
+
+	err = repo.DeleteUserData(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("user.service.deleteAccount: failed to delete user data: %w", err)
+		return
+	}
+
+	err = repo.Anonymize(ctx, userID, deletedUserName)
+	if err != nil {
+		err = fmt.Errorf("user.service.deleteAccount: failed to anonymize user: %w", err)
+		return
+	}
+
+	return
+}
+
+// deleteImage removes the uploaded profile image on a best effort basis, images hosted elsewhere are left alone
+func (s Service) deleteImage(ctx context.Context, imageUrl string) {
+	key, ok := s.storage.KeyFromURL(imageUrl)
+	if !ok {
+		return
+	}
+
+	err := s.storage.Delete(ctx, key)
+	if err != nil {
+		logger.Log(ctx).Warn().Err(err).Str("key", key).Msg("user.service.deleteAccount: failed to delete profile image")
+	}
+}
@@ -2,15 +2,34 @@ package usersvc
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/arfan21/project-sprint-social-media-api/config"
 	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/notification"
 	"github.com/arfan21/project-sprint-social-media-api/internal/user"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/cursor"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/jwtkey"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/loginguard"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/mention"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/notifier"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/otp"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/pubsub"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/storage"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -19,11 +38,61 @@ import (
 )
 
 type Service struct {
-	repo user.Repository
+	repo            user.Repository
+	notificationSvc notification.Service
+	publisher       pubsub.Publisher
+	notifier        notifier.Notifier
+	loginGuard      *loginguard.Guard
+	jwtKeys         *jwtkey.KeySet
+	storage         storage.Storage
+	sessions        *sessionCache
 }
 
-func New(repo user.Repository) *Service {
-	return &Service{repo: repo}
+func New(
+	repo user.Repository,
+	notificationSvc notification.Service,
+	publisher pubsub.Publisher,
+	notifier notifier.Notifier,
+	loginGuard *loginguard.Guard,
+	jwtKeys *jwtkey.KeySet,
+	fileStorage storage.Storage,
+) *Service {
+	return &Service{
+		repo:            repo,
+		notificationSvc: notificationSvc,
+		publisher:       publisher,
+		notifier:        notifier,
+		loginGuard:      loginGuard,
+		jwtKeys:         jwtKeys,
+		storage:         fileStorage,
+		sessions:        newSessionCache(time.Duration(config.Get().Session.CacheTTL) * time.Second),
+	}
+}
+
+// JWKS returns the public keys that verify the access tokens
+func (s Service) JWKS() jwtkey.JWKS {
+	return s.jwtKeys.JWKS()
+}
+
+// notify sends the notification on a best effort basis,
+// a failure is only logged so it never fails the action of the user
+func (s Service) notify(ctx context.Context, req model.NotificationCreateRequest) {
+	err := s.notificationSvc.Notify(ctx, req)
+	if err != nil {
+		logger.Log(ctx).Error().Err(err).Str("type", req.Type).Msg("failed to send notification")
+	}
+}
+
+// publish pushes the event to the stream of the users on a best effort basis
+func (s Service) publish(ctx context.Context, eventType string, userIDs []string, data any) {
+	msg, err := pubsub.NewMessage(eventType, userIDs, data)
+	if err == nil {
+		err = s.publisher.Publish(ctx, msg)
+	}
+
+	if err != nil {
+		logger.Log(ctx).Error().Err(err).Str("type", eventType).Msg("failed to publish event")
+	}
 }
 
 func (s Service) Register(ctx context.Context, req model.UserRegisterRequest) (res model.UserLoginResponse, err error) {
@@ -33,12 +102,7 @@ func (s Service) Register(ctx context.Context, req model.UserRegisterRequest) (r
 		return
 	}
 
-	cost := bcrypt.DefaultCost
-	if config.Get().Bcrypt.Salt > 0 {
-		cost = config.Get().Bcrypt.Salt
-	}
-
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), cost)
+	hashedPassword, err := hashPassword(req.Password)
 	if err != nil {
 		err = fmt.Errorf("user.service.Register: failed to hash password: %w", err)
 		return
@@ -50,17 +114,43 @@ func (s Service) Register(ctx context.Context, req model.UserRegisterRequest) (r
 		return
 	}
 
+	// the credential is only stored in the user once it is verified, so a credential taken by another user
+	// is refused here while a credential waiting to be verified by another user does not block its owner
+	_, err = s.repo.GetByCredential(ctx, req.CredentialType, req.CredentialValue)
+	if err == nil {
+		err = constant.ErrEmailAlreadyRegistered
+		if req.CredentialType == "phone" {
+			err = constant.ErrPhoneAlreadyRegistered
+		}
+		err = fmt.Errorf("user.service.Register: credential taken, %w", err)
+		return
+	}
+
+	if !errors.Is(err, constant.ErrUserNotFound) {
+		err = fmt.Errorf("user.service.Register: failed to get user by credential: %w", err)
+		return
+	}
+
 	data := entity.User{
 		ID:       id,
 		Name:     req.Name,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 	}
 
-	if req.CredentialType == "email" {
-		data.Email = null.StringFrom(req.CredentialValue)
-	} else {
-		data.Phone = null.StringFrom(req.CredentialValue)
-	}
+	var code string
+
+	// registered before the transaction defer so it only runs after the commit,
+	// the user can ask for a new code when the delivery fails so it does not fail the registration
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		errSend := s.sendVerification(ctx, req.CredentialType, req.CredentialValue, code)
+		if errSend != nil {
+			logger.Log(ctx).Error().Err(errSend).Msg("user.service.Register: failed to send verification code")
+		}
+	}()
 
 	tx, err := s.repo.Begin(ctx)
 	if err != nil {
@@ -91,9 +181,30 @@ func (s Service) Register(ctx context.Context, req model.UserRegisterRequest) (r
 		return
 	}
 
-	return s.login(data, true)
+	code, err = s.createVerification(ctx, s.repo.WithTx(tx), data.ID, req.CredentialType, req.CredentialValue)
+	if err != nil {
+		err = fmt.Errorf("user.service.Register: %w", err)
+		return
+	}
+
+	res, err = s.login(ctx, s.repo.WithTx(tx), data, req.Client, loginguard.CredentialKey(req.CredentialType, req.CredentialValue), true)
+	if err != nil {
+		err = fmt.Errorf("user.service.Register: %w", err)
+		return
+	}
+
+	// the response shows the credential waiting to be verified
+	if req.CredentialType == "email" {
+		res.Email = &req.CredentialValue
+	} else {
+		res.Phone = &req.CredentialValue
+	}
+
+	return
 }
 
+// Login checks the credential and password of the user, a credential is only stored in the user
+// once verified so a credential still waiting for its verification is rejected as unknown
 func (s Service) Login(ctx context.Context, req model.UserLoginRequest) (res model.UserLoginResponse, err error) {
 	err = validation.Validate(req)
 	if err != nil {
@@ -101,10 +212,22 @@ func (s Service) Login(ctx context.Context, req model.UserLoginRequest) (res mod
 		return
 	}
 
+	credentialKey := loginguard.CredentialKey(req.CredentialType, req.CredentialValue)
+	guardKeys := loginGuardKeys(credentialKey, req.Client.IP)
+
+	err = s.checkLogin(ctx, guardKeys)
+	if err != nil {
+		err = fmt.Errorf("user.service.Login: %w", err)
+		return
+	}
+
 	var data entity.User
 
 	data, err = s.repo.GetByCredential(ctx, req.CredentialType, req.CredentialValue)
 	if err != nil {
+		if errors.Is(err, constant.ErrUserNotFound) {
+			s.failLogin(ctx, guardKeys)
+		}
 		err = fmt.Errorf("user.service.Login: failed to get user by phone: %w", err)
 		return
 	}
@@ -112,22 +235,106 @@ func (s Service) Login(ctx context.Context, req model.UserLoginRequest) (res mod
 	err = bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(req.Password))
 	if err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			s.failLogin(ctx, guardKeys)
 			err = constant.ErrUsernameOrPasswordInvalid
 		}
 		err = fmt.Errorf("user.service.Login: failed to compare password: %w", err)
 		return
 	}
 
-	return s.login(data, false)
+	if !credentialVerified(data, req.CredentialType) {
+		err = fmt.Errorf("user.service.Login: %w", constant.ErrCredentialNotVerified)
+		return
+	}
+
+	// the failed attempts are only forgotten once the second factor is verified too
+	if data.TOTPEnabledAt.Valid {
+		return s.createLoginChallenge(ctx, data, credentialKey)
+	}
+
+	return s.login(ctx, s.repo, data, req.Client, credentialKey, false)
+}
+
+// loginGuardKeys returns the keys the failed logins are counted for
+func loginGuardKeys(credentialKey loginguard.Key, ip string) (keys []loginguard.Key) {
+	keys = []loginguard.Key{credentialKey}
+	if ip != "" {
+		keys = append(keys, loginguard.IPKey(ip))
+	}
+
+	return
+}
+
+// checkLogin refuses the login while one of the keys is delayed or locked by the failed attempts
+func (s Service) checkLogin(ctx context.Context, keys []loginguard.Key) (err error) {
+	retryAfter, err := s.loginGuard.Check(ctx, keys...)
+	if err != nil {
+		err = fmt.Errorf("user.service.checkLogin: failed to check login attempts: %w", err)
+		return
+	}
+
+	if retryAfter > 0 {
+		seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+		err = fmt.Errorf("user.service.checkLogin: %w", constant.ErrLoginTooManyAttempts.WithHeader("Retry-After", seconds))
+		return
+	}
+
+	return
+}
+
+// failLogin counts the failed login on a best effort basis, the caller already fails with the login error
+func (s Service) failLogin(ctx context.Context, keys []loginguard.Key) {
+	err := s.loginGuard.Fail(ctx, keys...)
+	if err != nil {
+		logger.Log(ctx).Error().Err(err).Msg("user.service.failLogin: failed to count failed login")
+	}
 }
 
-func (s Service) login(data entity.User, isRegister bool) (res model.UserLoginResponse, err error) {
+// login starts a new session for the user and issues its first access and refresh token pair,
+// repo is passed explicitly so register can create the session inside its transaction.
+// The failed attempts of the credential are forgotten once the session is created
+func (s Service) login(ctx context.Context, repo user.Repository, data entity.User, client model.SessionClient, credentialKey loginguard.Key, isRegister bool) (res model.UserLoginResponse, err error) {
+	// logging in during the grace period keeps the account
+	if data.DeletionScheduledAt.Valid {
+		err = repo.CancelDeletion(ctx, data.ID.String())
+		if err != nil {
+			err = fmt.Errorf("user.service.login: failed to cancel deletion: %w", err)
+			return
+		}
+	}
+
+	sessionID, err := uuid.NewV7()
+	if err != nil {
+		err = fmt.Errorf("user.service.login: failed to generate session id: %w", err)
+		return
+	}
+
+	refreshToken, refreshTokenHash, err := generateRefreshToken(sessionID.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.login: failed to generate refresh token: %w", err)
+		return
+	}
+
+	refreshTokenExpire := time.Duration(config.Get().JWT.RefreshExpireIn) * time.Second
+
+	session := newSession(client)
+	session.ID = sessionID
+	session.UserID = data.ID
+	session.RefreshTokenHash = refreshTokenHash
+	session.ExpiresAt = time.Now().Add(refreshTokenExpire)
+
+	err = repo.CreateSession(ctx, session)
+	if err != nil {
+		err = fmt.Errorf("user.service.login: failed to create session: %w", err)
+		return
+	}
+
 	accessTokenExpire := time.Duration(config.Get().JWT.ExpireIn) * time.Second
 
 	accessToken, err := s.CreateJWTWithExpiry(
 		data.ID.String(),
 		data.Name,
-		config.Get().JWT.Secret,
+		sessionID.String(),
 		accessTokenExpire,
 	)
 
@@ -135,9 +342,17 @@ func (s Service) login(data entity.User, isRegister bool) (res model.UserLoginRe
 		err = fmt.Errorf("user.service.login: failed to create access token: %w", err)
 		return
 	}
+
+	err = s.loginGuard.Succeed(ctx, credentialKey)
+	if err != nil {
+		err = fmt.Errorf("user.service.login: failed to reset login attempts: %w", err)
+		return
+	}
 	res = model.UserLoginResponse{
-		Name:        data.Name,
-		AccessToken: accessToken,
+		Name:              data.Name,
+		AccessToken:       accessToken,
+		RefreshToken:      refreshToken,
+		DeletionCancelled: data.DeletionScheduledAt.Valid,
 	}
 
 	if isRegister {
@@ -153,10 +368,11 @@ func (s Service) login(data entity.User, isRegister bool) (res model.UserLoginRe
 	return
 }
 
-func (s Service) CreateJWTWithExpiry(id, name, secret string, expiry time.Duration) (token string, err error) {
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, model.JWTClaims{
+func (s Service) CreateJWTWithExpiry(id, name, sessionID string, expiry time.Duration) (token string, err error) {
+	token, err = s.jwtKeys.Sign(model.JWTClaims{
 		Name: name,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			Issuer:    config.Get().Service.Name,
 			Subject:   id,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
@@ -164,8 +380,6 @@ func (s Service) CreateJWTWithExpiry(id, name, secret string, expiry time.Durati
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	})
-
-	token, err = jwtToken.SignedString([]byte(secret))
 	if err != nil {
 		err = fmt.Errorf("usecase: failed to create jwt token: %w", err)
 		return
@@ -174,79 +388,181 @@ func (s Service) CreateJWTWithExpiry(id, name, secret string, expiry time.Durati
 	return
 }
 
-func (s Service) AddFriend(ctx context.Context, req model.FriendRequest) (err error) {
-	err = validation.Validate(req)
+// generateRefreshToken returns a refresh token in the form of "<sessionID>.<secret>"
+// and the hash of the secret, only the hash is stored in the database.
+func generateRefreshToken(sessionID string) (token, hash string, err error) {
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
 	if err != nil {
-		err = fmt.Errorf("user.service.AddFriend: failed to validate request: %w", err)
+		err = fmt.Errorf("user.service.generateRefreshToken: failed to read random bytes: %w", err)
 		return
 	}
 
-	// if req.UserID == req.UserIDAdder {
-	// 	err = constant.ErrFriendSelfAdding
-	// 	return
-	// }
+	secretStr := base64.RawURLEncoding.EncodeToString(secret)
 
-	tx, err := s.repo.Begin(ctx)
+	return sessionID + "." + secretStr, hashRefreshTokenSecret(secretStr), nil
+}
+
+func hashRefreshTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s Service) RefreshToken(ctx context.Context, req model.UserRefreshTokenRequest) (res model.UserLoginResponse, err error) {
+	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("user.service.AddFriend: failed to begin transaction: %w", err)
+		err = fmt.Errorf("user.service.RefreshToken: failed to validate request: %w", err)
 		return
 	}
 
-	defer func() {
-		if err != nil {
-			errRb := tx.Rollback(ctx)
-			if errRb != nil {
-				err = fmt.Errorf("user.service.AddFriend: failed  to rollback: %w", errRb)
-				return
-			}
-			return
+	sessionID, secret, ok := strings.Cut(req.RefreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		err = fmt.Errorf("user.service.RefreshToken: malformed refresh token, %w", constant.ErrRefreshTokenInvalid)
+		return
+	}
+
+	session, err := s.repo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, constant.ErrSessionNotFound) {
+			err = constant.ErrRefreshTokenInvalid
 		}
+		err = fmt.Errorf("user.service.RefreshToken: failed to get session: %w", err)
+		return
+	}
 
-		err = tx.Commit(ctx)
-		if err != nil {
-			err = fmt.Errorf("user.service.AddFriend: failed  to commit: %w", err)
+	if session.RevokedAt.Valid || time.Now().After(session.ExpiresAt) {
+		err = fmt.Errorf("user.service.RefreshToken: session revoked or expired, %w", constant.ErrRefreshTokenInvalid)
+		return
+	}
+
+	// a valid session with a different hash means an already rotated token is being replayed,
+	// the whole session is revoked because we cannot tell the legitimate client from the attacker
+	if subtle.ConstantTimeCompare([]byte(hashRefreshTokenSecret(secret)), []byte(session.RefreshTokenHash)) != 1 {
+		err = s.revokeReusedSession(ctx, session)
+		return
+	}
+
+	refreshToken, refreshTokenHash, err := generateRefreshToken(sessionID)
+	if err != nil {
+		err = fmt.Errorf("user.service.RefreshToken: failed to generate refresh token: %w", err)
+		return
+	}
+
+	refreshTokenExpire := time.Duration(config.Get().JWT.RefreshExpireIn) * time.Second
+
+	err = s.repo.RotateSession(ctx, sessionID, session.RefreshTokenHash, refreshTokenHash, time.Now().Add(refreshTokenExpire))
+	if err != nil {
+		if errors.Is(err, constant.ErrRefreshTokenReused) {
+			err = s.revokeReusedSession(ctx, session)
 			return
 		}
-	}()
+		err = fmt.Errorf("user.service.RefreshToken: failed to rotate session: %w", err)
+		return
+	}
 
-	_, err = s.repo.WithTx(tx).GetByID(ctx, req.UserID)
+	data, err := s.repo.GetByID(ctx, session.UserID.String())
 	if err != nil {
-		err = fmt.Errorf("user.service.AddFriend: failed to get user by id: %w", err)
+		err = fmt.Errorf("user.service.RefreshToken: failed to get user by id: %w", err)
 		return
 	}
 
-	err = s.repo.WithTx(tx).AddFriend(ctx, req.UserIDAdder, req.UserID)
+	accessTokenExpire := time.Duration(config.Get().JWT.ExpireIn) * time.Second
+
+	accessToken, err := s.CreateJWTWithExpiry(
+		data.ID.String(),
+		data.Name,
+		sessionID,
+		accessTokenExpire,
+	)
 	if err != nil {
-		err = fmt.Errorf("user.service.AddFriend: failed to add friend: %w", err)
+		err = fmt.Errorf("user.service.RefreshToken: failed to create access token: %w", err)
+		return
+	}
+
+	// a missing credential is left out of the response instead of returned as an empty string
+	res = model.UserLoginResponse{
+		Phone:        data.Phone.Ptr(),
+		Email:        data.Email.Ptr(),
+		Name:         data.Name,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}
+
+	return
+}
+
+func (s Service) revokeReusedSession(ctx context.Context, session entity.Session) (err error) {
+	logger.Log(ctx).Warn().
+		Str("session_id", session.ID.String()).
+		Str("user_id", session.UserID.String()).
+		Msg("user.service.RefreshToken: refresh token reuse detected, revoking session")
+
+	err = s.repo.RevokeSession(ctx, session.ID.String(), session.UserID.String())
+	if err != nil && !errors.Is(err, constant.ErrSessionNotFound) {
+		err = fmt.Errorf("user.service.RefreshToken: failed to revoke session: %w", err)
 		return
 	}
 
-	err = s.repo.WithTx(tx).IncrementFriendCount(ctx, req.UserID)
+	s.sessions.forget(session.ID.String())
+
+	return fmt.Errorf("user.service.RefreshToken: refresh token reused, %w", constant.ErrRefreshTokenReused)
+}
+
+func (s Service) Logout(ctx context.Context, req model.UserLogoutRequest) (err error) {
+	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("user.service.AddFriend: failed to increment friend count: %w", err)
+		err = fmt.Errorf("user.service.Logout: failed to validate request: %w", err)
 		return
 	}
 
-	err = s.repo.WithTx(tx).IncrementFriendCount(ctx, req.UserIDAdder)
+	err = s.repo.RevokeSession(ctx, req.SessionID, req.UserID)
 	if err != nil {
-		err = fmt.Errorf("user.service.AddFriend: failed to increment friend count: %w", err)
+		err = fmt.Errorf("user.service.Logout: failed to revoke session: %w", err)
 		return
 	}
 
+	s.sessions.forget(req.SessionID)
+
 	return
 }
 
-func (s Service) DeleteFriend(ctx context.Context, req model.FriendRequest) (err error) {
+func (s Service) AddFriend(ctx context.Context, req model.FriendRequest) (res model.FriendRequestResponse, err error) {
 	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("user.service.DeleteFriend: failed to validate request: %w", err)
+		err = fmt.Errorf("user.service.AddFriend: failed to validate request: %w", err)
 		return
 	}
 
-	// if req.UserID == req.UserIDAdder {
-	// 	err = constant.ErrFriendSelfDeleting
-	// 	return
-	// }
+	if req.UserID == req.UserIDAdder {
+		err = fmt.Errorf("user.service.AddFriend: cannot add self, %w", constant.ErrFriendSelfAdding)
+		return
+	}
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		if res.Status == entity.FriendRequestStatusAccepted {
+			s.notify(ctx, model.NotificationCreateRequest{
+				UserID:  req.UserID,
+				ActorID: req.UserIDAdder,
+				Type:    entity.NotificationTypeFriendAccepted,
+			})
+			s.publishFriendAccepted(ctx, req.UserIDAdder, req.UserID)
+			return
+		}
+
+		s.notify(ctx, model.NotificationCreateRequest{
+			UserID:  req.UserID,
+			ActorID: req.UserIDAdder,
+			Type:    entity.NotificationTypeFriendRequest,
+		})
+		s.publish(ctx, model.StreamEventFriendRequested, []string{req.UserID}, model.StreamFriendEvent{
+			UserID: req.UserIDAdder,
+		})
+	}()
 
 	tx, err := s.repo.Begin(ctx)
 	if err != nil {
@@ -271,111 +587,918 @@ func (s Service) DeleteFriend(ctx context.Context, req model.FriendRequest) (err
 		}
 	}()
 
-	_, err = s.repo.WithTx(tx).GetByID(ctx, req.UserID)
+	target, err := s.repo.WithTx(tx).GetByID(ctx, req.UserID)
 	if err != nil {
-		err = fmt.Errorf("user.service.DeleteFriend: failed to get user by id: %w", err)
+		err = fmt.Errorf("user.service.AddFriend: failed to get user by id: %w", err)
 		return
 	}
 
-	err = s.repo.WithTx(tx).DeleteFriend(ctx, req.UserIDAdder, req.UserID)
+	isBlocked, err := s.repo.WithTx(tx).IsBlocked(ctx, req.UserIDAdder, req.UserID)
 	if err != nil {
-		err = fmt.Errorf("user.service.DeleteFriend: failed to delete friend: %w", err)
+		err = fmt.Errorf("user.service.AddFriend: failed to check is blocked: %w", err)
 		return
 	}
 
-	err = s.repo.WithTx(tx).DecrementFriendCount(ctx, req.UserID)
-	if err != nil {
-		err = fmt.Errorf("user.service.DeleteFriend: failed to decrement friend count: %w", err)
+	if isBlocked {
+		err = fmt.Errorf("user.service.AddFriend: %w", constant.ErrUserBlocked)
 		return
 	}
 
-	err = s.repo.WithTx(tx).DecrementFriendCount(ctx, req.UserIDAdder)
+	isFriend, err := s.repo.WithTx(tx).IsFriend(ctx, req.UserIDAdder, req.UserID)
 	if err != nil {
-		err = fmt.Errorf("user.service.DeleteFriend: failed to decrement friend count: %w", err)
+		err = fmt.Errorf("user.service.AddFriend: failed to check is friend: %w", err)
 		return
 	}
-	return
-}
 
-func (s Service) GetList(ctx context.Context, req model.UserGetListRequest) (res []model.UserResponse, count int, err error) {
-	err = validation.Validate(req)
-	if err != nil {
-		err = fmt.Errorf("user.service.GetList: failed to validate request: %w", err)
+	if isFriend {
+		err = fmt.Errorf("user.service.AddFriend: already friend, %w", constant.ErrFriendUseralreadyAdded)
 		return
 	}
 
-	// tx, err := s.repo.Begin(ctx)
-	// if err != nil {
-	// 	err = fmt.Errorf("user.service.GetList: failed to begin transaction: %w", err)
-	// 	return
-	// }
+	res.User = model.UserResponse{
+		UserID: target.ID.String(),
+		Name:   target.Name,
+	}
 
-	// defer func() {
-	// 	if err != nil {
-	// 		errRb := tx.Rollback(ctx)
-	// 		if errRb != nil {
-	// 			err = fmt.Errorf("user.service.GetList: failed  to rollback: %w", errRb)
-	// 			return
-	// 		}
-	// 		return
-	// 	}
+	// the target already asked to be friends, sending a request back accepts theirs
+	incoming, err := s.repo.WithTx(tx).GetPendingFriendRequest(ctx, req.UserID, req.UserIDAdder)
+	if err == nil {
+		err = s.acceptFriendRequest(ctx, s.repo.WithTx(tx), incoming)
+		if err != nil {
+			err = fmt.Errorf("user.service.AddFriend: failed to accept incoming friend request: %w", err)
+			return
+		}
 
-	// 	err = tx.Commit(ctx)
-	// 	if err != nil {
-	// 		err = fmt.Errorf("user.service.GetList: failed  to commit: %w", err)
-	// 		return
-	// 	}
-	// }()
+		res.FriendRequestID = incoming.ID.String()
+		res.Status = entity.FriendRequestStatusAccepted
+		res.CreatedAt = incoming.CreatedAt.Format(constant.TimeISO8601Format)
+		return
+	}
 
-	resDB, err := s.repo.GetList(ctx, req)
-	if err != nil {
-		err = fmt.Errorf("user.service.GetList: failed to get list user: %w", err)
+	if !errors.Is(err, constant.ErrFriendRequestNotFound) {
+		err = fmt.Errorf("user.service.AddFriend: failed to get incoming friend request: %w", err)
 		return
 	}
 
-	// count, err = s.repo.WithTx(tx).GetCountList(ctx, req)
-	// if err != nil {
-	// 	err = fmt.Errorf("user.service.GetList: failed to get count list user: %w", err)
-	// 	return
-	// }
+	id, err := uuid.NewV7()
+	if err != nil {
+		err = fmt.Errorf("user.service.AddFriend: failed to generate friend request id: %w", err)
+		return
+	}
 
-	res = make([]model.UserResponse, len(resDB))
+	userIdAdderUUID, err := uuid.Parse(req.UserIDAdder)
+	if err != nil {
+		err = fmt.Errorf("user.service.AddFriend: failed to parse user id: %w", err)
+		return
+	}
 
-	for i, v := range resDB {
-		count = v.Total
-		res[i] = model.UserResponse{
-			UserID:      v.ID.String(),
-			Name:        v.Name,
-			ImageUrl:    v.ImageUrl.ValueOrZero(),
-			FriendCount: v.FriendCount,
-			CreatedAt:   v.CreatedAt.Format(constant.TimeISO8601Format),
-		}
+	data := entity.FriendRequest{
+		ID:              id,
+		UserIDRequester: userIdAdderUUID,
+		UserIDTarget:    target.ID,
+		Status:          entity.FriendRequestStatusPending,
+	}
+
+	err = s.repo.WithTx(tx).CreateFriendRequest(ctx, data)
+	if err != nil {
+		err = fmt.Errorf("user.service.AddFriend: failed to create friend request: %w", err)
+		return
+	}
+
+	res.FriendRequestID = data.ID.String()
+	res.Status = data.Status
+	res.CreatedAt = time.Now().UTC().Format(constant.TimeISO8601Format)
+
+	return
+}
+
+// publishFriendAccepted tells both users that they are now friends
+func (s Service) publishFriendAccepted(ctx context.Context, userID, friendID string) {
+	s.publish(ctx, model.StreamEventFriendAccepted, []string{userID}, model.StreamFriendEvent{UserID: friendID})
+	s.publish(ctx, model.StreamEventFriendAccepted, []string{friendID}, model.StreamFriendEvent{UserID: userID})
+}
+
+// acceptFriendRequest is the only place where a friends row is created,
+// it is expected to be called within a transaction. The pair is unique in either direction
+// so when mutual requests are accepted at the same time the second one fails as already added
+func (s Service) acceptFriendRequest(ctx context.Context, repo user.Repository, data entity.FriendRequest) (err error) {
+	err = repo.UpdateFriendRequestStatus(ctx, data.ID.String(), entity.FriendRequestStatusAccepted)
+	if err != nil {
+		err = fmt.Errorf("user.service.acceptFriendRequest: failed to update friend request status: %w", err)
+		return
+	}
+
+	err = repo.AddFriend(ctx, data.UserIDRequester.String(), data.UserIDTarget.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.acceptFriendRequest: failed to add friend: %w", err)
+		return
+	}
+
+	err = repo.IncrementFriendCount(ctx, data.UserIDTarget.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.acceptFriendRequest: failed to increment friend count: %w", err)
+		return
+	}
+
+	err = repo.IncrementFriendCount(ctx, data.UserIDRequester.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.acceptFriendRequest: failed to increment friend count: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) AcceptFriendRequest(ctx context.Context, req model.FriendRequestActionRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.AcceptFriendRequest: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.resolveFriendRequest(ctx, req, entity.FriendRequestStatusAccepted)
+	if err != nil {
+		err = fmt.Errorf("user.service.AcceptFriendRequest: failed to accept friend request: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) RejectFriendRequest(ctx context.Context, req model.FriendRequestActionRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.RejectFriendRequest: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.resolveFriendRequest(ctx, req, entity.FriendRequestStatusRejected)
+	if err != nil {
+		err = fmt.Errorf("user.service.RejectFriendRequest: failed to reject friend request: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) CancelFriendRequest(ctx context.Context, req model.FriendRequestActionRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.CancelFriendRequest: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.resolveFriendRequest(ctx, req, entity.FriendRequestStatusCancelled)
+	if err != nil {
+		err = fmt.Errorf("user.service.CancelFriendRequest: failed to cancel friend request: %w", err)
+		return
+	}
+
+	return
+}
+
+// resolveFriendRequest moves a pending friend request to its final status,
+// only the target can accept or reject it and only the requester can cancel it
+func (s Service) resolveFriendRequest(ctx context.Context, req model.FriendRequestActionRequest, status string) (err error) {
+	var data entity.FriendRequest
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil || status != entity.FriendRequestStatusAccepted {
+			return
+		}
+
+		s.notify(ctx, model.NotificationCreateRequest{
+			UserID:  data.UserIDRequester.String(),
+			ActorID: data.UserIDTarget.String(),
+			Type:    entity.NotificationTypeFriendAccepted,
+		})
+		s.publishFriendAccepted(ctx, data.UserIDTarget.String(), data.UserIDRequester.String())
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.resolveFriendRequest: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.resolveFriendRequest: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.resolveFriendRequest: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	data, err = s.repo.WithTx(tx).GetFriendRequestByID(ctx, req.FriendRequestID)
+	if err != nil {
+		err = fmt.Errorf("user.service.resolveFriendRequest: failed to get friend request: %w", err)
+		return
+	}
+
+	allowedUserID := data.UserIDTarget.String()
+	if status == entity.FriendRequestStatusCancelled {
+		allowedUserID = data.UserIDRequester.String()
+	}
+
+	// hide requests that do not belong to the user instead of returning forbidden
+	if allowedUserID != req.UserID {
+		err = fmt.Errorf("user.service.resolveFriendRequest: %w", constant.ErrFriendRequestNotFound)
+		return
+	}
+
+	if data.Status != entity.FriendRequestStatusPending {
+		err = fmt.Errorf("user.service.resolveFriendRequest: %w", constant.ErrFriendRequestNotPending)
+		return
+	}
+
+	if status == entity.FriendRequestStatusAccepted {
+		err = s.acceptFriendRequest(ctx, s.repo.WithTx(tx), data)
+		return
+	}
+
+	err = s.repo.WithTx(tx).UpdateFriendRequestStatus(ctx, data.ID.String(), status)
+	if err != nil {
+		err = fmt.Errorf("user.service.resolveFriendRequest: failed to update friend request status: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) GetFriendRequestList(ctx context.Context, req model.FriendRequestGetListRequest) (res []model.FriendRequestResponse, count int, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetFriendRequestList: failed to validate request: %w", err)
+		return
+	}
+
+	resDB, err := s.repo.GetFriendRequestList(ctx, req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetFriendRequestList: failed to get list friend request: %w", err)
+		return
+	}
+
+	userIDs := make([]string, len(resDB))
+	for i, v := range resDB {
+		userIDs[i] = v.UserIDTarget.String()
+		if req.Incoming {
+			userIDs[i] = v.UserIDRequester.String()
+		}
+	}
+
+	userMap := map[string]model.UserResponse{}
+	if len(userIDs) > 0 {
+		userMap, err = s.GetListMap(ctx, model.UserGetListRequest{
+			UserIDs:       userIDs,
+			DisableOffset: true,
+			DisableOrder:  true,
+		})
+		if err != nil {
+			err = fmt.Errorf("user.service.GetFriendRequestList: failed to get list user: %w", err)
+			return
+		}
+	}
+
+	res = make([]model.FriendRequestResponse, len(resDB))
+	for i, v := range resDB {
+		count = v.Total
+		res[i] = model.FriendRequestResponse{
+			FriendRequestID: v.ID.String(),
+			Status:          v.Status,
+			User:            userMap[userIDs[i]],
+			CreatedAt:       v.CreatedAt.Format(constant.TimeISO8601Format),
+		}
+	}
+
+	return
+}
+
+func (s Service) GetFriendIDs(ctx context.Context, userID string) (ids []string, err error) {
+	ids, err = s.repo.GetFriendIDs(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetFriendIDs: failed to get friend ids: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) DeleteFriend(ctx context.Context, req model.FriendRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteFriend: failed to validate request: %w", err)
+		return
+	}
+
+	// if req.UserID == req.UserIDAdder {
+	// 	err = constant.ErrFriendSelfDeleting
+	// 	return
+	// }
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		s.publish(ctx, model.StreamEventFriendRemoved, []string{req.UserID}, model.StreamFriendEvent{UserID: req.UserIDAdder})
+		s.publish(ctx, model.StreamEventFriendRemoved, []string{req.UserIDAdder}, model.StreamFriendEvent{UserID: req.UserID})
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.AddFriend: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.AddFriend: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.AddFriend: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	_, err = s.repo.WithTx(tx).GetByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteFriend: failed to get user by id: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).DeleteFriend(ctx, req.UserIDAdder, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteFriend: failed to delete friend: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).DecrementFriendCount(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteFriend: failed to decrement friend count: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).DecrementFriendCount(ctx, req.UserIDAdder)
+	if err != nil {
+		err = fmt.Errorf("user.service.DeleteFriend: failed to decrement friend count: %w", err)
+		return
+	}
+	return
+}
+
+// GetProfile returns the user with their relationship with the viewer,
+// a pending friend request in either direction is returned with the relationship.
+// It fails with ErrUserBlocked when one of the viewer and the user blocked the other
+func (s Service) GetProfile(ctx context.Context, req model.UserProfileRequest) (res model.UserProfileResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetProfile: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetProfile: failed to get user: %w", err)
+		return
+	}
+
+	res.UserResponse = model.UserResponse{
+		UserID:      data.ID.String(),
+		Name:        data.Name,
+		ImageUrl:    data.ImageUrl.ValueOrZero(),
+		FriendCount: data.FriendCount,
+		CreatedAt:   data.CreatedAt.Format(constant.TimeISO8601Format),
+	}
+
+	if res.UserID == req.ViewerID {
+		res.Relationship = entity.RelationshipSelf
+		return
+	}
+
+	isBlocked, err := s.repo.IsBlocked(ctx, req.ViewerID, res.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetProfile: failed to check is blocked: %w", err)
+		return
+	}
+
+	if isBlocked {
+		err = fmt.Errorf("user.service.GetProfile: %w", constant.ErrUserBlocked)
+		return
+	}
+
+	isFriend, err := s.repo.IsFriend(ctx, req.ViewerID, res.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetProfile: failed to check is friend: %w", err)
+		return
+	}
+
+	if isFriend {
+		res.Relationship = entity.RelationshipFriend
+		return
+	}
+
+	friendRequest, err := s.repo.GetPendingFriendRequestBetween(ctx, req.ViewerID, res.UserID)
+	if err != nil {
+		if errors.Is(err, constant.ErrFriendRequestNotFound) {
+			res.Relationship = entity.RelationshipNone
+			return res, nil
+		}
+
+		err = fmt.Errorf("user.service.GetProfile: failed to get pending friend request: %w", err)
+		return
+	}
+
+	res.Relationship = entity.RelationshipPending
+	res.FriendRequestID = friendRequest.ID.String()
+	res.FriendRequestIncoming = friendRequest.UserIDTarget.String() == req.ViewerID
+
+	return
+}
+
+// GetList returns list of user, a cursor from a previous page switches the list to keyset pagination.
+// The first page returns a next cursor as well unless the users are sorted by friendCount
+func (s Service) GetList(ctx context.Context, req model.UserGetListRequest) (res []model.UserResponse, nextCursor, prevCursor string, count int, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetList: failed to validate request: %w", err)
+		return
+	}
+
+	current, err := cursor.Decode(cursor.ScopeUser, req.Cursor)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetList: failed to decode cursor: %w", err)
+		return
+	}
+
+	// ids only follow the creation time, a friendCount page cannot be continued from an id
+	orderedByID := req.SortBy != "friendCount" && !req.DisableOffset
+	if current.ID != "" && !orderedByID {
+		err = fmt.Errorf("user.service.GetList: %w", constant.ErrCursorSortUnsupported)
+		return
+	}
+
+	// tx, err := s.repo.Begin(ctx)
+	// if err != nil {
+	// 	err = fmt.Errorf("user.service.GetList: failed to begin transaction: %w", err)
+	// 	return
+	// }
+
+	// defer func() {
+	// 	if err != nil {
+	// 		errRb := tx.Rollback(ctx)
+	// 		if errRb != nil {
+	// 			err = fmt.Errorf("user.service.GetList: failed  to rollback: %w", errRb)
+	// 			return
+	// 		}
+	// 		return
+	// 	}
+
+	// 	err = tx.Commit(ctx)
+	// 	if err != nil {
+	// 		err = fmt.Errorf("user.service.GetList: failed  to commit: %w", err)
+	// 		return
+	// 	}
+	// }()
+
+	// fetch one more user to know whether there is another page
+	filter := req
+	filter.CursorID, filter.CursorPrev = current.ID, current.Prev
+	if orderedByID {
+		filter.Limit = req.Limit + 1
+	}
+
+	resDB, err := s.repo.GetList(ctx, filter)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetList: failed to get list user: %w", err)
+		return
+	}
+
+	hasMore := orderedByID && len(resDB) > req.Limit
+	if hasMore {
+		resDB = resDB[:req.Limit]
+	}
+
+	if current.Prev {
+		slices.Reverse(resDB)
+	}
+
+	// count, err = s.repo.WithTx(tx).GetCountList(ctx, req)
+	// if err != nil {
+	// 	err = fmt.Errorf("user.service.GetList: failed to get count list user: %w", err)
+	// 	return
+	// }
+
+	res = make([]model.UserResponse, len(resDB))
+
+	for i, v := range resDB {
+		count = v.Total
+		res[i] = model.UserResponse{
+			UserID:      v.ID.String(),
+			Name:        v.Name,
+			ImageUrl:    v.ImageUrl.ValueOrZero(),
+			FriendCount: v.FriendCount,
+			CreatedAt:   v.CreatedAt.Format(constant.TimeISO8601Format),
+		}
+	}
+
+	if orderedByID {
+		ids := make([]string, len(res))
+		for i, v := range res {
+			ids[i] = v.UserID
+		}
+
+		nextCursor, prevCursor = cursor.Paginate(cursor.ScopeUser, ids, current, hasMore)
+	}
+
+	return
+}
+
+func (s Service) IsFriend(ctx context.Context, userIdAdder, userIdAdded string) (isFriend bool, err error) {
+	return s.repo.IsFriend(ctx, userIdAdder, userIdAdded)
+}
+
+func (s Service) GetListMap(ctx context.Context, req model.UserGetListRequest) (data map[string]model.UserResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetListMap: failed to validate request: %w", err)
+		return
+	}
+
+	resDB, err := s.repo.GetListMap(ctx, req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetListMap: failed to get list user: %w", err)
+		return
+	}
+
+	data = make(map[string]model.UserResponse)
+
+	for k, v := range resDB {
+		data[k] = model.UserResponse{
+			UserID:      v.ID.String(),
+			Name:        v.Name,
+			ImageUrl:    v.ImageUrl.ValueOrZero(),
+			FriendCount: v.FriendCount,
+			CreatedAt:   v.CreatedAt.Format(constant.TimeISO8601Format),
+		}
+	}
+
+	return
+}
+
+// ResolveMentions returns the user id of each normalized handle among the owner and, when included, their friends.
+// A handle matching the name of more than one user is left out, so are the users blocked with the author
+func (s Service) ResolveMentions(ctx context.Context, req model.MentionResolveRequest) (res map[string]string, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResolveMentions: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetMentionCandidates(ctx, req.OwnerID, req.AuthorID, req.Handles, req.IncludeFriends)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResolveMentions: failed to get mention candidates: %w", err)
+		return
+	}
+
+	res = make(map[string]string)
+	ambiguous := make(map[string]struct{})
+	for _, v := range data {
+		handle := mention.Normalize(v.Name)
+		if _, ok := res[handle]; ok {
+			ambiguous[handle] = struct{}{}
+			continue
+		}
+
+		res[handle] = v.ID.String()
+	}
+
+	for handle := range ambiguous {
+		delete(res, handle)
+	}
+
+	return
+}
+
+// UpdatePhone sends an otp to the phone, it is linked to the user once VerifyCredential accepts the otp
+func (s Service) UpdatePhone(ctx context.Context, req model.UserPhoneUpdateRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdatePhone: failed to validate request: %w", err)
+		return
+	}
+
+	resDB, err := s.repo.GetByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdatePhone: failed to get user by id: %w", err)
+		return
+	}
+
+	if resDB.Phone.Valid {
+		err = fmt.Errorf("user.service.UpdatePhone: phone already exist, %w", constant.ErrUserAlreadyHavePhone)
+		return
+	}
+
+	_, err = s.repo.GetByCredential(ctx, "phone", req.Phone)
+	if err == nil {
+		err = fmt.Errorf("user.service.UpdatePhone: phone taken, %w", constant.ErrPhoneAlreadyRegistered)
+		return
+	}
+
+	if !errors.Is(err, constant.ErrUserNotFound) {
+		err = fmt.Errorf("user.service.UpdatePhone: failed to get user by phone: %w", err)
+		return
+	}
+
+	err = s.startVerification(ctx, resDB.ID, "phone", req.Phone)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdatePhone: %w", err)
+		return
+	}
+
+	return
+}
+
+// UpdateEmail sends an otp to the email, it is linked to the user once VerifyCredential accepts the otp
+func (s Service) UpdateEmail(ctx context.Context, req model.UserEmailUpdateRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdateEmail: failed to validate request: %w", err)
+		return
+	}
+
+	resDB, err := s.repo.GetByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdateEmail: failed to get user by id: %w", err)
+		return
+	}
+
+	if resDB.Email.Valid {
+		err = fmt.Errorf("user.service.UpdateEmail: email already exist, %w", constant.ErrUserAlreadyHaveEmail)
+		return
+	}
+
+	_, err = s.repo.GetByCredential(ctx, "email", req.Email)
+	if err == nil {
+		err = fmt.Errorf("user.service.UpdateEmail: email taken, %w", constant.ErrEmailAlreadyRegistered)
+		return
+	}
+
+	if !errors.Is(err, constant.ErrUserNotFound) {
+		err = fmt.Errorf("user.service.UpdateEmail: failed to get user by email: %w", err)
+		return
+	}
+
+	err = s.startVerification(ctx, resDB.ID, "email", req.Email)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdateEmail: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) UpdateProfile(ctx context.Context, req model.UserProfileUpdateRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdateProfile: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.repo.UpdateProfile(ctx, entity.User{
+		ID:       uuid.MustParse(req.UserID),
+		Name:     req.Name,
+		ImageUrl: null.StringFrom(req.ImageUrl),
+	})
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdateProfile: failed to update profile: %w", err)
+		return
+	}
+
+	return
+}
+
+// Block blocks the user, an existing friendship and the pending friend requests between them are removed
+func (s Service) Block(ctx context.Context, req model.UserBlockRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to validate request: %w", err)
+		return
+	}
+
+	if req.UserID == req.UserIDBlocker {
+		err = fmt.Errorf("user.service.Block: cannot block self, %w", constant.ErrUserSelfBlocking)
+		return
+	}
+
+	var isFriend bool
+
+	// registered before the transaction defer so it only runs after the commit,
+	// the blocked user is not told about the block
+	defer func() {
+		if err != nil || !isFriend {
+			return
+		}
+
+		s.publish(ctx, model.StreamEventFriendRemoved, []string{req.UserIDBlocker}, model.StreamFriendEvent{UserID: req.UserID})
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.Block: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.Block: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	repo := s.repo.WithTx(tx)
+
+	_, err = repo.GetByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to get user by id: %w", err)
+		return
+	}
+
+	err = repo.CreateBlock(ctx, req.UserIDBlocker, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to block user: %w", err)
+		return
+	}
+
+	err = repo.CancelPendingFriendRequestsBetween(ctx, req.UserIDBlocker, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to cancel pending friend requests: %w", err)
+		return
+	}
+
+	isFriend, err = repo.IsFriend(ctx, req.UserIDBlocker, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to check is friend: %w", err)
+		return
+	}
+
+	if !isFriend {
+		return
+	}
+
+	err = repo.DeleteFriend(ctx, req.UserIDBlocker, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to delete friend: %w", err)
+		return
+	}
+
+	err = repo.DecrementFriendCount(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to decrement friend count: %w", err)
+		return
+	}
+
+	err = repo.DecrementFriendCount(ctx, req.UserIDBlocker)
+	if err != nil {
+		err = fmt.Errorf("user.service.Block: failed to decrement friend count: %w", err)
+		return
 	}
 
 	return
 }
 
-func (s Service) IsFriend(ctx context.Context, userIdAdder, userIdAdded string) (isFriend bool, err error) {
-	return s.repo.IsFriend(ctx, userIdAdder, userIdAdded)
+// Unblock removes the block, the friendship removed by the block is not restored
+func (s Service) Unblock(ctx context.Context, req model.UserBlockRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.Unblock: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.repo.DeleteBlock(ctx, req.UserIDBlocker, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Unblock: failed to unblock user: %w", err)
+		return
+	}
+
+	return
 }
 
-func (s Service) GetListMap(ctx context.Context, req model.UserGetListRequest) (data map[string]model.UserResponse, err error) {
+// IsBlocked reports whether one of the users blocked the other
+func (s Service) IsBlocked(ctx context.Context, userIdA, userIdB string) (isBlocked bool, err error) {
+	return s.repo.IsBlocked(ctx, userIdA, userIdB)
+}
+
+func (s Service) GetBlockList(ctx context.Context, req model.UserRestrictionGetListRequest) (res []model.UserResponse, count int, err error) {
 	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("user.service.GetListMap: failed to validate request: %w", err)
+		err = fmt.Errorf("user.service.GetBlockList: failed to validate request: %w", err)
 		return
 	}
 
-	resDB, err := s.repo.GetListMap(ctx, req)
+	resDB, err := s.repo.GetBlockList(ctx, req)
 	if err != nil {
-		err = fmt.Errorf("user.service.GetListMap: failed to get list user: %w", err)
+		err = fmt.Errorf("user.service.GetBlockList: failed to get list blocked user: %w", err)
 		return
 	}
 
-	data = make(map[string]model.UserResponse)
+	res, count = toUserListResponse(resDB)
+	return
+}
 
-	for k, v := range resDB {
-		data[k] = model.UserResponse{
+// Mute hides the posts of the user from the feed of the muter, the muted user is not told about it
+func (s Service) Mute(ctx context.Context, req model.UserMuteRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.Mute: failed to validate request: %w", err)
+		return
+	}
+
+	if req.UserID == req.UserIDMuter {
+		err = fmt.Errorf("user.service.Mute: cannot mute self, %w", constant.ErrUserSelfMuting)
+		return
+	}
+
+	_, err = s.repo.GetByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Mute: failed to get user by id: %w", err)
+		return
+	}
+
+	err = s.repo.CreateMute(ctx, req.UserIDMuter, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Mute: failed to mute user: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) Unmute(ctx context.Context, req model.UserMuteRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.Unmute: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.repo.DeleteMute(ctx, req.UserIDMuter, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.Unmute: failed to unmute user: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) GetMuteList(ctx context.Context, req model.UserRestrictionGetListRequest) (res []model.UserResponse, count int, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetMuteList: failed to validate request: %w", err)
+		return
+	}
+
+	resDB, err := s.repo.GetMuteList(ctx, req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetMuteList: failed to get list muted user: %w", err)
+		return
+	}
+
+	res, count = toUserListResponse(resDB)
+	return
+}
+
+func toUserListResponse(data []entity.User) (res []model.UserResponse, count int) {
+	res = make([]model.UserResponse, len(data))
+	for i, v := range data {
+		count = v.Total
+		res[i] = model.UserResponse{
 			UserID:      v.ID.String(),
 			Name:        v.Name,
 			ImageUrl:    v.ImageUrl.ValueOrZero(),
@@ -387,74 +1510,316 @@ func (s Service) GetListMap(ctx context.Context, req model.UserGetListRequest) (
 	return
 }
 
-func (s Service) UpdatePhone(ctx context.Context, req model.UserPhoneUpdateRequest) (err error) {
+func hashPassword(password string) (hash string, err error) {
+	cost := bcrypt.DefaultCost
+	if config.Get().Bcrypt.Salt > 0 {
+		cost = config.Get().Bcrypt.Salt
+	}
+
+	b, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return
+	}
+
+	return string(b), nil
+}
+
+// UpdatePassword changes the password after checking the old one,
+// every other session of the user is revoked so only the current device stays logged in
+func (s Service) UpdatePassword(ctx context.Context, req model.UserPasswordUpdateRequest) (err error) {
 	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdatePhone: failed to validate request: %w", err)
+		err = fmt.Errorf("user.service.UpdatePassword: failed to validate request: %w", err)
 		return
 	}
 
-	resDB, err := s.repo.GetByID(ctx, req.UserID)
+	password, err := s.repo.GetPasswordByID(ctx, req.UserID)
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdatePhone: failed to get user by id: %w", err)
+		err = fmt.Errorf("user.service.UpdatePassword: failed to get password: %w", err)
 		return
 	}
 
-	if resDB.Phone.Valid {
-		err = fmt.Errorf("user.service.UpdatePhone: phone already exist, %w", constant.ErrUserAlreadyHavePhone)
+	err = bcrypt.CompareHashAndPassword([]byte(password), []byte(req.OldPassword))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			err = constant.ErrOldPasswordInvalid
+		}
+		err = fmt.Errorf("user.service.UpdatePassword: failed to compare password: %w", err)
+		return
+	}
+
+	hashedPassword, err := hashPassword(req.NewPassword)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdatePassword: failed to hash password: %w", err)
+		return
+	}
+
+	var revokedIDs []string
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		s.sessions.forget(revokedIDs...)
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdatePassword: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.UpdatePassword: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.UpdatePassword: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	err = s.repo.WithTx(tx).UpdatePassword(ctx, req.UserID, hashedPassword)
+	if err != nil {
+		err = fmt.Errorf("user.service.UpdatePassword: failed to update password: %w", err)
 		return
 	}
 
-	err = s.repo.UpdatePhone(ctx, req.UserID, req.Phone)
+	revokedIDs, err = s.repo.WithTx(tx).RevokeUserSessions(ctx, req.UserID, req.SessionID)
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdatePhone: failed to update phone: %w", err)
+		err = fmt.Errorf("user.service.UpdatePassword: failed to revoke other sessions: %w", err)
 		return
 	}
 
 	return
 }
 
-func (s Service) UpdateEmail(ctx context.Context, req model.UserEmailUpdateRequest) (err error) {
+// ForgotPassword sends an otp to the email or phone to reset the password,
+// an unknown credential is not reported so the endpoint cannot be used to find registered users
+func (s Service) ForgotPassword(ctx context.Context, req model.UserPasswordForgotRequest) (err error) {
 	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdateEmail: failed to validate request: %w", err)
+		err = fmt.Errorf("user.service.ForgotPassword: failed to validate request: %w", err)
 		return
 	}
 
-	resDB, err := s.repo.GetByID(ctx, req.UserID)
+	data, err := s.repo.GetByCredential(ctx, req.CredentialType, req.CredentialValue)
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdateEmail: failed to get user by id: %w", err)
+		if errors.Is(err, constant.ErrUserNotFound) {
+			logger.Log(ctx).Info().Str("credential_type", req.CredentialType).Msg("user.service.ForgotPassword: credential not registered")
+			return nil
+		}
+
+		err = fmt.Errorf("user.service.ForgotPassword: failed to get user by credential: %w", err)
 		return
 	}
 
-	if resDB.Email.Valid {
-		err = fmt.Errorf("user.service.UpdateEmail: email already exist, %w", constant.ErrUserAlreadyHaveEmail)
+	if !credentialVerified(data, req.CredentialType) {
+		logger.Log(ctx).Info().Str("credential_type", req.CredentialType).Msg("user.service.ForgotPassword: credential not verified")
+		return nil
+	}
+
+	cfg := config.Get().OTP
+	now := time.Now()
+
+	count, latestAt, err := s.repo.GetPasswordResetStats(ctx, data.ID.String(), now.Add(-time.Duration(cfg.SendWindow)*time.Second))
+	if err != nil {
+		err = fmt.Errorf("user.service.ForgotPassword: failed to get password reset stats: %w", err)
+		return
+	}
+
+	// a throttled request is answered like the others so the response does not tell the credential is registered,
+	// the user can still use the last code sent
+	if latestAt.Valid && now.Sub(latestAt.Time) < time.Duration(cfg.ResendInterval)*time.Second {
+		logger.Log(ctx).Info().Str("credential_type", req.CredentialType).Msg("user.service.ForgotPassword: otp resend too soon")
+		return nil
+	}
+
+	if cfg.SendLimit > 0 && count >= cfg.SendLimit {
+		logger.Log(ctx).Warn().Str("credential_type", req.CredentialType).Int("count", count).Msg("user.service.ForgotPassword: too many password resets")
+		return nil
+	}
+
+	code, err := otp.Generate()
+	if err != nil {
+		err = fmt.Errorf("user.service.ForgotPassword: failed to generate otp: %w", err)
+		return
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		err = fmt.Errorf("user.service.ForgotPassword: failed to generate password reset id: %w", err)
+		return
+	}
+
+	expireIn := time.Duration(cfg.ExpireIn) * time.Second
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		err = s.notifier.Send(ctx, notifier.Message{
+			Channel: req.CredentialType,
+			To:      req.CredentialValue,
+			Subject: "Password reset",
+			Body:    fmt.Sprintf("Your password reset code is %s, it expires in %d minutes.", code, int(expireIn.Minutes())),
+			Code:    code,
+		})
+		if err != nil {
+			err = fmt.Errorf("user.service.ForgotPassword: failed to send otp: %w", err)
+			return
+		}
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.ForgotPassword: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.ForgotPassword: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.ForgotPassword: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	err = s.repo.WithTx(tx).ExpirePasswordResets(ctx, data.ID.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.ForgotPassword: failed to expire previous password resets: %w", err)
 		return
 	}
 
-	err = s.repo.UpdateEmail(ctx, req.UserID, req.Email)
+	err = s.repo.WithTx(tx).CreatePasswordReset(ctx, entity.PasswordReset{
+		ID:        id,
+		UserID:    data.ID,
+		Channel:   req.CredentialType,
+		OTPHash:   otp.Hash(code),
+		ExpiresAt: now.Add(expireIn),
+		CreatedAt: now,
+	})
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdateEmail: failed to update email: %w", err)
+		err = fmt.Errorf("user.service.ForgotPassword: failed to create password reset: %w", err)
 		return
 	}
 
 	return
 }
 
-func (s Service) UpdateProfile(ctx context.Context, req model.UserProfileUpdateRequest) (err error) {
+// ResetPassword sets a new password with the otp sent by ForgotPassword,
+// every session of the user is revoked so the tokens issued before the reset stop working
+func (s Service) ResetPassword(ctx context.Context, req model.UserPasswordResetRequest) (err error) {
 	err = validation.Validate(req)
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdateProfile: failed to validate request: %w", err)
+		err = fmt.Errorf("user.service.ResetPassword: failed to validate request: %w", err)
 		return
 	}
 
-	err = s.repo.UpdateProfile(ctx, entity.User{
-		ID:       uuid.MustParse(req.UserID),
-		Name:     req.Name,
-		ImageUrl: null.StringFrom(req.ImageUrl),
-	})
+	data, err := s.repo.GetByCredential(ctx, req.CredentialType, req.CredentialValue)
 	if err != nil {
-		err = fmt.Errorf("user.service.UpdateProfile: failed to update profile: %w", err)
+		if errors.Is(err, constant.ErrUserNotFound) {
+			err = constant.ErrOTPInvalid
+		}
+		err = fmt.Errorf("user.service.ResetPassword: failed to get user by credential: %w", err)
+		return
+	}
+
+	if !credentialVerified(data, req.CredentialType) {
+		err = fmt.Errorf("user.service.ResetPassword: credential not verified, %w", constant.ErrOTPInvalid)
+		return
+	}
+
+	reset, err := s.repo.GetActivePasswordReset(ctx, data.ID.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.ResetPassword: failed to get password reset: %w", err)
+		return
+	}
+
+	// the otp is only valid for the credential it was sent to
+	if reset.Channel != req.CredentialType || time.Now().After(reset.ExpiresAt) {
+		err = fmt.Errorf("user.service.ResetPassword: otp expired, %w", constant.ErrOTPInvalid)
+		return
+	}
+
+	err = s.repo.ConsumePasswordResetAttempt(ctx, reset.ID.String(), config.Get().OTP.MaxAttempts)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResetPassword: %w", err)
+		return
+	}
+
+	if !otp.Equal(reset.OTPHash, req.OTP) {
+		err = fmt.Errorf("user.service.ResetPassword: otp mismatch, %w", constant.ErrOTPInvalid)
+		return
+	}
+
+	hashedPassword, err := hashPassword(req.NewPassword)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResetPassword: failed to hash password: %w", err)
+		return
+	}
+
+	var revokedIDs []string
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		s.sessions.forget(revokedIDs...)
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResetPassword: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.ResetPassword: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.ResetPassword: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	err = s.repo.WithTx(tx).UsePasswordReset(ctx, reset.ID.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.ResetPassword: failed to use password reset: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).UpdatePassword(ctx, data.ID.String(), hashedPassword)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResetPassword: failed to update password: %w", err)
+		return
+	}
+
+	revokedIDs, err = s.repo.WithTx(tx).RevokeUserSessions(ctx, data.ID.String(), "")
+	if err != nil {
+		err = fmt.Errorf("user.service.ResetPassword: failed to revoke sessions: %w", err)
 		return
 	}
 
@@ -0,0 +1,71 @@
+package usersvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/user"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/google/uuid"
+)
+
+// profileRepo answers the queries of GetProfile, any other call panics on the nil embedded repository
+type profileRepo struct {
+	user.Repository
+	blocked bool
+	friend  bool
+}
+
+func (r profileRepo) GetByID(ctx context.Context, id string) (entity.User, error) {
+	return entity.User{ID: uuid.MustParse(id), Name: "user"}, nil
+}
+
+func (r profileRepo) IsBlocked(ctx context.Context, userIdA, userIdB string) (bool, error) {
+	return r.blocked, nil
+}
+
+func (r profileRepo) IsFriend(ctx context.Context, userIdAdder, userIdAdded string) (bool, error) {
+	return r.friend, nil
+}
+
+func (r profileRepo) GetPendingFriendRequestBetween(ctx context.Context, userIdA, userIdB string) (entity.FriendRequest, error) {
+	return entity.FriendRequest{}, constant.ErrFriendRequestNotFound
+}
+
+func TestGetProfile(t *testing.T) {
+	const (
+		userID   = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e6f"
+		viewerID = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e70"
+	)
+
+	tests := []struct {
+		name             string
+		viewerID         string
+		repo             profileRepo
+		wantRelationship string
+		wantErr          error
+	}{
+		{name: "self", viewerID: userID, repo: profileRepo{}, wantRelationship: entity.RelationshipSelf},
+		{name: "friend", viewerID: viewerID, repo: profileRepo{friend: true}, wantRelationship: entity.RelationshipFriend},
+		{name: "stranger", viewerID: viewerID, repo: profileRepo{}, wantRelationship: entity.RelationshipNone},
+		{name: "blocked", viewerID: viewerID, repo: profileRepo{blocked: true}, wantErr: constant.ErrUserBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{repo: tt.repo}
+
+			res, err := s.GetProfile(context.Background(), model.UserProfileRequest{UserID: userID, ViewerID: tt.viewerID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetProfile() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if res.Relationship != tt.wantRelationship {
+				t.Errorf("GetProfile() relationship = %q, want %q", res.Relationship, tt.wantRelationship)
+			}
+		})
+	}
+}
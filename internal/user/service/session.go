@@ -0,0 +1,227 @@
+package usersvc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	maxDeviceLength    = 255
+	maxUserAgentLength = 512
+)
+
+// sessionCache remembers the sessions found active so JWTAuth does not hit the database on every request,
+// revoked sessions are never cached so a revocation in this process applies at once
+// and a revocation in another replica applies once the entry expires
+type sessionCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]time.Time
+	lastSweep time.Time
+}
+
+func newSessionCache(ttl time.Duration) *sessionCache {
+	return &sessionCache{
+		ttl:     ttl,
+		entries: make(map[string]time.Time),
+	}
+}
+
+func (c *sessionCache) active(id string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checkedAt, ok := c.entries[id]
+	return ok && now.Sub(checkedAt) < c.ttl
+}
+
+func (c *sessionCache) set(id string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastSweep) > c.ttl {
+		for key, checkedAt := range c.entries {
+			if now.Sub(checkedAt) >= c.ttl {
+				delete(c.entries, key)
+			}
+		}
+
+		c.lastSweep = now
+	}
+
+	c.entries[id] = now
+}
+
+func (c *sessionCache) forget(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		delete(c.entries, id)
+	}
+}
+
+// deviceFromUserAgent describes the device in a few words, e.g. "Chrome on Windows"
+func deviceFromUserAgent(userAgent string) string {
+	var os string
+	switch {
+	case strings.Contains(userAgent, "iPhone"):
+		os = "iPhone"
+	case strings.Contains(userAgent, "iPad"):
+		os = "iPad"
+	case strings.Contains(userAgent, "Android"):
+		os = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		os = "Windows"
+	case strings.Contains(userAgent, "Macintosh"), strings.Contains(userAgent, "Mac OS X"):
+		os = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		os = "Linux"
+	}
+
+	// the order matters, most browsers also claim to be the ones they are based on
+	var browser string
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case os != "":
+		return os
+	case browser != "":
+		return browser
+	default:
+		return ""
+	}
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+
+	return strings.ToValidUTF8(s[:length], "")
+}
+
+// newSession fills the client of the session, the device falls back to a description of the user agent
+func newSession(client model.SessionClient) entity.Session {
+	device := client.Device
+	if device == "" {
+		device = deviceFromUserAgent(client.UserAgent)
+	}
+
+	return entity.Session{
+		Device:    null.NewString(truncate(device, maxDeviceLength), device != ""),
+		UserAgent: null.NewString(truncate(client.UserAgent, maxUserAgentLength), client.UserAgent != ""),
+		IP:        null.NewString(client.IP, client.IP != ""),
+	}
+}
+
+// IsSessionRevoked is called by JWTAuth on every request, an active session is only checked again,
+// and its lastSeenAt updated, once its cache entry expires
+func (s Service) IsSessionRevoked(ctx context.Context, sessionID string) (isRevoked bool, err error) {
+	now := time.Now()
+	if s.sessions.active(sessionID, now) {
+		return false, nil
+	}
+
+	active, err := s.repo.TouchSession(ctx, sessionID)
+	if err != nil {
+		err = fmt.Errorf("user.service.IsSessionRevoked: failed to touch session: %w", err)
+		return
+	}
+
+	if active {
+		s.sessions.set(sessionID, now)
+	}
+
+	return !active, nil
+}
+
+func (s Service) GetSessionList(ctx context.Context, req model.UserSessionGetListRequest) (res []model.UserSessionResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetSessionList: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetSessionList(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.GetSessionList: failed to get sessions: %w", err)
+		return
+	}
+
+	res = make([]model.UserSessionResponse, len(data))
+	for i, v := range data {
+		res[i] = model.UserSessionResponse{
+			ID:         v.ID.String(),
+			Device:     v.Device.ValueOrZero(),
+			UserAgent:  v.UserAgent.ValueOrZero(),
+			IP:         v.IP.ValueOrZero(),
+			Current:    v.ID.String() == req.SessionID,
+			LastSeenAt: v.LastSeenAt.Format(constant.TimeISO8601Format),
+			CreatedAt:  v.CreatedAt.Format(constant.TimeISO8601Format),
+			ExpiresAt:  v.ExpiresAt.Format(constant.TimeISO8601Format),
+		}
+	}
+
+	return
+}
+
+// RevokeSession logs out one of the sessions of the user, revoking the current session is the same as Logout
+func (s Service) RevokeSession(ctx context.Context, req model.UserSessionRevokeRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.RevokeSession: failed to validate request: %w", err)
+		return
+	}
+
+	err = s.repo.RevokeSession(ctx, req.ID, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.RevokeSession: failed to revoke session: %w", err)
+		return
+	}
+
+	s.sessions.forget(req.ID)
+
+	return
+}
+
+// LogoutAll revokes every session of the user, the current one included
+func (s Service) LogoutAll(ctx context.Context, req model.UserLogoutRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.LogoutAll: failed to validate request: %w", err)
+		return
+	}
+
+	ids, err := s.repo.RevokeUserSessions(ctx, req.UserID, "")
+	if err != nil {
+		err = fmt.Errorf("user.service.LogoutAll: failed to revoke sessions: %w", err)
+		return
+	}
+
+	s.sessions.forget(ids...)
+
+	return
+}
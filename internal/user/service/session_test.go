@@ -0,0 +1,153 @@
+package usersvc
+
+import (
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestDeviceFromUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{
+			name:      "chrome on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want:      "Chrome on Windows",
+		},
+		{
+			name:      "edge on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0",
+			want:      "Edge on Windows",
+		},
+		{
+			name:      "opera on linux",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 OPR/109.0.0.0",
+			want:      "Opera on Linux",
+		},
+		{
+			name:      "firefox on macos",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14.4; rv:125.0) Gecko/20100101 Firefox/125.0",
+			want:      "Firefox on macOS",
+		},
+		{
+			name:      "safari on iphone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want:      "Safari on iPhone",
+		},
+		{
+			name:      "safari on ipad",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want:      "Safari on iPad",
+		},
+		{
+			name:      "chrome on android",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+			want:      "Chrome on Android",
+		},
+		{
+			name:      "os only",
+			userAgent: "okhttp/4.12.0 (Linux; Android 14)",
+			want:      "Android",
+		},
+		{
+			name:      "unknown client",
+			userAgent: "curl/8.6.0",
+			want:      "",
+		},
+		{
+			name:      "empty",
+			userAgent: "",
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deviceFromUserAgent(tt.userAgent); got != tt.want {
+				t.Errorf("deviceFromUserAgent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		length int
+		want   string
+	}{
+		{name: "shorter", input: "abc", length: 5, want: "abc"},
+		{name: "exact", input: "abcde", length: 5, want: "abcde"},
+		{name: "longer", input: "abcdef", length: 5, want: "abcde"},
+		{name: "multi byte rune is not split", input: "abcdé", length: 5, want: "abcd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.input, tt.length)
+			if got != tt.want {
+				t.Errorf("truncate() = %q, want %q", got, tt.want)
+			}
+
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate() = %q is not valid utf-8", got)
+			}
+		})
+	}
+}
+
+func TestSessionCache(t *testing.T) {
+	now := time.Now()
+	c := newSessionCache(time.Minute)
+
+	c.set("a", now)
+	c.set("b", now)
+
+	tests := []struct {
+		name string
+		id   string
+		at   time.Time
+		want bool
+	}{
+		{name: "cached", id: "a", at: now, want: true},
+		{name: "before expiry", id: "a", at: now.Add(59 * time.Second), want: true},
+		{name: "expired", id: "a", at: now.Add(time.Minute)},
+		{name: "unknown", id: "c", at: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.active(tt.id, tt.at); got != tt.want {
+				t.Errorf("active(%s) = %t, want %t", tt.id, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("forget", func(t *testing.T) {
+		c.forget("a", "c")
+		if c.active("a", now) {
+			t.Error("active(a) = true after forget")
+		}
+
+		if !c.active("b", now) {
+			t.Error("active(b) = false, forget removed another session")
+		}
+	})
+
+	t.Run("expired entries are swept", func(t *testing.T) {
+		later := now.Add(2 * time.Minute)
+		c.set("d", later)
+
+		if _, ok := c.entries["b"]; ok {
+			t.Error("expired entry b was not swept")
+		}
+
+		if !c.active("d", later) {
+			t.Error("active(d) = false right after set")
+		}
+	})
+}
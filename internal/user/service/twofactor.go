@@ -0,0 +1,470 @@
+package usersvc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/user"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/loginguard"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/otp"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/totp"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const recoveryCodeSize = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCode returns a random code formatted as four groups of four characters
+func generateRecoveryCode() (code string, err error) {
+	b := make([]byte, recoveryCodeSize)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("user.service.generateRecoveryCode: failed to read random bytes: %w", err)
+	}
+
+	raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeRecoveryCode removes the formatting so a code is accepted with or without dashes and in any case
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// verifyTwoFactorCode accepts a code of the authenticator app or an unused recovery code,
+// the code is marked as used so it is expected to be called within a transaction
+func (s Service) verifyTwoFactorCode(ctx context.Context, repo user.Repository, data entity.UserTOTP, code string) (err error) {
+	code = strings.TrimSpace(code)
+
+	if isTOTPCode(code) {
+		step, ok := totp.Validate(data.Secret, code, time.Now())
+		if !ok {
+			err = fmt.Errorf("user.service.verifyTwoFactorCode: %w", constant.ErrTwoFactorCodeInvalid)
+			return
+		}
+
+		err = repo.UseTOTPStep(ctx, data.UserID.String(), step)
+		if err != nil {
+			err = fmt.Errorf("user.service.verifyTwoFactorCode: %w", err)
+			return
+		}
+
+		return
+	}
+
+	err = repo.UseRecoveryCode(ctx, data.UserID.String(), otp.Hash(normalizeRecoveryCode(code)))
+	if err != nil {
+		err = fmt.Errorf("user.service.verifyTwoFactorCode: %w", err)
+		return
+	}
+
+	return
+}
+
+// EnrollTwoFactor creates a new secret for the authenticator app of the user,
+// it is not asked on login until EnableTwoFactor receives a code of the app
+func (s Service) EnrollTwoFactor(ctx context.Context, req model.UserTwoFactorEnrollRequest) (res model.UserTwoFactorEnrollResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.EnrollTwoFactor: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.EnrollTwoFactor: failed to get user by id: %w", err)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		err = fmt.Errorf("user.service.EnrollTwoFactor: failed to generate secret: %w", err)
+		return
+	}
+
+	err = s.repo.UpsertTOTP(ctx, entity.UserTOTP{
+		UserID: data.ID,
+		Secret: secret,
+	})
+	if err != nil {
+		err = fmt.Errorf("user.service.EnrollTwoFactor: failed to save secret: %w", err)
+		return
+	}
+
+	issuer := config.Get().TOTP.Issuer
+	if issuer == "" {
+		issuer = config.Get().Service.Name
+	}
+
+	account := data.Email.ValueOrZero()
+	if account == "" {
+		account = data.Phone.ValueOrZero()
+	}
+
+	res = model.UserTwoFactorEnrollResponse{
+		Secret: secret,
+		URI:    totp.URI(issuer, account, secret),
+	}
+
+	return
+}
+
+// EnableTwoFactor enables the enrolled secret once a code of the authenticator app proves it works,
+// the recovery codes are only returned here, only their hashes are stored
+func (s Service) EnableTwoFactor(ctx context.Context, req model.UserTwoFactorEnableRequest) (res model.UserTwoFactorEnableResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.EnableTwoFactor: failed to validate request: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetTOTP(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.EnableTwoFactor: failed to get totp: %w", err)
+		return
+	}
+
+	if data.EnabledAt.Valid {
+		err = fmt.Errorf("user.service.EnableTwoFactor: %w", constant.ErrTwoFactorAlreadyEnabled)
+		return
+	}
+
+	step, ok := totp.Validate(data.Secret, req.Code, time.Now())
+	if !ok {
+		err = fmt.Errorf("user.service.EnableTwoFactor: %w", constant.ErrTwoFactorCodeInvalid)
+		return
+	}
+
+	codes := make([]string, config.Get().TOTP.RecoveryCodeCount)
+	codeHashes := make([]string, len(codes))
+	for i := range codes {
+		codes[i], err = generateRecoveryCode()
+		if err != nil {
+			err = fmt.Errorf("user.service.EnableTwoFactor: %w", err)
+			return
+		}
+
+		codeHashes[i] = otp.Hash(normalizeRecoveryCode(codes[i]))
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.EnableTwoFactor: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.EnableTwoFactor: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.EnableTwoFactor: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	err = s.repo.WithTx(tx).EnableTOTP(ctx, req.UserID, step)
+	if err != nil {
+		err = fmt.Errorf("user.service.EnableTwoFactor: failed to enable totp: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).ReplaceRecoveryCodes(ctx, req.UserID, codeHashes)
+	if err != nil {
+		err = fmt.Errorf("user.service.EnableTwoFactor: failed to save recovery codes: %w", err)
+		return
+	}
+
+	res = model.UserTwoFactorEnableResponse{
+		RecoveryCodes: codes,
+	}
+
+	return
+}
+
+// DisableTwoFactor removes the secret and the recovery codes of the user,
+// both the password and a code are asked so a stolen access token alone cannot turn it off
+func (s Service) DisableTwoFactor(ctx context.Context, req model.UserTwoFactorDisableRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.DisableTwoFactor: failed to validate request: %w", err)
+		return
+	}
+
+	password, err := s.repo.GetPasswordByID(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.DisableTwoFactor: failed to get password: %w", err)
+		return
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(password), []byte(req.Password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			err = constant.ErrPasswordInvalid
+		}
+		err = fmt.Errorf("user.service.DisableTwoFactor: failed to compare password: %w", err)
+		return
+	}
+
+	data, err := s.repo.GetTOTP(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, constant.ErrTwoFactorNotEnrolled) {
+			err = constant.ErrTwoFactorNotEnabled
+		}
+		err = fmt.Errorf("user.service.DisableTwoFactor: failed to get totp: %w", err)
+		return
+	}
+
+	if !data.EnabledAt.Valid {
+		err = fmt.Errorf("user.service.DisableTwoFactor: %w", constant.ErrTwoFactorNotEnabled)
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.DisableTwoFactor: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.DisableTwoFactor: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.DisableTwoFactor: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	err = s.verifyTwoFactorCode(ctx, s.repo.WithTx(tx), data, req.Code)
+	if err != nil {
+		err = fmt.Errorf("user.service.DisableTwoFactor: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).DeleteRecoveryCodes(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.DisableTwoFactor: failed to delete recovery codes: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).DeleteTOTP(ctx, req.UserID)
+	if err != nil {
+		err = fmt.Errorf("user.service.DisableTwoFactor: failed to delete totp: %w", err)
+		return
+	}
+
+	return
+}
+
+// createLoginChallenge is the first step of a login with two factor authentication,
+// the returned challenge token is exchanged for the access and refresh token by LoginTwoFactor.
+// The credential key is kept with the challenge so the wrong codes count as failed logins of the credential
+func (s Service) createLoginChallenge(ctx context.Context, data entity.User, credentialKey loginguard.Key) (res model.UserLoginResponse, err error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		err = fmt.Errorf("user.service.createLoginChallenge: failed to generate challenge id: %w", err)
+		return
+	}
+
+	expiry := time.Duration(config.Get().TOTP.ChallengeExpireIn) * time.Second
+
+	err = s.repo.CreateLoginChallenge(ctx, entity.LoginChallenge{
+		ID:            id,
+		UserID:        data.ID,
+		CredentialKey: credentialKey.Value,
+		ExpiresAt:     time.Now().Add(expiry),
+	})
+	if err != nil {
+		err = fmt.Errorf("user.service.createLoginChallenge: failed to create login challenge: %w", err)
+		return
+	}
+
+	token, err := s.jwtKeys.Sign(model.JWTClaims{
+		Purpose: constant.JWTPurposeLoginChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.String(),
+			Issuer:    config.Get().Service.Name,
+			Subject:   data.ID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	})
+	if err != nil {
+		err = fmt.Errorf("user.service.createLoginChallenge: failed to create challenge token: %w", err)
+		return
+	}
+
+	res = model.UserLoginResponse{
+		Name:              data.Name,
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	}
+
+	return
+}
+
+// parseChallengeToken returns the claims of a token created by createLoginChallenge,
+// an access token is refused so it cannot be used to skip the password
+func (s Service) parseChallengeToken(token string) (claims *model.JWTClaims, err error) {
+	claims = &model.JWTClaims{}
+	t, err := s.jwtKeys.Parse(token, claims)
+	if err != nil || !t.Valid || claims.Purpose != constant.JWTPurposeLoginChallenge {
+		return nil, fmt.Errorf("user.service.parseChallengeToken: %w", constant.ErrLoginChallengeInvalid)
+	}
+
+	return claims, nil
+}
+
+// LoginTwoFactor is the second step of a login with two factor authentication,
+// the challenge accepts a limited number of wrong codes and can only be used once
+func (s Service) LoginTwoFactor(ctx context.Context, req model.UserLoginTwoFactorRequest) (res model.UserLoginResponse, err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: failed to validate request: %w", err)
+		return
+	}
+
+	claims, err := s.parseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: %w", err)
+		return
+	}
+
+	challenge, err := s.repo.GetLoginChallenge(ctx, claims.ID)
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: failed to get login challenge: %w", err)
+		return
+	}
+
+	if challenge.UserID.String() != claims.Subject || challenge.UsedAt.Valid || time.Now().After(challenge.ExpiresAt) {
+		err = fmt.Errorf("user.service.LoginTwoFactor: challenge expired, %w", constant.ErrLoginChallengeInvalid)
+		return
+	}
+
+	credentialKey := loginguard.Key{Scope: loginguard.ScopeCredential, Value: challenge.CredentialKey}
+	guardKeys := loginGuardKeys(credentialKey, req.Client.IP)
+
+	err = s.checkLogin(ctx, guardKeys)
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: %w", err)
+		return
+	}
+
+	err = s.repo.ConsumeLoginChallengeAttempt(ctx, challenge.ID.String(), config.Get().OTP.MaxAttempts)
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: %w", err)
+		return
+	}
+
+	totpData, err := s.repo.GetTOTP(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, constant.ErrTwoFactorNotEnrolled) {
+			err = constant.ErrLoginChallengeInvalid
+		}
+		err = fmt.Errorf("user.service.LoginTwoFactor: failed to get totp: %w", err)
+		return
+	}
+
+	// two factor authentication was disabled after the challenge was created
+	if !totpData.EnabledAt.Valid {
+		err = fmt.Errorf("user.service.LoginTwoFactor: totp disabled, %w", constant.ErrLoginChallengeInvalid)
+		return
+	}
+
+	data, err := s.repo.GetByID(ctx, claims.Subject)
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: failed to get user by id: %w", err)
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.LoginTwoFactor: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.LoginTwoFactor: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	err = s.verifyTwoFactorCode(ctx, s.repo.WithTx(tx), totpData, req.Code)
+	if err != nil {
+		if errors.Is(err, constant.ErrTwoFactorCodeInvalid) {
+			s.failLogin(ctx, guardKeys)
+		}
+		err = fmt.Errorf("user.service.LoginTwoFactor: %w", err)
+		return
+	}
+
+	err = s.repo.WithTx(tx).UseLoginChallenge(ctx, challenge.ID.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.LoginTwoFactor: failed to use login challenge: %w", err)
+		return
+	}
+
+	return s.login(ctx, s.repo.WithTx(tx), data, req.Client, credentialKey, false)
+}
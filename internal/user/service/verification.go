@@ -0,0 +1,260 @@
+package usersvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/internal/entity"
+	"github.com/arfan21/project-sprint-social-media-api/internal/model"
+	"github.com/arfan21/project-sprint-social-media-api/internal/user"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/notifier"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/otp"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/validation"
+	"github.com/google/uuid"
+)
+
+// credentialVerified reports whether the email or phone of the user is verified
+func credentialVerified(data entity.User, credentialType string) bool {
+	if credentialType == notifier.ChannelPhone {
+		return data.PhoneVerifiedAt.Valid
+	}
+
+	return data.EmailVerifiedAt.Valid
+}
+
+// createVerification stores a new code for the credential, the previous codes of the user for the channel stop working.
+// A new code is refused until OTP_RESEND_INTERVAL has passed since the last one sent to the user or to the address,
+// and once OTP_SEND_LIMIT codes were sent to the address inside OTP_SEND_WINDOW whichever user asked for them.
+// It is expected to be called within a transaction
+func (s Service) createVerification(ctx context.Context, repo user.Repository, userID uuid.UUID, channel, value string) (code string, err error) {
+	cfg := config.Get().OTP
+	now := time.Now()
+	resendInterval := time.Duration(cfg.ResendInterval) * time.Second
+
+	latest, err := repo.GetLatestVerification(ctx, userID.String(), channel)
+	if err != nil && !errors.Is(err, constant.ErrVerificationNotFound) {
+		err = fmt.Errorf("user.service.createVerification: failed to get latest verification: %w", err)
+		return
+	}
+
+	if err == nil && now.Sub(latest.CreatedAt) < resendInterval {
+		err = fmt.Errorf("user.service.createVerification: %w", constant.ErrOTPResendTooSoon)
+		return
+	}
+
+	count, latestAt, err := repo.GetVerificationStats(ctx, channel, value, now.Add(-time.Duration(cfg.SendWindow)*time.Second))
+	if err != nil {
+		err = fmt.Errorf("user.service.createVerification: failed to get verification stats: %w", err)
+		return
+	}
+
+	if latestAt.Valid && now.Sub(latestAt.Time) < resendInterval {
+		err = fmt.Errorf("user.service.createVerification: address, %w", constant.ErrOTPResendTooSoon)
+		return
+	}
+
+	if cfg.SendLimit > 0 && count >= cfg.SendLimit {
+		err = fmt.Errorf("user.service.createVerification: address, %w", constant.ErrOTPSendLimitReached)
+		return
+	}
+
+	code, err = otp.Generate()
+	if err != nil {
+		err = fmt.Errorf("user.service.createVerification: failed to generate otp: %w", err)
+		return
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		err = fmt.Errorf("user.service.createVerification: failed to generate verification id: %w", err)
+		return
+	}
+
+	err = repo.ExpireVerifications(ctx, userID.String(), channel)
+	if err != nil {
+		err = fmt.Errorf("user.service.createVerification: failed to expire previous verifications: %w", err)
+		return
+	}
+
+	err = repo.CreateVerification(ctx, entity.CredentialVerification{
+		ID:        id,
+		UserID:    userID,
+		Channel:   channel,
+		Value:     value,
+		OTPHash:   otp.Hash(code),
+		ExpiresAt: now.Add(time.Duration(cfg.ExpireIn) * time.Second),
+		CreatedAt: now,
+	})
+	if err != nil {
+		err = fmt.Errorf("user.service.createVerification: failed to create verification: %w", err)
+		return
+	}
+
+	return
+}
+
+func (s Service) sendVerification(ctx context.Context, channel, value, code string) (err error) {
+	expireIn := time.Duration(config.Get().OTP.ExpireIn) * time.Second
+
+	err = s.notifier.Send(ctx, notifier.Message{
+		Channel: channel,
+		To:      value,
+		Subject: "Verification code",
+		Body:    fmt.Sprintf("Your verification code is %s, it expires in %d minutes.", code, int(expireIn.Minutes())),
+		Code:    code,
+	})
+	if err != nil {
+		err = fmt.Errorf("user.service.sendVerification: failed to send otp: %w", err)
+		return
+	}
+
+	return
+}
+
+// startVerification creates a code for the credential and sends it
+func (s Service) startVerification(ctx context.Context, userID uuid.UUID, channel, value string) (err error) {
+	var code string
+
+	// registered before the transaction defer so it only runs after the commit
+	defer func() {
+		if err != nil {
+			return
+		}
+
+		err = s.sendVerification(ctx, channel, value, code)
+	}()
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.startVerification: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.startVerification: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.startVerification: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	code, err = s.createVerification(ctx, s.repo.WithTx(tx), userID, channel, value)
+	if err != nil {
+		err = fmt.Errorf("user.service.startVerification: %w", err)
+		return
+	}
+
+	return
+}
+
+// VerifyCredential checks the otp sent to the email or phone of the user and links the credential to the user,
+// the credential is only stored in the user once verified so an unverified address does not take it from its owner
+func (s Service) VerifyCredential(ctx context.Context, req model.UserVerifyRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.VerifyCredential: failed to validate request: %w", err)
+		return
+	}
+
+	verification, err := s.repo.GetActiveVerification(ctx, req.UserID, req.CredentialType)
+	if err != nil {
+		if errors.Is(err, constant.ErrVerificationNotFound) {
+			err = constant.ErrOTPInvalid
+		}
+		err = fmt.Errorf("user.service.VerifyCredential: failed to get verification: %w", err)
+		return
+	}
+
+	if time.Now().After(verification.ExpiresAt) {
+		err = fmt.Errorf("user.service.VerifyCredential: otp expired, %w", constant.ErrOTPInvalid)
+		return
+	}
+
+	err = s.repo.ConsumeVerificationAttempt(ctx, verification.ID.String(), config.Get().OTP.MaxAttempts)
+	if err != nil {
+		err = fmt.Errorf("user.service.VerifyCredential: %w", err)
+		return
+	}
+
+	if !otp.Equal(verification.OTPHash, req.OTP) {
+		err = fmt.Errorf("user.service.VerifyCredential: otp mismatch, %w", constant.ErrOTPInvalid)
+		return
+	}
+
+	tx, err := s.repo.Begin(ctx)
+	if err != nil {
+		err = fmt.Errorf("user.service.VerifyCredential: failed to begin transaction: %w", err)
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			errRb := tx.Rollback(ctx)
+			if errRb != nil {
+				err = fmt.Errorf("user.service.VerifyCredential: failed  to rollback: %w", errRb)
+				return
+			}
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("user.service.VerifyCredential: failed  to commit: %w", err)
+			return
+		}
+	}()
+
+	err = s.repo.WithTx(tx).UseVerification(ctx, verification.ID.String())
+	if err != nil {
+		err = fmt.Errorf("user.service.VerifyCredential: failed to use verification: %w", err)
+		return
+	}
+
+	if verification.Channel == notifier.ChannelPhone {
+		err = s.repo.WithTx(tx).UpdatePhone(ctx, verification.UserID.String(), verification.Value)
+	} else {
+		err = s.repo.WithTx(tx).UpdateEmail(ctx, verification.UserID.String(), verification.Value)
+	}
+	if err != nil {
+		err = fmt.Errorf("user.service.VerifyCredential: failed to link credential: %w", err)
+		return
+	}
+
+	return
+}
+
+// ResendVerification sends a new otp to the credential of the user waiting to be verified
+func (s Service) ResendVerification(ctx context.Context, req model.UserVerificationResendRequest) (err error) {
+	err = validation.Validate(req)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResendVerification: failed to validate request: %w", err)
+		return
+	}
+
+	verification, err := s.repo.GetActiveVerification(ctx, req.UserID, req.CredentialType)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResendVerification: failed to get verification: %w", err)
+		return
+	}
+
+	err = s.startVerification(ctx, verification.UserID, verification.Channel, verification.Value)
+	if err != nil {
+		err = fmt.Errorf("user.service.ResendVerification: %w", err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,20 @@
+CREATE TABLE
+    IF NOT EXISTS sessions (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        userId UUID NOT NULL,
+        refreshTokenHash VARCHAR(255) NOT NULL,
+        expiresAt TIMESTAMP NOT NULL,
+        revokedAt TIMESTAMP,
+        createdAt TIMESTAMP DEFAULT now (),
+        updatedAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (userId);
+
+CREATE TRIGGER update_sessions_updated_at
+    BEFORE UPDATE
+    ON sessions
+    FOR EACH ROW
+    EXECUTE PROCEDURE trigger_set_updated();
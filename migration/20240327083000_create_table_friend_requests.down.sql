@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_friends_pair;
+
+DROP TABLE IF EXISTS friend_requests;
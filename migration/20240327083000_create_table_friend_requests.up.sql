@@ -0,0 +1,54 @@
+CREATE TABLE
+    IF NOT EXISTS friend_requests (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        userIdRequester UUID NOT NULL,
+        userIdTarget UUID NOT NULL,
+        status VARCHAR(20) NOT NULL DEFAULT 'pending',
+        createdAt TIMESTAMP DEFAULT now (),
+        updatedAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_userIdRequester FOREIGN KEY (userIdRequester) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT fk_userIdTarget FOREIGN KEY (userIdTarget) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT chk_status CHECK (status IN ('pending', 'accepted', 'rejected', 'cancelled'))
+    );
+
+-- only one pending request is allowed per direction
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friend_requests_pending ON friend_requests (userIdRequester, userIdTarget)
+WHERE
+    status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_friend_requests_target ON friend_requests (userIdTarget, status);
+
+-- a friendship is stored once in either direction, mutual requests accepted at the same time
+-- must not add it twice, the pairs already stored twice are kept once with their count fixed
+WITH
+    removed AS (
+        DELETE FROM friends f USING friends o
+        WHERE
+            f.userIdAdder = o.userIdAdded
+            AND f.userIdAdded = o.userIdAdder
+            AND f.userIdAdder > f.userIdAdded
+        RETURNING
+            f.userIdAdder,
+            f.userIdAdded
+    ),
+    removed_users AS (
+        SELECT userIdAdder AS id FROM removed
+        UNION ALL
+        SELECT userIdAdded FROM removed
+    )
+UPDATE users
+SET
+    friendCount = friendCount - r.count
+FROM
+    (SELECT id, COUNT(*) AS count FROM removed_users GROUP BY id) r
+WHERE
+    users.id = r.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friends_pair ON friends (LEAST (userIdAdder, userIdAdded), GREATEST (userIdAdder, userIdAdded));
+
+CREATE TRIGGER update_friend_requests_updated_at
+    BEFORE UPDATE
+    ON friend_requests
+    FOR EACH ROW
+    EXECUTE PROCEDURE trigger_set_updated();
@@ -0,0 +1,2 @@
+ALTER TABLE posts
+DROP COLUMN deletedAt;
\ No newline at end of file
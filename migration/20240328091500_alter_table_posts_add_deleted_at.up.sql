@@ -0,0 +1,2 @@
+ALTER TABLE posts
+ADD COLUMN deletedAt TIMESTAMP;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE posts
+DROP COLUMN bodyOriginal;
+
+ALTER TABLE post_comments
+DROP COLUMN commentOriginal;
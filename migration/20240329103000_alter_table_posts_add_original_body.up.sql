@@ -0,0 +1,6 @@
+-- original user input before sanitization, null when the input was already clean
+ALTER TABLE posts
+ADD COLUMN bodyOriginal TEXT;
+
+ALTER TABLE post_comments
+ADD COLUMN commentOriginal TEXT;
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS post_comment_reactions;
+
+DROP TABLE IF EXISTS post_reactions;
+
+ALTER TABLE post_comments
+DROP COLUMN likeCount,
+DROP COLUMN loveCount,
+DROP COLUMN hahaCount,
+DROP COLUMN wowCount,
+DROP COLUMN sadCount,
+DROP COLUMN angryCount;
+
+ALTER TABLE posts
+DROP COLUMN likeCount,
+DROP COLUMN loveCount,
+DROP COLUMN hahaCount,
+DROP COLUMN wowCount,
+DROP COLUMN sadCount,
+DROP COLUMN angryCount;
@@ -0,0 +1,55 @@
+ALTER TABLE posts
+ADD COLUMN likeCount INT DEFAULT 0,
+ADD COLUMN loveCount INT DEFAULT 0,
+ADD COLUMN hahaCount INT DEFAULT 0,
+ADD COLUMN wowCount INT DEFAULT 0,
+ADD COLUMN sadCount INT DEFAULT 0,
+ADD COLUMN angryCount INT DEFAULT 0;
+
+ALTER TABLE post_comments
+ADD COLUMN likeCount INT DEFAULT 0,
+ADD COLUMN loveCount INT DEFAULT 0,
+ADD COLUMN hahaCount INT DEFAULT 0,
+ADD COLUMN wowCount INT DEFAULT 0,
+ADD COLUMN sadCount INT DEFAULT 0,
+ADD COLUMN angryCount INT DEFAULT 0;
+
+CREATE TABLE
+    IF NOT EXISTS post_reactions (
+        postId UUID NOT NULL,
+        userId UUID NOT NULL,
+        type VARCHAR(20) NOT NULL,
+        createdAt TIMESTAMP DEFAULT now (),
+        updatedAt TIMESTAMP DEFAULT now (),
+
+        PRIMARY KEY (postId, userId),
+        CONSTRAINT fk_post FOREIGN KEY (postId) REFERENCES posts (id) ON DELETE CASCADE,
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT chk_type CHECK (type IN ('like', 'love', 'haha', 'wow', 'sad', 'angry'))
+    );
+
+CREATE TRIGGER update_post_reactions_updated_at
+    BEFORE UPDATE
+    ON post_reactions
+    FOR EACH ROW
+    EXECUTE PROCEDURE trigger_set_updated();
+
+CREATE TABLE
+    IF NOT EXISTS post_comment_reactions (
+        commentId UUID NOT NULL,
+        userId UUID NOT NULL,
+        type VARCHAR(20) NOT NULL,
+        createdAt TIMESTAMP DEFAULT now (),
+        updatedAt TIMESTAMP DEFAULT now (),
+
+        PRIMARY KEY (commentId, userId),
+        CONSTRAINT fk_comment FOREIGN KEY (commentId) REFERENCES post_comments (id) ON DELETE CASCADE,
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT chk_type CHECK (type IN ('like', 'love', 'haha', 'wow', 'sad', 'angry'))
+    );
+
+CREATE TRIGGER update_post_comment_reactions_updated_at
+    BEFORE UPDATE
+    ON post_comment_reactions
+    FOR EACH ROW
+    EXECUTE PROCEDURE trigger_set_updated();
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_post_comments_parent_comment_id;
+
+DROP INDEX IF EXISTS idx_post_comments_post_id;
+
+ALTER TABLE posts
+DROP COLUMN IF EXISTS commentCount;
+
+ALTER TABLE post_comments
+DROP CONSTRAINT IF EXISTS fk_parent_comment,
+DROP COLUMN IF EXISTS replyCount,
+DROP COLUMN IF EXISTS parentCommentId;
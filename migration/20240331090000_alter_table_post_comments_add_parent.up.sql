@@ -0,0 +1,20 @@
+ALTER TABLE post_comments
+ADD COLUMN parentCommentId UUID NULL,
+ADD COLUMN replyCount INT DEFAULT 0,
+ADD CONSTRAINT fk_parent_comment FOREIGN KEY (parentCommentId) REFERENCES post_comments (id) ON DELETE CASCADE;
+
+ALTER TABLE posts
+ADD COLUMN commentCount INT DEFAULT 0;
+
+UPDATE posts p
+SET commentCount = c.total
+FROM (
+    SELECT postId, COUNT(*) AS total
+    FROM post_comments
+    GROUP BY postId
+) c
+WHERE c.postId = p.id;
+
+CREATE INDEX IF NOT EXISTS idx_post_comments_post_id ON post_comments (postId, id);
+
+CREATE INDEX IF NOT EXISTS idx_post_comments_parent_comment_id ON post_comments (parentCommentId, id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS post_comment_revisions;
+
+ALTER TABLE post_comments
+DROP COLUMN IF EXISTS deletedAt,
+DROP COLUMN IF EXISTS editedAt;
@@ -0,0 +1,16 @@
+ALTER TABLE post_comments
+ADD COLUMN editedAt TIMESTAMP NULL,
+ADD COLUMN deletedAt TIMESTAMP NULL;
+
+CREATE TABLE
+    IF NOT EXISTS post_comment_revisions (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        commentId UUID NOT NULL,
+        comment TEXT NOT NULL,
+        commentOriginal TEXT NULL,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_comment FOREIGN KEY (commentId) REFERENCES post_comments (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_post_comment_revisions_comment_id ON post_comment_revisions (commentId, id);
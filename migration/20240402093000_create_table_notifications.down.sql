@@ -0,0 +1,4 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS notificationOptOut;
+
+DROP TABLE IF EXISTS notifications;
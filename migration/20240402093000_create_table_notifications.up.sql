@@ -0,0 +1,31 @@
+CREATE TABLE
+    IF NOT EXISTS notifications (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        userId UUID NOT NULL,
+        actorId UUID NOT NULL,
+        type VARCHAR(30) NOT NULL,
+        postId UUID NULL,
+        commentId UUID NULL,
+        readAt TIMESTAMP NULL,
+        createdAt TIMESTAMP DEFAULT now (),
+        updatedAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT fk_actor FOREIGN KEY (actorId) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT fk_post FOREIGN KEY (postId) REFERENCES posts (id) ON DELETE CASCADE,
+        CONSTRAINT fk_comment FOREIGN KEY (commentId) REFERENCES post_comments (id) ON DELETE CASCADE,
+        CONSTRAINT chk_type CHECK (type IN ('friend_request', 'friend_accepted', 'comment', 'reply', 'mention'))
+    );
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (userId, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_unread ON notifications (userId) WHERE readAt IS NULL;
+
+CREATE TRIGGER update_notifications_updated_at
+    BEFORE UPDATE
+    ON notifications
+    FOR EACH ROW
+    EXECUTE PROCEDURE trigger_set_updated();
+
+ALTER TABLE users
+ADD COLUMN notificationOptOut TEXT[] NOT NULL DEFAULT '{}';
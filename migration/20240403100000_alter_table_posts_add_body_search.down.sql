@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS idx_posts ON posts (body);
+
+DROP INDEX IF EXISTS idx_posts_body_search;
+
+ALTER TABLE posts
+DROP COLUMN IF EXISTS bodySearch;
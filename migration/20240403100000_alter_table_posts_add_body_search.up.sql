@@ -0,0 +1,9 @@
+-- html tags are stripped so markup and attribute values are not indexed
+ALTER TABLE posts
+ADD COLUMN bodySearch TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('english', regexp_replace(body, '<[^>]*>', ' ', 'g'))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_posts_body_search ON posts USING GIN (bodySearch);
+
+DROP INDEX IF EXISTS idx_posts CASCADE;
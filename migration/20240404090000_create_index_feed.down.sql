@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_user_id_id;
+
+DROP INDEX IF EXISTS idx_friends_user_id_added;
@@ -0,0 +1,5 @@
+-- friends is keyed by (userIdAdder, userIdAdded), this index serves the lookup from the other side
+CREATE INDEX IF NOT EXISTS idx_friends_user_id_added ON friends (userIdAdded, userIdAdder);
+
+-- newest posts of each feed user, deleted posts are never part of a feed
+CREATE INDEX IF NOT EXISTS idx_posts_user_id_id ON posts (userId, id DESC) WHERE deletedAt IS NULL;
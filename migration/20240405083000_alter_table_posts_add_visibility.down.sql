@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_public_id;
+
+ALTER TABLE posts
+DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,6 @@
+ALTER TABLE posts
+ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'friends'
+    CONSTRAINT chk_posts_visibility CHECK (visibility IN ('public', 'friends', 'only_me'));
+
+-- newest public posts for the discover list
+CREATE INDEX IF NOT EXISTS idx_posts_public_id ON posts (id DESC) WHERE visibility = 'public' AND deletedAt IS NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_created_at;
+
+DROP TABLE IF EXISTS tag_trends;
+
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,42 @@
+-- tags are stored normalized: trimmed, lower cased, without the leading # and without duplicates
+UPDATE posts p
+SET tags = n.tags
+FROM (
+    SELECT x.id, COALESCE(array_agg(DISTINCT x.tag) FILTER (WHERE x.tag <> ''), '{}') AS tags
+    FROM (
+        SELECT posts.id, lower(ltrim(btrim(tag), '#')) AS tag
+        FROM posts, unnest(posts.tags) AS tag
+    ) x
+    GROUP BY x.id
+) n
+WHERE p.id = n.id AND p.tags IS DISTINCT FROM n.tags;
+
+CREATE TABLE
+    IF NOT EXISTS tags (
+        name VARCHAR(255) PRIMARY KEY,
+        useCount INT NOT NULL DEFAULT 0,
+        lastUsedAt TIMESTAMP NOT NULL DEFAULT now ()
+    );
+
+-- prefix search of the autocomplete
+CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags (name varchar_pattern_ops);
+
+INSERT INTO tags (name, useCount, lastUsedAt)
+SELECT tag, COUNT(*), MAX(createdAt)
+FROM posts, unnest(posts.tags) AS tag
+WHERE deletedAt IS NULL AND visibility = 'public'
+GROUP BY tag
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE
+    IF NOT EXISTS tag_trends (
+        name VARCHAR(255) PRIMARY KEY,
+        score DOUBLE PRECISION NOT NULL,
+        postCount INT NOT NULL,
+        refreshedAt TIMESTAMP NOT NULL DEFAULT now ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_tag_trends_score ON tag_trends (score DESC);
+
+-- posts of the trending window
+CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts (createdAt) WHERE deletedAt IS NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE
+    IF NOT EXISTS post_mentions (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        postId UUID NOT NULL,
+        commentId UUID,
+        userId UUID NOT NULL,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_post FOREIGN KEY (postId) REFERENCES posts (id) ON DELETE CASCADE,
+        CONSTRAINT fk_comment FOREIGN KEY (commentId) REFERENCES post_comments (id) ON DELETE CASCADE,
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+-- a user is mentioned once per post body and once per comment
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_mentions_post_user ON post_mentions (postId, userId) WHERE commentId IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_mentions_comment_user ON post_mentions (commentId, userId) WHERE commentId IS NOT NULL;
+
+-- posts the user is mentioned in
+CREATE INDEX IF NOT EXISTS idx_post_mentions_user_post ON post_mentions (userId, postId);
@@ -0,0 +1,24 @@
+CREATE TABLE
+    IF NOT EXISTS user_blocks (
+        userIdBlocker UUID NOT NULL,
+        userIdBlocked UUID NOT NULL,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        PRIMARY KEY (userIdBlocker, userIdBlocked),
+        CONSTRAINT fk_userIdBlocker FOREIGN KEY (userIdBlocker) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT fk_userIdBlocked FOREIGN KEY (userIdBlocked) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+-- blocks are checked in both directions
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks (userIdBlocked, userIdBlocker);
+
+CREATE TABLE
+    IF NOT EXISTS user_mutes (
+        userIdMuter UUID NOT NULL,
+        userIdMuted UUID NOT NULL,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        PRIMARY KEY (userIdMuter, userIdMuted),
+        CONSTRAINT fk_userIdMuter FOREIGN KEY (userIdMuter) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT fk_userIdMuted FOREIGN KEY (userIdMuted) REFERENCES users (id) ON DELETE CASCADE
+    );
@@ -0,0 +1,22 @@
+CREATE TABLE
+    IF NOT EXISTS password_resets (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        userId UUID NOT NULL,
+        channel VARCHAR(10) NOT NULL,
+        otpHash VARCHAR(255) NOT NULL,
+        attempts INT NOT NULL DEFAULT 0,
+        expiresAt TIMESTAMP NOT NULL,
+        usedAt TIMESTAMP,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT chk_channel CHECK (channel IN ('email', 'phone'))
+    );
+
+-- only the latest unused reset of the user can be used
+CREATE INDEX IF NOT EXISTS idx_password_resets_user_active ON password_resets (userId, createdAt DESC)
+WHERE
+    usedAt IS NULL;
+
+-- resets sent to the user inside the throttling window, used or not
+CREATE INDEX IF NOT EXISTS idx_password_resets_user_created_at ON password_resets (userId, createdAt DESC);
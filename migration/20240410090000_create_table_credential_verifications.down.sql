@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS credential_verifications;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS emailVerifiedAt,
+DROP COLUMN IF EXISTS phoneVerifiedAt;
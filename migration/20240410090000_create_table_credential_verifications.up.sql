@@ -0,0 +1,38 @@
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS emailVerifiedAt TIMESTAMP,
+ADD COLUMN IF NOT EXISTS phoneVerifiedAt TIMESTAMP;
+
+-- credentials linked before the verification existed are trusted, later ones are only stored in the user once verified
+UPDATE users
+SET
+    emailVerifiedAt = createdAt
+WHERE
+    email IS NOT NULL;
+
+UPDATE users
+SET
+    phoneVerifiedAt = createdAt
+WHERE
+    phone IS NOT NULL;
+
+CREATE TABLE
+    IF NOT EXISTS credential_verifications (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        userId UUID NOT NULL,
+        channel VARCHAR(10) NOT NULL,
+        value VARCHAR(255) NOT NULL,
+        otpHash VARCHAR(255) NOT NULL,
+        attempts INT NOT NULL DEFAULT 0,
+        expiresAt TIMESTAMP NOT NULL,
+        usedAt TIMESTAMP,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE,
+        CONSTRAINT chk_channel CHECK (channel IN ('email', 'phone'))
+    );
+
+-- the codes are looked up by user, the address is only used for throttling, used codes included
+CREATE INDEX IF NOT EXISTS idx_credential_verifications_value ON credential_verifications (channel, value, createdAt DESC);
+
+-- resend throttling looks up the latest code of the user
+CREATE INDEX IF NOT EXISTS idx_credential_verifications_user ON credential_verifications (userId, channel, createdAt DESC);
@@ -0,0 +1,39 @@
+CREATE TABLE
+    IF NOT EXISTS user_totps (
+        userId UUID PRIMARY KEY,
+        secret VARCHAR(64) NOT NULL,
+        -- the secret is only used for login once the user proved the authenticator app works
+        enabledAt TIMESTAMP,
+        -- the last accepted time step, a code cannot be used twice
+        lastUsedStep BIGINT NOT NULL DEFAULT 0,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE TABLE
+    IF NOT EXISTS user_recovery_codes (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        userId UUID NOT NULL,
+        codeHash VARCHAR(255) NOT NULL,
+        usedAt TIMESTAMP,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user ON user_recovery_codes (userId)
+WHERE
+    usedAt IS NULL;
+
+CREATE TABLE
+    IF NOT EXISTS login_challenges (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        userId UUID NOT NULL,
+        attempts INT NOT NULL DEFAULT 0,
+        expiresAt TIMESTAMP NOT NULL,
+        usedAt TIMESTAMP,
+        createdAt TIMESTAMP DEFAULT now (),
+
+        CONSTRAINT fk_user FOREIGN KEY (userId) REFERENCES users (id) ON DELETE CASCADE
+    );
@@ -0,0 +1,4 @@
+ALTER TABLE login_challenges
+DROP COLUMN IF EXISTS credentialKey;
+
+DROP TABLE IF EXISTS login_attempts;
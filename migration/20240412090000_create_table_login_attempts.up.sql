@@ -0,0 +1,13 @@
+CREATE TABLE
+    IF NOT EXISTS login_attempts (
+        -- scope and value of the key, e.g. credential:email:john@mail.com or ip:127.0.0.1
+        key VARCHAR(255) PRIMARY KEY,
+        failures INT NOT NULL DEFAULT 0,
+        lastFailureAt TIMESTAMP NOT NULL,
+        blockedUntil TIMESTAMP,
+        locked BOOLEAN NOT NULL DEFAULT false
+    );
+
+-- the wrong codes of the challenge count as failed logins of the credential the password was checked for
+ALTER TABLE login_challenges
+ADD COLUMN IF NOT EXISTS credentialKey VARCHAR(300) NOT NULL DEFAULT '';
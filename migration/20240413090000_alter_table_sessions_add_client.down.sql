@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS device,
+DROP COLUMN IF EXISTS userAgent,
+DROP COLUMN IF EXISTS ip,
+DROP COLUMN IF EXISTS lastSeenAt;
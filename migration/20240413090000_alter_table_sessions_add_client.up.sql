@@ -0,0 +1,11 @@
+ALTER TABLE sessions
+ADD COLUMN IF NOT EXISTS device VARCHAR(255),
+ADD COLUMN IF NOT EXISTS userAgent VARCHAR(512),
+ADD COLUMN IF NOT EXISTS ip VARCHAR(64),
+ADD COLUMN IF NOT EXISTS lastSeenAt TIMESTAMP NOT NULL DEFAULT now ();
+
+UPDATE sessions
+SET
+    lastSeenAt = updatedAt
+WHERE
+    updatedAt IS NOT NULL;
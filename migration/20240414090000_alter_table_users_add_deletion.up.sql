@@ -0,0 +1,6 @@
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS deletionScheduledAt TIMESTAMP NULL,
+ADD COLUMN IF NOT EXISTS deletedAt TIMESTAMP NULL;
+
+-- accounts waiting for the deletion worker
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletionScheduledAt) WHERE deletionScheduledAt IS NOT NULL;
@@ -2,6 +2,7 @@ package constant
 
 import (
 	"errors"
+	"maps"
 	"net/http"
 )
 
@@ -20,23 +21,84 @@ var (
 	ErrFriendUseralreadyAdded        = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user already added as friend"}
 	ErrFriendSelfDeleting            = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "cannot delete self as friend"}
 	ErrFriendUserNotAdded            = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user not found in friend list"}
+	ErrUserBlocked                   = &ErrWithCode{HTTPStatusCode: http.StatusForbidden, Message: "cannot interact with a blocked user"}
+	ErrUserSelfBlocking              = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "cannot block self"}
+	ErrUserAlreadyBlocked            = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user already blocked"}
+	ErrUserNotBlocked                = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user not found in block list"}
+	ErrUserSelfMuting                = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "cannot mute self"}
+	ErrUserAlreadyMuted              = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user already muted"}
+	ErrUserNotMuted                  = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user not found in mute list"}
 	ErrPostNotFound                  = &ErrWithCode{HTTPStatusCode: http.StatusNotFound, Message: "post not found"}
+	ErrCommentNotFound               = &ErrWithCode{HTTPStatusCode: http.StatusNotFound, Message: "comment not found"}
+	ErrInvalidCursor                 = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "invalid cursor"}
+	ErrCursorSortUnsupported         = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "cursor pagination only supports sorting by createdAt"}
+	ErrReactionNotFound              = &ErrWithCode{HTTPStatusCode: http.StatusNotFound, Message: "reaction not found"}
+	ErrReactionAlreadyExists         = &ErrWithCode{HTTPStatusCode: http.StatusConflict, Message: "reaction already exists"}
+	ErrNotificationNotFound          = &ErrWithCode{HTTPStatusCode: http.StatusNotFound, Message: "notification not found"}
 	ErrUserNotFriend                 = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user is not friend with post owner"}
 	ErrPhoneAlreadyRegistered        = &ErrWithCode{HTTPStatusCode: http.StatusConflict, Message: "phone already registered"}
 	ErrUserAlreadyHavePhone          = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user already have phone"}
 	ErrEmailAlreadyRegistered        = &ErrWithCode{HTTPStatusCode: http.StatusConflict, Message: "email already registered"}
 	ErrUserAlreadyHaveEmail          = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "user already have email"}
+	ErrFriendRequestNotFound         = &ErrWithCode{HTTPStatusCode: http.StatusNotFound, Message: "friend request not found"}
+	ErrFriendRequestAlreadySent      = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "friend request already sent"}
+	ErrFriendRequestNotPending       = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "friend request is no longer pending"}
+	ErrSessionNotFound               = &ErrWithCode{HTTPStatusCode: http.StatusNotFound, Message: "session not found"}
+	ErrRefreshTokenInvalid           = &ErrWithCode{HTTPStatusCode: http.StatusUnauthorized, Message: "invalid or expired refresh token"}
+	ErrRefreshTokenReused            = &ErrWithCode{HTTPStatusCode: http.StatusUnauthorized, Message: "refresh token already used, session revoked"}
+	ErrOldPasswordInvalid            = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "old password invalid"}
+	ErrOTPInvalid                    = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "invalid or expired otp"}
+	ErrOTPTooManyAttempts            = &ErrWithCode{HTTPStatusCode: http.StatusTooManyRequests, Message: "too many wrong otp, request a new one"}
+	ErrOTPResendTooSoon              = &ErrWithCode{HTTPStatusCode: http.StatusTooManyRequests, Message: "otp already sent, wait before requesting a new one"}
+	ErrOTPSendLimitReached           = &ErrWithCode{HTTPStatusCode: http.StatusTooManyRequests, Message: "too many otp sent to the address, try again later"}
+	ErrCredentialNotVerified         = &ErrWithCode{HTTPStatusCode: http.StatusForbidden, Message: "credential not verified, verify it with the otp sent to it"}
+	ErrVerificationNotFound          = &ErrWithCode{HTTPStatusCode: http.StatusNotFound, Message: "verification not found"}
+	ErrTwoFactorAlreadyEnabled       = &ErrWithCode{HTTPStatusCode: http.StatusConflict, Message: "two factor authentication already enabled"}
+	ErrTwoFactorNotEnrolled          = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "two factor authentication not enrolled"}
+	ErrTwoFactorNotEnabled           = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "two factor authentication not enabled"}
+	ErrTwoFactorCodeInvalid          = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "invalid two factor code"}
+	ErrLoginChallengeInvalid         = &ErrWithCode{HTTPStatusCode: http.StatusUnauthorized, Message: "invalid or expired challenge token"}
+	ErrLoginTooManyAttempts          = &ErrWithCode{HTTPStatusCode: http.StatusTooManyRequests, Message: "too many failed login attempts, try again later"}
+	ErrPasswordInvalid               = &ErrWithCode{HTTPStatusCode: http.StatusBadRequest, Message: "password invalid"}
 )
 
 type ErrWithCode struct {
 	HTTPStatusCode int
 	Message        string
+	// Headers are set on the error response, see WithHeader
+	Headers map[string]string
+
+	parent *ErrWithCode
 }
 
 func (e *ErrWithCode) Error() string {
 	return e.Message
 }
 
+// WithHeader returns a copy of the error that sets the header on the error response,
+// the copy still matches the original error with errors.Is
+func (e *ErrWithCode) WithHeader(key, value string) *ErrWithCode {
+	headers := make(map[string]string, len(e.Headers)+1)
+	maps.Copy(headers, e.Headers)
+	headers[key] = value
+
+	parent := e
+	if e.parent != nil {
+		parent = e.parent
+	}
+
+	return &ErrWithCode{
+		HTTPStatusCode: e.HTTPStatusCode,
+		Message:        e.Message,
+		Headers:        headers,
+		parent:         parent,
+	}
+}
+
+func (e *ErrWithCode) Is(target error) bool {
+	return e.parent != nil && e.parent == target
+}
+
 type ErrValidation struct {
 	Message string
 }
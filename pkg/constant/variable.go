@@ -8,4 +8,8 @@ var (
 
 const (
 	TimeISO8601Format = "2006-01-02T15:04:05Z"
+
+	// JWTPurposeLoginChallenge marks the token returned by the first step of a two factor login,
+	// it is only accepted by the second step and never as an access token
+	JWTPurposeLoginChallenge = "login_challenge"
 )
@@ -0,0 +1,116 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/google/uuid"
+)
+
+// scopes bind a cursor to the list it was issued for
+const (
+	ScopePost    = "post"
+	ScopeUser    = "user"
+	ScopeComment = "comment"
+)
+
+// Cursor points at the row a keyset page starts after,
+// Prev means the page before the row is requested instead of the page after it
+type Cursor struct {
+	Scope string `json:"s"`
+	ID    string `json:"id"`
+	Prev  bool   `json:"p,omitempty"`
+}
+
+// Encode returns an opaque token of the cursor signed with the cursor secret
+func Encode(c Cursor) string {
+	payload, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sign(payload))
+}
+
+// Decode verifies the token and returns the cursor,
+// an empty token returns an empty cursor which means the first page
+func Decode(scope, token string) (c Cursor, err error) {
+	if token == "" {
+		return
+	}
+
+	payloadStr, signatureStr, ok := strings.Cut(token, ".")
+	if !ok {
+		return c, constant.ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadStr)
+	if err != nil {
+		return c, constant.ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(signatureStr)
+	if err != nil {
+		return c, constant.ErrInvalidCursor
+	}
+
+	if !hmac.Equal(signature, sign(payload)) {
+		return c, constant.ErrInvalidCursor
+	}
+
+	err = json.Unmarshal(payload, &c)
+	if err != nil {
+		return c, constant.ErrInvalidCursor
+	}
+
+	if c.Scope != scope {
+		return Cursor{}, constant.ErrInvalidCursor
+	}
+
+	if _, err = uuid.Parse(c.ID); err != nil {
+		return Cursor{}, constant.ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// Paginate returns the next and previous cursor of a page,
+// ids are the row ids of the page in display order, current is the cursor used to get the page
+// and hasMore reports whether the query found a row beyond the page in the requested direction
+func Paginate(scope string, ids []string, current Cursor, hasMore bool) (next, prev string) {
+	// an empty page is bounded by the cursor itself
+	first, last := current.ID, current.ID
+	if len(ids) > 0 {
+		first, last = ids[0], ids[len(ids)-1]
+	}
+
+	if last != "" && (hasMore || current.Prev) {
+		next = Encode(Cursor{Scope: scope, ID: last})
+	}
+
+	if first != "" && current.ID != "" && (hasMore || !current.Prev) {
+		prev = Encode(Cursor{Scope: scope, ID: first, Prev: true})
+	}
+
+	return
+}
+
+func sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret()))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// secret falls back to a key derived from the jwt secret so a deployment does not need another secret to use cursors,
+// the jwt secret itself is never reused as the cursor key
+func secret() string {
+	if s := config.Get().Cursor.Secret; s != "" {
+		return s
+	}
+
+	mac := hmac.New(sha256.New, []byte(config.Get().JWT.Secret))
+	mac.Write([]byte("cursor"))
+	return string(mac.Sum(nil))
+}
@@ -0,0 +1,161 @@
+package cursor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+)
+
+const (
+	idA = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e6f"
+	idB = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e70"
+	idC = "018e6f5a-7c3d-7b1a-9f2e-1a2b3c4d5e71"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "next page", cursor: Cursor{Scope: ScopePost, ID: idA}},
+		{name: "previous page", cursor: Cursor{Scope: ScopeUser, ID: idB, Prev: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.cursor.Scope, Encode(tt.cursor))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if got != tt.cursor {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	valid := Encode(Cursor{Scope: ScopePost, ID: idA})
+	payload, signature, _ := strings.Cut(valid, ".")
+	other := Encode(Cursor{Scope: ScopePost, ID: idB})
+	_, otherSignature, _ := strings.Cut(other, ".")
+
+	tests := []struct {
+		name    string
+		scope   string
+		token   string
+		want    Cursor
+		wantErr bool
+	}{
+		{name: "empty token is the first page", scope: ScopePost, token: ""},
+		{name: "valid", scope: ScopePost, token: valid, want: Cursor{Scope: ScopePost, ID: idA}},
+		{name: "without signature", scope: ScopePost, token: payload, wantErr: true},
+		{name: "signature of another payload", scope: ScopePost, token: payload + "." + otherSignature, wantErr: true},
+		{name: "tampered signature", scope: ScopePost, token: payload + "." + signature + "A", wantErr: true},
+		{name: "not base64", scope: ScopePost, token: "!!!." + signature, wantErr: true},
+		{name: "other scope", scope: ScopeComment, token: valid, wantErr: true},
+		{name: "id is not an uuid", scope: ScopePost, token: Encode(Cursor{Scope: ScopePost, ID: "1 OR 1=1"}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.scope, tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, constant.ErrInvalidCursor) {
+					t.Fatalf("Decode() error = %v, want %v", err, constant.ErrInvalidCursor)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []string
+		current  Cursor
+		hasMore  bool
+		wantNext Cursor
+		wantPrev Cursor
+	}{
+		{
+			name:     "first page with more rows",
+			ids:      []string{idA, idB},
+			hasMore:  true,
+			wantNext: Cursor{Scope: ScopePost, ID: idB},
+		},
+		{
+			name: "first page without more rows",
+			ids:  []string{idA, idB},
+		},
+		{
+			name:     "next page with more rows",
+			ids:      []string{idB, idC},
+			current:  Cursor{Scope: ScopePost, ID: idA},
+			hasMore:  true,
+			wantNext: Cursor{Scope: ScopePost, ID: idC},
+			wantPrev: Cursor{Scope: ScopePost, ID: idB, Prev: true},
+		},
+		{
+			name:     "last page",
+			ids:      []string{idB, idC},
+			current:  Cursor{Scope: ScopePost, ID: idA},
+			wantPrev: Cursor{Scope: ScopePost, ID: idB, Prev: true},
+		},
+		{
+			name:     "previous page with more rows",
+			ids:      []string{idA, idB},
+			current:  Cursor{Scope: ScopePost, ID: idC, Prev: true},
+			hasMore:  true,
+			wantNext: Cursor{Scope: ScopePost, ID: idB},
+			wantPrev: Cursor{Scope: ScopePost, ID: idA, Prev: true},
+		},
+		{
+			name:     "previous page reaching the start",
+			ids:      []string{idA, idB},
+			current:  Cursor{Scope: ScopePost, ID: idC, Prev: true},
+			wantNext: Cursor{Scope: ScopePost, ID: idB},
+		},
+		{
+			name:     "empty page is bounded by the cursor",
+			current:  Cursor{Scope: ScopePost, ID: idA},
+			wantPrev: Cursor{Scope: ScopePost, ID: idA, Prev: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, prev := Paginate(ScopePost, tt.ids, tt.current, tt.hasMore)
+
+			gotNext, err := Decode(ScopePost, next)
+			if err != nil {
+				t.Fatalf("Decode(next) error = %v", err)
+			}
+
+			gotPrev, err := Decode(ScopePost, prev)
+			if err != nil {
+				t.Fatalf("Decode(prev) error = %v", err)
+			}
+
+			if gotNext != tt.wantNext {
+				t.Errorf("Paginate() next = %+v, want %+v", gotNext, tt.wantNext)
+			}
+
+			if gotPrev != tt.wantPrev {
+				t.Errorf("Paginate() prev = %+v, want %+v", gotPrev, tt.wantPrev)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package postgrestest runs the repository tests against the database configured through the DB_ environment variables
+package postgrestest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// BeginTx returns a transaction on the migrated database, it is rolled back once the test is done
+// so the test can seed what it needs. The test is skipped when DB_HOST is not set
+func BeginTx(ctx context.Context, tb testing.TB) pgx.Tx {
+	tb.Helper()
+
+	if os.Getenv("DB_HOST") == "" {
+		tb.Skip("DB_HOST is not set, the test needs a migrated database")
+	}
+
+	_, err := config.LoadConfig()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	_, err = config.ParseConfig(config.GetViper())
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	db, err := dbpostgres.NewPgxPool()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(db.Close)
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		tb.Fatalf("failed to begin transaction: %v", err)
+	}
+	tb.Cleanup(func() { tx.Rollback(ctx) })
+
+	return tx
+}
+
+// CreateUser inserts a user with the name and returns its id
+func CreateUser(ctx context.Context, tb testing.TB, tx pgx.Tx, name string) string {
+	tb.Helper()
+
+	var id string
+	err := tx.QueryRow(ctx, `INSERT INTO users (name, password) VALUES ($1, '-') RETURNING id`, name).Scan(&id)
+	if err != nil {
+		tb.Fatalf("failed to create user %s: %v", name, err)
+	}
+
+	return id
+}
+
+// Block stores the block of the blocked user by the blocker
+func Block(ctx context.Context, tb testing.TB, tx pgx.Tx, blockerID, blockedID string) {
+	tb.Helper()
+
+	_, err := tx.Exec(ctx, `INSERT INTO user_blocks (userIdBlocker, userIdBlocked) VALUES ($1, $2)`, blockerID, blockedID)
+	if err != nil {
+		tb.Fatalf("failed to block user: %v", err)
+	}
+}
@@ -52,6 +52,10 @@ func FiberErrorHandler(ctx *fiber.Ctx, err error) error {
 		if withCodeErr.Message != "" {
 			defaultRes.Message = withCodeErr.Message
 		}
+
+		for key, value := range withCodeErr.Headers {
+			ctx.Set(key, value)
+		}
 	}
 
 	var fiberError *fiber.Error
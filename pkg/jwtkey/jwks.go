@@ -0,0 +1,104 @@
+package jwtkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// JWK is the public part of a key as described in RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// N and E are set for RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Crv and X are set for Ed25519 keys
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that verify the tokens, a key set signing with the secret publishes none
+func (ks *KeySet) JWKS() JWKS {
+	res := JWKS{Keys: []JWK{}}
+
+	for id, k := range ks.keys {
+		jwk, ok := publicJWK(k.verify)
+		if !ok {
+			continue
+		}
+
+		jwk.Kid = id
+		jwk.Use = "sig"
+		jwk.Alg = k.method.Alg()
+		res.Keys = append(res.Keys, jwk)
+	}
+
+	sort.Slice(res.Keys, func(i, j int) bool { return res.Keys[i].Kid < res.Keys[j].Kid })
+
+	return res
+}
+
+func publicJWK(pub any) (jwk JWK, ok bool) {
+	switch v := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(v.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(v.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(v),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+// thumbprint is the RFC 7638 thumbprint of the public key, it is used as kid
+// so every replica loading the same key files derives the same key ids
+func thumbprint(pub any) (string, error) {
+	jwk, ok := publicJWK(pub)
+	if !ok {
+		return "", fmt.Errorf("jwtkey: unsupported public key type %T", pub)
+	}
+
+	// the members are required to be in lexicographic order, which is the order of the struct fields below
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", fmt.Errorf("jwtkey: failed to marshal thumbprint members: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
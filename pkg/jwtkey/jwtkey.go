@@ -0,0 +1,177 @@
+package jwtkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// hmacKeyID is the kid of the tokens signed with JWT_SECRET, the secret itself is never published
+const hmacKeyID = "hs256"
+
+var ErrUnknownKey = errors.New("jwtkey: unknown key id")
+
+type key struct {
+	id     string
+	method jwt.SigningMethod
+	// sign is only set for the signing key
+	sign   any
+	verify any
+}
+
+// KeySet signs the tokens with a single key and verifies them with every key it knows,
+// the previous keys are kept during a rotation so the tokens they signed stay valid until they expire
+type KeySet struct {
+	signing *key
+	keys    map[string]*key
+}
+
+// Load creates the key set from the config, the tokens are signed with JWT_SECRET when JWT_PRIVATE_KEY_FILE is not set
+func Load() (*KeySet, error) {
+	cfg := config.Get().JWT
+
+	if cfg.PrivateKeyFile == "" {
+		return NewHMAC([]byte(cfg.Secret)), nil
+	}
+
+	return load(cfg.PrivateKeyFile, cfg.VerificationKeyFiles)
+}
+
+// load creates the key set signing with the private key file,
+// verificationKeyFiles is the comma separated list of the previous public keys still accepted
+func load(privateKeyFile, verificationKeyFiles string) (*KeySet, error) {
+	signing, err := loadKey(privateKeyFile, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{
+		signing: signing,
+		keys:    map[string]*key{signing.id: signing},
+	}
+
+	for _, file := range strings.Split(verificationKeyFiles, ",") {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+
+		k, err := loadKey(file, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := ks.keys[k.id]; !ok {
+			ks.keys[k.id] = k
+		}
+	}
+
+	return ks, nil
+}
+
+// NewHMAC creates a key set that signs and verifies the tokens with HS256,
+// the tokens issued before the kid header was added are accepted as well
+func NewHMAC(secret []byte) *KeySet {
+	k := &key{id: hmacKeyID, method: jwt.SigningMethodHS256, sign: secret, verify: secret}
+
+	return &KeySet{
+		signing: k,
+		keys:    map[string]*key{k.id: k, "": k},
+	}
+}
+
+// Sign returns the signed token with the kid of the signing key in its header
+func (ks *KeySet) Sign(claims jwt.Claims) (token string, err error) {
+	t := jwt.NewWithClaims(ks.signing.method, claims)
+	t.Header["kid"] = ks.signing.id
+
+	token, err = t.SignedString(ks.signing.sign)
+	if err != nil {
+		return "", fmt.Errorf("jwtkey: failed to sign token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Parse verifies the token with the key of its kid, the algorithm of the token must be the one of the key
+func (ks *KeySet) Parse(token string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(token, claims, ks.keyFunc)
+}
+
+func (ks *KeySet) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+
+	if t.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("jwtkey: invalid token signing algorithm %s", t.Method.Alg())
+	}
+
+	return k.verify, nil
+}
+
+// loadKey reads a pem key, a private key is required for the signing key
+func loadKey(file string, private bool) (*key, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkey: failed to read key file %s: %w", file, err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("jwtkey: no pem block in key file %s", file)
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwtkey: unsupported pem block %s in key file %s", block.Type, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwtkey: failed to parse key file %s: %w", file, err)
+	}
+
+	k := &key{}
+	switch v := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.method, k.sign, k.verify = jwt.SigningMethodRS256, v, &v.PublicKey
+	case *rsa.PublicKey:
+		k.method, k.verify = jwt.SigningMethodRS256, v
+	case ed25519.PrivateKey:
+		k.method, k.sign, k.verify = jwt.SigningMethodEdDSA, v, v.Public()
+	case ed25519.PublicKey:
+		k.method, k.verify = jwt.SigningMethodEdDSA, v
+	default:
+		return nil, fmt.Errorf("jwtkey: unsupported key type %T in key file %s, use RSA or Ed25519", parsed, file)
+	}
+
+	if private && k.sign == nil {
+		return nil, fmt.Errorf("jwtkey: key file %s is not a private key", file)
+	}
+
+	k.id, err = thumbprint(k.verify)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
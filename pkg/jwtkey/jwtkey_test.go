@@ -0,0 +1,324 @@
+package jwtkey
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	rsaKey, _            = rsa.GenerateKey(rand.Reader, 2048)
+	ed25519Pub, edKey, _ = ed25519.GenerateKey(rand.Reader)
+)
+
+// writePEM writes the der bytes as a pem block into a file of the test directory
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	return file
+}
+
+func mustMarshal(t *testing.T, der []byte, err error) []byte {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	return der
+}
+
+func TestLoadKey(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ecdsa key: %v", err)
+	}
+
+	rsaPKCS8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	rsaPKCS8 = mustMarshal(t, rsaPKCS8, err)
+	rsaPKIX, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	rsaPKIX = mustMarshal(t, rsaPKIX, err)
+	edPKCS8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	edPKCS8 = mustMarshal(t, edPKCS8, err)
+	edPKIX, err := x509.MarshalPKIXPublicKey(ed25519Pub)
+	edPKIX = mustMarshal(t, edPKIX, err)
+	ecdsaPKCS8, err := x509.MarshalPKCS8PrivateKey(ecdsaKey)
+	ecdsaPKCS8 = mustMarshal(t, ecdsaPKCS8, err)
+
+	tests := []struct {
+		name       string
+		blockType  string
+		der        []byte
+		private    bool
+		wantMethod jwt.SigningMethod
+		wantErr    bool
+	}{
+		{name: "rsa pkcs8 private key", blockType: "PRIVATE KEY", der: rsaPKCS8, private: true, wantMethod: jwt.SigningMethodRS256},
+		{name: "rsa pkcs1 private key", blockType: "RSA PRIVATE KEY", der: x509.MarshalPKCS1PrivateKey(rsaKey), private: true, wantMethod: jwt.SigningMethodRS256},
+		{name: "rsa pkix public key", blockType: "PUBLIC KEY", der: rsaPKIX, wantMethod: jwt.SigningMethodRS256},
+		{name: "rsa pkcs1 public key", blockType: "RSA PUBLIC KEY", der: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey), wantMethod: jwt.SigningMethodRS256},
+		{name: "ed25519 private key", blockType: "PRIVATE KEY", der: edPKCS8, private: true, wantMethod: jwt.SigningMethodEdDSA},
+		{name: "ed25519 public key", blockType: "PUBLIC KEY", der: edPKIX, wantMethod: jwt.SigningMethodEdDSA},
+		{name: "public key as signing key", blockType: "PUBLIC KEY", der: rsaPKIX, private: true, wantErr: true},
+		{name: "unsupported pem block", blockType: "CERTIFICATE", der: rsaPKIX, wantErr: true},
+		{name: "unsupported key type", blockType: "PRIVATE KEY", der: ecdsaPKCS8, private: true, wantErr: true},
+		{name: "malformed key", blockType: "PRIVATE KEY", der: []byte("not a key"), private: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := loadKey(writePEM(t, tt.blockType, tt.der), tt.private)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadKey() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if k.method != tt.wantMethod {
+				t.Errorf("loadKey() method = %v, want %v", k.method.Alg(), tt.wantMethod.Alg())
+			}
+
+			if tt.private && k.sign == nil {
+				t.Errorf("loadKey() of a private key has no signing key")
+			}
+
+			if !tt.private && k.sign != nil {
+				t.Errorf("loadKey() of a public key has a signing key")
+			}
+		})
+	}
+}
+
+func TestLoadKeyWithoutPEM(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(file, []byte("not a pem file"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	_, err = loadKey(file, true)
+	if err == nil {
+		t.Errorf("loadKey() error = nil, want an error")
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", s, err)
+		}
+
+		return b
+	}
+
+	tests := []struct {
+		name string
+		pub  any
+		want string
+	}{
+		{
+			// RFC 7638 section 3.1
+			name: "rsa",
+			pub: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(decode("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")),
+				E: 65537,
+			},
+			want: "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+		{
+			// RFC 8037 appendix A.3
+			name: "ed25519",
+			pub:  ed25519.PublicKey(decode("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")),
+			want: "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := thumbprint(tt.pub)
+			if err != nil {
+				t.Fatalf("thumbprint() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("thumbprint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotation(t *testing.T) {
+	oldPrivate, err := x509.MarshalPKCS8PrivateKey(edKey)
+	oldPrivate = mustMarshal(t, oldPrivate, err)
+	oldPublic, err := x509.MarshalPKIXPublicKey(ed25519Pub)
+	oldPublic = mustMarshal(t, oldPublic, err)
+
+	oldKeys, err := load(writePEM(t, "PRIVATE KEY", oldPrivate), "")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	oldToken, err := oldKeys.Sign(jwt.RegisteredClaims{Subject: "user"})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	newPrivate := writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	rotated, err := load(newPrivate, " , "+writePEM(t, "PUBLIC KEY", oldPublic))
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	newToken, err := rotated.Sign(jwt.RegisteredClaims{Subject: "user"})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	for name, token := range map[string]string{"old key": oldToken, "new key": newToken} {
+		_, err = rotated.Parse(token, &jwt.RegisteredClaims{})
+		if err != nil {
+			t.Errorf("Parse() of the token signed with the %s error = %v", name, err)
+		}
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error = %v", err)
+	}
+
+	wantKid, _ := thumbprint(&rsaKey.PublicKey)
+	if parsed.Header["kid"] != wantKid {
+		t.Errorf("kid = %v, want %s", parsed.Header["kid"], wantKid)
+	}
+
+	retired, err := load(newPrivate, "")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	_, err = retired.Parse(oldToken, &jwt.RegisteredClaims{})
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Parse() of the token signed with a retired key error = %v, want %v", err, ErrUnknownKey)
+	}
+}
+
+func TestParseRejectsAnotherAlgorithm(t *testing.T) {
+	ks, err := load(writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), "")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	// a token signed with the public key as hmac secret under the kid of the rsa key
+	kid, _ := thumbprint(&rsaKey.PublicKey)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "user"})
+	forged.Header["kid"] = kid
+
+	token, err := forged.SignedString(x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	_, err = ks.Parse(token, &jwt.RegisteredClaims{})
+	if err == nil {
+		t.Errorf("Parse() error = nil, want an error")
+	}
+}
+
+func TestNewHMAC(t *testing.T) {
+	ks := NewHMAC([]byte("secret"))
+
+	token, err := ks.Sign(jwt.RegisteredClaims{Subject: "user"})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	_, err = ks.Parse(token, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	// tokens issued before the kid header was added
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "user"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	_, err = ks.Parse(legacy, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Errorf("Parse() of a token without kid error = %v", err)
+	}
+
+	if keys := ks.JWKS().Keys; len(keys) != 0 {
+		t.Errorf("JWKS() = %+v, want no keys", keys)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	edPublic, err := x509.MarshalPKIXPublicKey(ed25519Pub)
+	edPublic = mustMarshal(t, edPublic, err)
+
+	ks, err := load(writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), writePEM(t, "PUBLIC KEY", edPublic))
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	keys := ks.JWKS().Keys
+	if len(keys) != 2 {
+		t.Fatalf("JWKS() returned %d keys, want 2", len(keys))
+	}
+
+	if keys[0].Kid > keys[1].Kid {
+		t.Errorf("JWKS() keys are not sorted by kid")
+	}
+
+	for _, jwk := range keys {
+		if jwk.Use != "sig" {
+			t.Errorf("JWKS() use = %s, want sig", jwk.Use)
+		}
+
+		switch jwk.Kty {
+		case "RSA":
+			want, _ := thumbprint(&rsaKey.PublicKey)
+			if jwk.Kid != want || jwk.Alg != "RS256" {
+				t.Errorf("JWKS() rsa key = %+v, want kid %s and alg RS256", jwk, want)
+			}
+
+			if jwk.E != "AQAB" || jwk.N != base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()) {
+				t.Errorf("JWKS() rsa key = %+v, want the modulus and exponent of the key", jwk)
+			}
+		case "OKP":
+			want, _ := thumbprint(ed25519Pub)
+			if jwk.Kid != want || jwk.Alg != "EdDSA" || jwk.Crv != "Ed25519" {
+				t.Errorf("JWKS() ed25519 key = %+v, want kid %s, alg EdDSA and crv Ed25519", jwk, want)
+			}
+
+			if jwk.X != base64.RawURLEncoding.EncodeToString(ed25519Pub) {
+				t.Errorf("JWKS() ed25519 key = %+v, want the public key in x", jwk)
+			}
+		default:
+			t.Errorf("JWKS() unexpected key type %s", jwk.Kty)
+		}
+	}
+}
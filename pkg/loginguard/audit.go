@@ -0,0 +1,28 @@
+package loginguard
+
+import (
+	"context"
+
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+)
+
+// LogAuditor writes the events to the log with an audit field so they can be filtered by the log pipeline
+type LogAuditor struct{}
+
+func (LogAuditor) Audit(ctx context.Context, event Event) {
+	log := logger.Log(ctx).Warn().
+		Str("audit", event.Type).
+		Str("scope", event.Key.Scope).
+		Str("key", event.Key.Value).
+		Str("reason", event.Reason)
+
+	if event.Failures > 0 {
+		log = log.Int("failures", event.Failures)
+	}
+
+	if !event.Until.IsZero() {
+		log = log.Time("until", event.Until)
+	}
+
+	log.Msg("loginguard: " + event.Type)
+}
@@ -0,0 +1,228 @@
+package loginguard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+)
+
+const (
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+)
+
+const (
+	ScopeCredential = "credential"
+	ScopeIP         = "ip"
+)
+
+const (
+	EventLocked   = "login_locked"
+	EventUnlocked = "login_unlocked"
+)
+
+// maxBackoffShift keeps the exponential backoff from overflowing before it is capped
+const maxBackoffShift = 30
+
+// Key identifies what the failed attempts are counted for
+type Key struct {
+	Scope string
+	Value string
+}
+
+func (k Key) String() string {
+	return k.Scope + ":" + k.Value
+}
+
+func CredentialKey(credentialType, credentialValue string) Key {
+	return Key{Scope: ScopeCredential, Value: credentialType + ":" + strings.ToLower(credentialValue)}
+}
+
+func IPKey(ip string) Key {
+	return Key{Scope: ScopeIP, Value: ip}
+}
+
+// State is the failed attempts of a key, login is refused until BlockedUntil.
+// Locked tells a lockout apart from the short backoff delays
+type State struct {
+	Failures     int
+	BlockedUntil time.Time
+	Locked       bool
+}
+
+// Store keeps the failed attempts, the postgres driver shares them between replicas
+type Store interface {
+	Get(ctx context.Context, key string) (state State, err error)
+	// Fail counts a failed attempt, the count starts over when the previous failure is older than window
+	Fail(ctx context.Context, key string, now time.Time, window time.Duration) (failures int, err error)
+	Block(ctx context.Context, key string, until time.Time, locked bool) (err error)
+	// Unlock clears a lockout that is over along with its failures, unlocked is false when there was none
+	Unlock(ctx context.Context, key string, now time.Time) (unlocked bool, err error)
+	// Reset removes the failed attempts of the key, wasLocked tells whether it was under a lockout
+	Reset(ctx context.Context, key string) (wasLocked bool, err error)
+}
+
+// Event is emitted when a key is locked or unlocked
+type Event struct {
+	Type     string
+	Key      Key
+	Failures int
+	Until    time.Time
+	Reason   string
+}
+
+type Auditor interface {
+	Audit(ctx context.Context, event Event)
+}
+
+// Policy sets when the failed attempts of a scope start to be delayed and when they lock the key
+type Policy struct {
+	FreeAttempts     int
+	LockoutThreshold int
+}
+
+type Guard struct {
+	store           Store
+	auditor         Auditor
+	policies        map[string]Policy
+	baseDelay       time.Duration
+	maxDelay        time.Duration
+	lockoutDuration time.Duration
+	window          time.Duration
+}
+
+// New creates the guard with the store selected by LOGIN_GUARD_DRIVER
+func New(db dbpostgres.Queryer, auditor Auditor) (*Guard, error) {
+	cfg := config.Get().LoginGuard
+
+	var store Store
+	switch cfg.Driver {
+	case DriverMemory, "":
+		store = NewMemory()
+	case DriverPostgres:
+		store = NewPostgres(db)
+	default:
+		return nil, fmt.Errorf("loginguard: unknown driver %s", cfg.Driver)
+	}
+
+	return &Guard{
+		store:   store,
+		auditor: auditor,
+		policies: map[string]Policy{
+			ScopeCredential: {FreeAttempts: cfg.CredentialFreeAttempts, LockoutThreshold: cfg.CredentialLockoutThreshold},
+			ScopeIP:         {FreeAttempts: cfg.IPFreeAttempts, LockoutThreshold: cfg.IPLockoutThreshold},
+		},
+		baseDelay:       time.Duration(cfg.BaseDelay) * time.Second,
+		maxDelay:        time.Duration(cfg.MaxDelay) * time.Second,
+		lockoutDuration: time.Duration(cfg.LockoutDuration) * time.Second,
+		window:          time.Duration(cfg.Window) * time.Second,
+	}, nil
+}
+
+// Check returns how long the login has to wait, zero when every key is allowed.
+// A lockout that is over is cleared here, so the unlock event is emitted on the next attempt after it
+func (g *Guard) Check(ctx context.Context, keys ...Key) (retryAfter time.Duration, err error) {
+	now := time.Now()
+
+	for _, key := range keys {
+		state, err := g.store.Get(ctx, key.String())
+		if err != nil {
+			return 0, fmt.Errorf("loginguard: failed to get state of %s: %w", key.Scope, err)
+		}
+
+		if state.BlockedUntil.After(now) {
+			retryAfter = max(retryAfter, state.BlockedUntil.Sub(now))
+			continue
+		}
+
+		if !state.Locked {
+			continue
+		}
+
+		unlocked, err := g.store.Unlock(ctx, key.String(), now)
+		if err != nil {
+			return 0, fmt.Errorf("loginguard: failed to unlock %s: %w", key.Scope, err)
+		}
+
+		if unlocked {
+			g.audit(ctx, Event{Type: EventUnlocked, Key: key, Reason: "expired"})
+		}
+	}
+
+	return retryAfter, nil
+}
+
+// Fail counts a failed login for every key and blocks the keys that went over their policy
+func (g *Guard) Fail(ctx context.Context, keys ...Key) (err error) {
+	now := time.Now()
+
+	for _, key := range keys {
+		failures, err := g.store.Fail(ctx, key.String(), now, g.window)
+		if err != nil {
+			return fmt.Errorf("loginguard: failed to count failure of %s: %w", key.Scope, err)
+		}
+
+		delay, locked := g.delay(g.policies[key.Scope], failures)
+		if delay <= 0 {
+			continue
+		}
+
+		until := now.Add(delay)
+		err = g.store.Block(ctx, key.String(), until, locked)
+		if err != nil {
+			return fmt.Errorf("loginguard: failed to block %s: %w", key.Scope, err)
+		}
+
+		if locked {
+			g.audit(ctx, Event{Type: EventLocked, Key: key, Failures: failures, Until: until, Reason: "too many failed attempts"})
+		}
+	}
+
+	return nil
+}
+
+// Succeed forgets the failed attempts of the key after a successful login
+func (g *Guard) Succeed(ctx context.Context, key Key) (err error) {
+	wasLocked, err := g.store.Reset(ctx, key.String())
+	if err != nil {
+		return fmt.Errorf("loginguard: failed to reset %s: %w", key.Scope, err)
+	}
+
+	if wasLocked {
+		g.audit(ctx, Event{Type: EventUnlocked, Key: key, Reason: "login succeeded"})
+	}
+
+	return nil
+}
+
+// delay doubles from the base delay for every failure after the free attempts,
+// the key is locked for the lockout duration once the failures reach the threshold
+func (g *Guard) delay(policy Policy, failures int) (delay time.Duration, locked bool) {
+	if policy.LockoutThreshold > 0 && failures >= policy.LockoutThreshold {
+		return g.lockoutDuration, true
+	}
+
+	if failures <= policy.FreeAttempts {
+		return 0, false
+	}
+
+	shift := min(failures-policy.FreeAttempts-1, maxBackoffShift)
+	delay = g.baseDelay << shift
+	if delay > g.maxDelay {
+		delay = g.maxDelay
+	}
+
+	return delay, false
+}
+
+func (g *Guard) audit(ctx context.Context, event Event) {
+	if g.auditor == nil {
+		return
+	}
+
+	g.auditor.Audit(ctx, event)
+}
@@ -0,0 +1,198 @@
+package loginguard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type recordingAuditor struct {
+	events []Event
+}
+
+func (a *recordingAuditor) Audit(ctx context.Context, event Event) {
+	a.events = append(a.events, event)
+}
+
+func newTestGuard(store Store, auditor Auditor) *Guard {
+	return &Guard{
+		store:   store,
+		auditor: auditor,
+		policies: map[string]Policy{
+			ScopeCredential: {FreeAttempts: 3, LockoutThreshold: 10},
+			ScopeIP:         {FreeAttempts: 20},
+		},
+		baseDelay:       time.Second,
+		maxDelay:        30 * time.Second,
+		lockoutDuration: time.Hour,
+		window:          time.Hour,
+	}
+}
+
+func TestGuardDelay(t *testing.T) {
+	g := newTestGuard(NewMemory(), nil)
+	credential := g.policies[ScopeCredential]
+	ip := g.policies[ScopeIP]
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		failures   int
+		wantDelay  time.Duration
+		wantLocked bool
+	}{
+		{name: "first failure is free", policy: credential, failures: 1},
+		{name: "last free attempt", policy: credential, failures: 3},
+		{name: "first delayed failure", policy: credential, failures: 4, wantDelay: time.Second},
+		{name: "delay doubles", policy: credential, failures: 5, wantDelay: 2 * time.Second},
+		{name: "delay doubles again", policy: credential, failures: 7, wantDelay: 8 * time.Second},
+		{name: "delay is capped", policy: credential, failures: 9, wantDelay: 30 * time.Second},
+		{name: "threshold locks", policy: credential, failures: 10, wantDelay: time.Hour, wantLocked: true},
+		{name: "over the threshold stays locked", policy: credential, failures: 11, wantDelay: time.Hour, wantLocked: true},
+		{name: "no threshold never locks", policy: ip, failures: 1000, wantDelay: 30 * time.Second},
+		{name: "shift does not overflow", policy: ip, failures: 200, wantDelay: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, locked := g.delay(tt.policy, tt.failures)
+			if delay != tt.wantDelay || locked != tt.wantLocked {
+				t.Errorf("delay(%d) = (%s, %t), want (%s, %t)", tt.failures, delay, locked, tt.wantDelay, tt.wantLocked)
+			}
+		})
+	}
+}
+
+func TestGuardLockout(t *testing.T) {
+	ctx := context.Background()
+	auditor := &recordingAuditor{}
+	store := NewMemory()
+	g := newTestGuard(store, auditor)
+	key := CredentialKey("email", "John@Example.com")
+
+	for i := 1; i <= 3; i++ {
+		if err := g.Fail(ctx, key); err != nil {
+			t.Fatalf("Fail() error = %v", err)
+		}
+
+		retryAfter, err := g.Check(ctx, key)
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+
+		if retryAfter != 0 {
+			t.Fatalf("Check() after %d failures = %s, want 0", i, retryAfter)
+		}
+	}
+
+	if err := g.Fail(ctx, key); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+
+	retryAfter, err := g.Check(ctx, CredentialKey("email", "john@example.com"))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Fatalf("Check() after the free attempts = %s, want up to 1s", retryAfter)
+	}
+
+	for i := 5; i <= 10; i++ {
+		if err := g.Fail(ctx, key); err != nil {
+			t.Fatalf("Fail() error = %v", err)
+		}
+	}
+
+	state, err := store.Get(ctx, key.String())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if !state.Locked || state.Failures != 10 {
+		t.Fatalf("state = %+v, want locked after 10 failures", state)
+	}
+
+	if len(auditor.events) != 1 || auditor.events[0].Type != EventLocked || auditor.events[0].Failures != 10 {
+		t.Fatalf("events = %+v, want one %s event", auditor.events, EventLocked)
+	}
+
+	if err := g.Succeed(ctx, key); err != nil {
+		t.Fatalf("Succeed() error = %v", err)
+	}
+
+	if len(auditor.events) != 2 || auditor.events[1].Type != EventUnlocked {
+		t.Fatalf("events = %+v, want an %s event after the login succeeded", auditor.events, EventUnlocked)
+	}
+
+	retryAfter, err = g.Check(ctx, key)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if retryAfter != 0 {
+		t.Errorf("Check() after a successful login = %s, want 0", retryAfter)
+	}
+}
+
+func TestGuardExpiredLockout(t *testing.T) {
+	ctx := context.Background()
+	auditor := &recordingAuditor{}
+	store := NewMemory()
+	g := newTestGuard(store, auditor)
+	key := IPKey("10.0.0.1")
+
+	if err := store.Block(ctx, key.String(), time.Now().Add(-time.Second), true); err != nil {
+		t.Fatalf("Block() error = %v", err)
+	}
+
+	retryAfter, err := g.Check(ctx, key)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if retryAfter != 0 {
+		t.Errorf("Check() = %s, want 0 once the lockout is over", retryAfter)
+	}
+
+	if len(auditor.events) != 1 || auditor.events[0].Type != EventUnlocked || auditor.events[0].Reason != "expired" {
+		t.Fatalf("events = %+v, want one expired %s event", auditor.events, EventUnlocked)
+	}
+
+	state, err := store.Get(ctx, key.String())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if state != (State{}) {
+		t.Errorf("state = %+v, want it cleared", state)
+	}
+}
+
+func TestMemoryFailWindow(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{name: "first failure", at: now, want: 1},
+		{name: "inside the window", at: now.Add(30 * time.Minute), want: 2},
+		{name: "window restarts from the last failure", at: now.Add(80 * time.Minute), want: 3},
+		{name: "after the window", at: now.Add(3 * time.Hour), want: 1},
+	}
+
+	for _, tt := range tests {
+		failures, err := m.Fail(ctx, "key", tt.at, time.Hour)
+		if err != nil {
+			t.Fatalf("%s: Fail() error = %v", tt.name, err)
+		}
+
+		if failures != tt.want {
+			t.Errorf("%s: Fail() = %d, want %d", tt.name, failures, tt.want)
+		}
+	}
+}
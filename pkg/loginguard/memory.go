@@ -0,0 +1,114 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	State
+	lastFailureAt time.Time
+}
+
+// Memory keeps the failed attempts in this process, the entries older than the window are swept on Fail
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (state State, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.entries[key]; ok {
+		state = entry.State
+	}
+
+	return
+}
+
+func (m *Memory) Fail(ctx context.Context, key string, now time.Time, window time.Duration) (failures int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) > window {
+		m.sweep(now, window)
+	}
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+
+	if now.Sub(entry.lastFailureAt) > window {
+		entry.Failures = 0
+	}
+
+	entry.Failures++
+	entry.lastFailureAt = now
+
+	return entry.Failures, nil
+}
+
+func (m *Memory) Block(ctx context.Context, key string, until time.Time, locked bool) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+
+	entry.BlockedUntil = until
+	entry.Locked = locked
+
+	return nil
+}
+
+func (m *Memory) Unlock(ctx context.Context, key string, now time.Time) (unlocked bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || !entry.Locked || entry.BlockedUntil.After(now) {
+		return false, nil
+	}
+
+	delete(m.entries, key)
+
+	return true, nil
+}
+
+func (m *Memory) Reset(ctx context.Context, key string) (wasLocked bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.entries[key]; ok {
+		wasLocked = entry.Locked
+		delete(m.entries, key)
+	}
+
+	return
+}
+
+// sweep removes the entries that would start over on their next failure and are not blocked,
+// the caller must hold the lock
+func (m *Memory) sweep(now time.Time, window time.Duration) {
+	for key, entry := range m.entries {
+		if now.Sub(entry.lastFailureAt) > window && !entry.BlockedUntil.After(now) && !entry.Locked {
+			delete(m.entries, key)
+		}
+	}
+
+	m.lastSweep = now
+}
@@ -0,0 +1,101 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dbpostgres "github.com/arfan21/project-sprint-social-media-api/pkg/db/postgres"
+	"github.com/jackc/pgx/v5"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Postgres keeps the failed attempts in the login_attempts table so every replica sees the same state
+type Postgres struct {
+	db dbpostgres.Queryer
+}
+
+func NewPostgres(db dbpostgres.Queryer) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (p *Postgres) Get(ctx context.Context, key string) (state State, err error) {
+	query := `
+		SELECT failures, blockedUntil, locked
+		FROM login_attempts
+		WHERE key = $1
+	`
+
+	var blockedUntil null.Time
+	err = p.db.QueryRow(ctx, query, key).Scan(&state.Failures, &blockedUntil, &state.Locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return State{}, nil
+		}
+
+		return
+	}
+
+	state.BlockedUntil = blockedUntil.ValueOrZero()
+
+	return
+}
+
+func (p *Postgres) Fail(ctx context.Context, key string, now time.Time, window time.Duration) (failures int, err error) {
+	query := `
+		INSERT INTO login_attempts (key, failures, lastFailureAt)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+				WHEN login_attempts.lastFailureAt < $3 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			lastFailureAt = EXCLUDED.lastFailureAt
+		RETURNING failures
+	`
+
+	err = p.db.QueryRow(ctx, query, key, now, now.Add(-window)).Scan(&failures)
+
+	return
+}
+
+func (p *Postgres) Block(ctx context.Context, key string, until time.Time, locked bool) (err error) {
+	query := `
+		UPDATE login_attempts
+		SET blockedUntil = $2, locked = $3
+		WHERE key = $1
+	`
+
+	_, err = p.db.Exec(ctx, query, key, until, locked)
+
+	return
+}
+
+func (p *Postgres) Unlock(ctx context.Context, key string, now time.Time) (unlocked bool, err error) {
+	query := `
+		DELETE FROM login_attempts
+		WHERE key = $1 AND locked AND blockedUntil <= $2
+	`
+
+	cmd, err := p.db.Exec(ctx, query, key, now)
+	if err != nil {
+		return
+	}
+
+	return cmd.RowsAffected() > 0, nil
+}
+
+func (p *Postgres) Reset(ctx context.Context, key string) (wasLocked bool, err error) {
+	query := `
+		DELETE FROM login_attempts
+		WHERE key = $1
+		RETURNING locked
+	`
+
+	err = p.db.QueryRow(ctx, query, key).Scan(&wasLocked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+
+	return
+}
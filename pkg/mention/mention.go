@@ -0,0 +1,120 @@
+package mention
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// MaxHandles is the maximum number of handles taken from one text,
+// so a single post cannot notify an unbounded number of users
+const MaxHandles = 20
+
+// a handle starts with @ that is not part of a word or an email address
+var handlePattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@./])@([\p{L}\p{N}_.]+)`)
+
+// Normalize returns the form used to match a handle with a user name,
+// lower cased with only letters, digits and underscores, so @JohnDoe matches the name "John Doe"
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// Parse returns the normalized handles written in the text of the html,
+// handles inside links are ignored
+func Parse(body string) (handles []string) {
+	seen := map[string]struct{}{}
+	walkText(body, func(text string) string {
+		for _, match := range handlePattern.FindAllStringSubmatch(text, -1) {
+			handle := Normalize(match[1])
+			if handle == "" || len(handles) >= MaxHandles {
+				continue
+			}
+
+			if _, ok := seen[handle]; ok {
+				continue
+			}
+
+			seen[handle] = struct{}{}
+			handles = append(handles, handle)
+		}
+
+		return ""
+	})
+
+	return
+}
+
+// Link wraps every handle found in hrefs, keyed by the normalized handle, with a link to the href.
+// The rest of the html is kept as is
+func Link(body string, hrefs map[string]string) string {
+	if len(hrefs) == 0 {
+		return body
+	}
+
+	return walkText(body, func(text string) string {
+		var b strings.Builder
+		last := 0
+		for _, loc := range handlePattern.FindAllStringSubmatchIndex(text, -1) {
+			// loc[2]:loc[3] is the handle without the @
+			start, end := loc[2]-1, loc[3]
+			raw := strings.TrimRight(text[loc[2]:end], ".")
+			end = loc[2] + len(raw)
+
+			href, ok := hrefs[Normalize(raw)]
+			if !ok {
+				continue
+			}
+
+			b.WriteString(html.EscapeString(text[last:start]))
+			b.WriteString(`<a href="` + html.EscapeString(href) + `">@` + html.EscapeString(raw) + `</a>`)
+			last = end
+		}
+
+		b.WriteString(html.EscapeString(text[last:]))
+		return b.String()
+	})
+}
+
+// walkText calls fn with the unescaped text outside links and replaces the text with the html returned by fn
+func walkText(body string, fn func(text string) string) string {
+	z := nethtml.NewTokenizer(strings.NewReader(body))
+
+	var b strings.Builder
+	anchorDepth := 0
+	for {
+		tt := z.Next()
+		switch tt {
+		case nethtml.ErrorToken:
+			return b.String()
+		case nethtml.TextToken:
+			if anchorDepth == 0 {
+				b.WriteString(fn(string(z.Text())))
+				continue
+			}
+		case nethtml.StartTagToken:
+			raw := string(z.Raw())
+			if name, _ := z.TagName(); string(name) == "a" {
+				anchorDepth++
+			}
+			b.WriteString(raw)
+			continue
+		case nethtml.EndTagToken:
+			raw := string(z.Raw())
+			if name, _ := z.TagName(); string(name) == "a" && anchorDepth > 0 {
+				anchorDepth--
+			}
+			b.WriteString(raw)
+			continue
+		}
+
+		b.Write(z.Raw())
+	}
+}
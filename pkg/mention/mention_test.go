@@ -0,0 +1,125 @@
+package mention
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "John Doe", want: "johndoe"},
+		{input: "JohnDoe", want: "johndoe"},
+		{input: "john_doe.99", want: "john_doe99"},
+		{input: "Ünïcode", want: "ünïcode"},
+		{input: "!!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Normalize(tt.input); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	many := make([]string, 0, MaxHandles+5)
+	for i := 0; i < MaxHandles+5; i++ {
+		many = append(many, "@user"+strconv.Itoa(i))
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "plain text", input: "hi @JohnDoe and @jane", want: []string{"johndoe", "jane"}},
+		{name: "start of text", input: "@john", want: []string{"john"}},
+		{name: "inside markup", input: "<p>hello <b>@john</b></p>", want: []string{"john"}},
+		{name: "duplicates are removed", input: "@john @John @JOHN", want: []string{"john"}},
+		{name: "email is not a handle", input: "mail me at john@example.com", want: nil},
+		{name: "handle inside a link is ignored", input: `<a href="/u/1">@john</a> @jane`, want: []string{"jane"}},
+		{name: "trailing dot is not part of the handle", input: "thanks @john.", want: []string{"john"}},
+		{name: "lone at sign", input: "meet @ noon", want: nil},
+		{name: "escaped text", input: "&lt;@john&gt;", want: []string{"john"}},
+		{name: "handles are capped", input: strings.Join(many, " "), want: func() []string {
+			want := make([]string, 0, MaxHandles)
+			for i := 0; i < MaxHandles; i++ {
+				want = append(want, "user"+strconv.Itoa(i))
+			}
+			return want
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	hrefs := map[string]string{
+		"johndoe": "/v1/user/1",
+		"jane":    "/v1/user/2?a=1&b=2",
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		hrefs map[string]string
+		want  string
+	}{
+		{
+			name:  "known handles are linked",
+			input: "<p>hi @JohnDoe and @jane</p>",
+			hrefs: hrefs,
+			want:  `<p>hi <a href="/v1/user/1">@JohnDoe</a> and <a href="/v1/user/2?a=1&amp;b=2">@jane</a></p>`,
+		},
+		{
+			name:  "unknown handle is kept",
+			input: "hi @nobody",
+			hrefs: hrefs,
+			want:  "hi @nobody",
+		},
+		{
+			name:  "trailing dot stays outside the link",
+			input: "thanks @jane.",
+			hrefs: hrefs,
+			want:  `thanks <a href="/v1/user/2?a=1&amp;b=2">@jane</a>.`,
+		},
+		{
+			name:  "existing link is not linked again",
+			input: `<a href="/x">@jane</a>`,
+			hrefs: hrefs,
+			want:  `<a href="/x">@jane</a>`,
+		},
+		{
+			name:  "text stays escaped",
+			input: "1 &lt; 2 @jane",
+			hrefs: hrefs,
+			want:  `1 &lt; 2 <a href="/v1/user/2?a=1&amp;b=2">@jane</a>`,
+		},
+		{
+			name:  "no hrefs returns the body",
+			input: "hi @jane <b>x",
+			want:  "hi @jane <b>x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Link(tt.input, tt.hrefs); got != tt.want {
+				t.Errorf("Link(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,63 +1,77 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	"github.com/arfan21/project-sprint-social-media-api/config"
 	"github.com/arfan21/project-sprint-social-media-api/internal/model"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/jwtkey"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
 	"github.com/arfan21/project-sprint-social-media-api/pkg/pkgutil"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func JWTAuth(c *fiber.Ctx) error {
-	// fetch token
-	head := c.Get("Authorization", "")
-	if head == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
-			Code:    fiber.StatusUnauthorized,
-			Message: "missing or malformed jwt",
-		})
-	}
+// SessionChecker is used by JWTAuth to reject access tokens whose session (jti) has been revoked
+type SessionChecker interface {
+	IsSessionRevoked(ctx context.Context, sessionID string) (isRevoked bool, err error)
+}
 
-	token := strings.Split(head, "Bearer ")
-	if len(token) != 2 {
-		return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
-			Code:    fiber.StatusUnauthorized,
-			Message: "missing or malformed jwt",
-		})
-	}
+func JWTAuth(keys *jwtkey.KeySet, sessionChecker SessionChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// fetch token
+		head := c.Get("Authorization", "")
+		if head == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
+				Code:    fiber.StatusUnauthorized,
+				Message: "missing or malformed jwt",
+			})
+		}
 
-	// validate token
-	t, err := jwt.ParseWithClaims(token[1], &model.JWTClaims{}, func(t *jwt.Token) (interface{}, error) {
-		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
-			return nil, fmt.Errorf("middleware: invalid token signing algorithm")
+		token := strings.Split(head, "Bearer ")
+		if len(token) != 2 {
+			return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
+				Code:    fiber.StatusUnauthorized,
+				Message: "missing or malformed jwt",
+			})
 		}
 
-		return []byte(config.Get().JWT.Secret), nil
-	})
-	if err != nil {
-		logger.Log(c.UserContext()).Error().Msgf("middleware: failed to parse jwt token: %v", err)
-		return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
-			Code:    fiber.StatusUnauthorized,
-			Message: "invalid or expired token",
-		})
-	}
+		// validate token
+		t, err := keys.Parse(token[1], &model.JWTClaims{})
+		if err != nil {
+			logger.Log(c.UserContext()).Error().Msgf("middleware: failed to parse jwt token: %v", err)
+			return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
+				Code:    fiber.StatusUnauthorized,
+				Message: "invalid or expired token",
+			})
+		}
 
-	claims, ok := t.Claims.(*model.JWTClaims)
-	if ok && t.Valid && claims != nil {
+		claims, ok := t.Claims.(*model.JWTClaims)
+		// a token with a purpose, such as the login challenge, is never an access token
+		if !ok || !t.Valid || claims == nil || claims.ID == "" || claims.Purpose != "" {
+			logger.Log(c.UserContext()).Error().Msg("middleware: invalid or expired token")
+			return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
+				Code:    fiber.StatusUnauthorized,
+				Message: "invalid or expired token",
+			})
+		}
+
+		isRevoked, err := sessionChecker.IsSessionRevoked(c.UserContext(), claims.ID)
+		if err != nil {
+			return fmt.Errorf("middleware: failed to check session: %w", err)
+		}
+
+		if isRevoked {
+			logger.Log(c.UserContext()).Error().Msgf("middleware: session %s revoked", claims.ID)
+			return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
+				Code:    fiber.StatusUnauthorized,
+				Message: "invalid or expired token",
+			})
+		}
 
 		claims.UserID = claims.Subject
 		c.Locals(constant.JWTClaimsContextKey, *claims)
 		return c.Next()
 	}
-
-	logger.Log(c.UserContext()).Error().Msg("middleware: invalid or expired token")
-	return c.Status(fiber.StatusUnauthorized).JSON(pkgutil.HTTPResponse{
-		Code:    fiber.StatusUnauthorized,
-		Message: "invalid or expired token",
-	})
 }
@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File appends the messages as json lines to a file instead of delivering them
+type File struct {
+	mu   sync.Mutex
+	path string
+}
+
+type fileEntry struct {
+	Message
+	SentAt time.Time `json:"sentAt"`
+}
+
+func NewFile(path string) (*File, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("notifier: failed to create file notifier dir: %w", err)
+	}
+
+	return &File{path: path}, nil
+}
+
+func (f *File) Send(ctx context.Context, msg Message) (err error) {
+	line, err := json.Marshal(fileEntry{Message: msg, SentAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("notifier: failed to marshal message: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("notifier: failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("notifier: failed to write message: %w", err)
+	}
+
+	return
+}
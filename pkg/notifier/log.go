@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+)
+
+// Log writes the messages to the application log instead of delivering them
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (l *Log) Send(ctx context.Context, msg Message) (err error) {
+	logger.Log(ctx).Info().
+		Str("channel", msg.Channel).
+		Str("to", msg.To).
+		Str("subject", msg.Subject).
+		Str("body", msg.Body).
+		Msg("notifier: message sent")
+
+	return
+}
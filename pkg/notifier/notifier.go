@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+)
+
+const (
+	DriverLog    = "log"
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+// channels a message can be delivered through
+const (
+	ChannelEmail = "email"
+	ChannelPhone = "phone"
+)
+
+// Message is delivered to the email address or the phone number in To depending on the channel,
+// Code is the one time code written in the body when the message carries one
+type Message struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Code    string `json:"code,omitempty"`
+}
+
+type Notifier interface {
+	Send(ctx context.Context, msg Message) (err error)
+}
+
+// New creates the notifier driver selected by NOTIFIER_DRIVER,
+// the log, file and memory drivers do not deliver anything and are meant for local development and tests
+func New() (Notifier, error) {
+	cfg := config.Get()
+
+	switch cfg.Notifier.Driver {
+	case DriverLog, "":
+		return NewLog(), nil
+	case DriverFile:
+		return NewFile(cfg.Notifier.FilePath)
+	case DriverMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("notifier: unknown driver %s", cfg.Notifier.Driver)
+	}
+}
@@ -0,0 +1,59 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+)
+
+const defaultLength = 6
+
+// Generate returns a random numeric code with the length of OTP_LENGTH
+func Generate() (code string, err error) {
+	length := config.Get().OTP.Length
+	if length <= 0 {
+		length = defaultLength
+	}
+
+	digits := make([]byte, length)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("otp: failed to read random number: %w", err)
+		}
+
+		digits[i] = byte('0' + n.Int64())
+	}
+
+	return string(digits), nil
+}
+
+// Hash returns the hash of the code, only the hash is stored.
+// The code is short so it is keyed with the otp secret to keep a leaked hash from being brute forced offline
+func Hash(code string) string {
+	mac := hmac.New(sha256.New, []byte(secret()))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Equal compares the code with the stored hash in constant time
+func Equal(hash, code string) bool {
+	return hmac.Equal([]byte(hash), []byte(Hash(code)))
+}
+
+// secret falls back to a key derived from the jwt secret so a deployment does not need another secret to use otps,
+// the jwt secret itself is never reused as the otp key
+func secret() string {
+	if s := config.Get().OTP.Secret; s != "" {
+		return s
+	}
+
+	mac := hmac.New(sha256.New, []byte(config.Get().JWT.Secret))
+	mac.Write([]byte("otp"))
+	return string(mac.Sum(nil))
+}
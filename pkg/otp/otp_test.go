@@ -0,0 +1,66 @@
+package otp
+
+import (
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+)
+
+func TestGenerate(t *testing.T) {
+	want := config.Get().OTP.Length
+	if want <= 0 {
+		want = defaultLength
+	}
+
+	for i := 0; i < 100; i++ {
+		code, err := Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		if len(code) != want {
+			t.Fatalf("Generate() = %s, want %d digits", code, want)
+		}
+
+		for _, r := range code {
+			if r < '0' || r > '9' {
+				t.Fatalf("Generate() = %s, want only digits", code)
+			}
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	hash := Hash("123456")
+
+	tests := []struct {
+		name string
+		hash string
+		code string
+		want bool
+	}{
+		{name: "same code", hash: hash, code: "123456", want: true},
+		{name: "different code", hash: hash, code: "123457"},
+		{name: "empty code", hash: hash, code: ""},
+		{name: "plain code is not a hash", hash: "123456", code: "123456"},
+		{name: "empty hash", hash: "", code: "123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.hash, tt.code); got != tt.want {
+				t.Errorf("Equal() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	if Hash("123456") != Hash("123456") {
+		t.Error("Hash() is not deterministic")
+	}
+
+	if Hash("123456") == Hash("654321") {
+		t.Error("Hash() returned the same hash for different codes")
+	}
+}
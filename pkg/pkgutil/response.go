@@ -24,4 +24,7 @@ type MetaResponse struct {
 	Total  int `json:"total" example:"1"`
 	Offset int `json:"offset" example:"0"`
 	Limit  int `json:"limit" example:"10"`
+
+	NextCursor string `json:"nextCursor,omitempty" example:"eyJzIjoicG9zdCIsImlkIjoiMDE4ZTlhYjQtMmM1Ni03ZDQ1LWE3OGQtNWI0YjY5N2E4YzA3In0.7vX0o9cYt1b3n5m2kQ8wZrV4lP6sJd1eHfGaBcDiEkU"`
+	PrevCursor string `json:"prevCursor,omitempty" example:"eyJzIjoicG9zdCIsImlkIjoiMDE4ZTlhYjQtMmM1Ni03ZDQ1LWE3OGQtNWI0YjY5N2E4YzA3IiwicCI6dHJ1ZX0.Jm3aT8uQx2Lr0cWn5YbK9pEvH1sDg4ZfO7iNq6MtUoA"`
 }
@@ -0,0 +1,98 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+)
+
+// subscriberBuffer is the number of messages kept for a slow subscriber before messages are dropped
+const subscriberBuffer = 64
+
+type subscriber struct {
+	ch chan Message
+}
+
+// Memory fans out messages to the subscribers of this process
+type Memory struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*subscriber]struct{}
+	closed      bool
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		subscribers: make(map[string]map[*subscriber]struct{}),
+	}
+}
+
+func (m *Memory) Publish(ctx context.Context, msg Message) (err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, userID := range msg.UserIDs {
+		for sub := range m.subscribers[userID] {
+			select {
+			case sub.ch <- msg:
+			default:
+				logger.Log(ctx).Warn().Str("user_id", userID).Str("type", msg.Type).Msg("pubsub: subscriber is full, message dropped")
+			}
+		}
+	}
+
+	return
+}
+
+func (m *Memory) Subscribe(userID string) (<-chan Message, func()) {
+	sub := &subscriber{ch: make(chan Message, subscriberBuffer)}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+
+	if _, ok := m.subscribers[userID]; !ok {
+		m.subscribers[userID] = make(map[*subscriber]struct{})
+	}
+	m.subscribers[userID][sub] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			if _, ok := m.subscribers[userID][sub]; !ok {
+				return
+			}
+
+			delete(m.subscribers[userID], sub)
+			if len(m.subscribers[userID]) == 0 {
+				delete(m.subscribers, userID)
+			}
+			close(sub.ch)
+		})
+	}
+
+	return sub.ch, unsubscribe
+}
+
+// Close closes every subscription so the subscribers can stop
+func (m *Memory) Close() (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for userID, subs := range m.subscribers {
+		for sub := range subs {
+			close(sub.ch)
+		}
+		delete(m.subscribers, userID)
+	}
+	m.closed = true
+
+	return
+}
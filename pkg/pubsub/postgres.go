@@ -0,0 +1,136 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/arfan21/project-sprint-social-media-api/pkg/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// notifyPayloadLimit is below the 8000 bytes limit of a postgres NOTIFY payload
+	notifyPayloadLimit = 7900
+	// notifyUserIDsChunk is the number of recipients sent in one NOTIFY
+	notifyUserIDsChunk = 100
+
+	listenRetryInterval = 3 * time.Second
+)
+
+// Postgres publishes messages with NOTIFY and delivers them to the local subscribers
+// from a LISTEN connection, so every replica receives the messages of the others
+type Postgres struct {
+	*Memory
+	db      *pgxpool.Pool
+	channel string
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewPostgres(db *pgxpool.Pool, channel string) (*Postgres, error) {
+	if db == nil {
+		return nil, fmt.Errorf("pubsub: database pool is required for driver %s", DriverPostgres)
+	}
+
+	if channel == "" {
+		return nil, fmt.Errorf("pubsub: PUBSUB_CHANNEL is required for driver %s", DriverPostgres)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Postgres{
+		Memory:  NewMemory(),
+		db:      db,
+		channel: channel,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go p.listen(ctx)
+
+	return p, nil
+}
+
+func (p *Postgres) Publish(ctx context.Context, msg Message) (err error) {
+	for start := 0; start < len(msg.UserIDs); start += notifyUserIDsChunk {
+		end := min(start+notifyUserIDsChunk, len(msg.UserIDs))
+
+		chunk := msg
+		chunk.UserIDs = msg.UserIDs[start:end]
+
+		payload, err := json.Marshal(chunk)
+		if err != nil {
+			return fmt.Errorf("pubsub: failed to marshal message: %w", err)
+		}
+
+		if len(payload) > notifyPayloadLimit {
+			return fmt.Errorf("pubsub: message %s is too large for notify: %d bytes", msg.Type, len(payload))
+		}
+
+		_, err = p.db.Exec(ctx, "SELECT pg_notify($1, $2)", p.channel, string(payload))
+		if err != nil {
+			return fmt.Errorf("pubsub: failed to notify: %w", err)
+		}
+	}
+
+	return
+}
+
+// listen keeps a dedicated connection listening on the channel and reconnects when it is lost
+func (p *Postgres) listen(ctx context.Context) {
+	defer close(p.done)
+
+	for {
+		err := p.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.Log(ctx).Error().Err(err).Msg("pubsub: listen connection lost, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+func (p *Postgres) listenOnce(ctx context.Context) (err error) {
+	conn, err := p.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("pubsub: failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, fmt.Sprintf("LISTEN %q", p.channel))
+	if err != nil {
+		return fmt.Errorf("pubsub: failed to listen: %w", err)
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			// the connection is still listening, it must not go back to the pool
+			_ = conn.Hijack().Close(context.Background())
+			return fmt.Errorf("pubsub: failed to wait for notification: %w", err)
+		}
+
+		var msg Message
+		err = json.Unmarshal([]byte(notification.Payload), &msg)
+		if err != nil {
+			logger.Log(ctx).Error().Err(err).Msg("pubsub: failed to unmarshal notification")
+			continue
+		}
+
+		_ = p.Memory.Publish(ctx, msg)
+	}
+}
+
+func (p *Postgres) Close() (err error) {
+	p.cancel()
+	<-p.done
+
+	return p.Memory.Close()
+}
@@ -0,0 +1,60 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+)
+
+// Message is an event addressed to a set of users, Data is sent to the clients as is
+type Message struct {
+	Type    string          `json:"type"`
+	UserIDs []string        `json:"userIds"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) (err error)
+}
+
+type PubSub interface {
+	Publisher
+	// Subscribe registers a subscriber for the messages of the user,
+	// the returned function must be called to release the subscription
+	Subscribe(userID string) (ch <-chan Message, unsubscribe func())
+	Close() (err error)
+}
+
+// New creates the pubsub driver selected by PUBSUB_DRIVER,
+// the postgres driver shares the messages between replicas with LISTEN/NOTIFY
+func New(db *pgxpool.Pool) (PubSub, error) {
+	cfg := config.Get()
+
+	switch cfg.PubSub.Driver {
+	case DriverMemory, "":
+		return NewMemory(), nil
+	case DriverPostgres:
+		return NewPostgres(db, cfg.PubSub.Channel)
+	default:
+		return nil, fmt.Errorf("pubsub: unknown driver %s", cfg.PubSub.Driver)
+	}
+}
+
+// NewMessage builds a message with data encoded as json
+func NewMessage(msgType string, userIDs []string, data any) (msg Message, err error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		err = fmt.Errorf("pubsub: failed to marshal message data: %w", err)
+		return
+	}
+
+	return Message{Type: msgType, UserIDs: userIDs, Data: b}, nil
+}
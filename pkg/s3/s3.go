@@ -3,7 +3,6 @@ package s3
 import (
 	"context"
 	"fmt"
-	"log"
 	"mime/multipart"
 	"strings"
 	"time"
@@ -15,15 +14,42 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
-	"github.com/jaevor/go-nanoid"
 )
 
 type S3 struct {
 	client    *awss3.Client
 	presigner *awss3.PresignClient
+	endpoint  string
 }
 
-func New() (*S3, error) {
+type OptFunc func(*S3)
+
+// WithEndpoint is a function to use an s3 compatible endpoint (minio, etc) instead of aws,
+// the endpoint is accessed with path style addressing
+func WithEndpoint(endpoint string) OptFunc {
+	return func(s *S3) {
+		if endpoint == "" {
+			return
+		}
+
+		if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+			scheme := "http://"
+			if config.Get().S3.UseSSL {
+				scheme = "https://"
+			}
+			endpoint = scheme + endpoint
+		}
+
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+func New(opt ...OptFunc) (*S3, error) {
+	s := &S3{}
+	for _, o := range opt {
+		o(s)
+	}
+
 	cfg, err := awscfg.LoadDefaultConfig(context.Background(),
 		awscfg.WithCredentialsProvider(
 			credentials.NewStaticCredentialsProvider(
@@ -35,22 +61,25 @@ func New() (*S3, error) {
 		awscfg.WithRegion(config.Get().S3.Region),
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("s3: failed to load aws config: %w", err)
 	}
 
-	client := awss3.NewFromConfig(cfg)
-	presigner := s3.NewPresignClient(client)
-	return &S3{
-		client:    client,
-		presigner: presigner,
-	}, nil
+	client := awss3.NewFromConfig(cfg, func(o *awss3.Options) {
+		if s.endpoint != "" {
+			o.BaseEndpoint = aws.String(s.endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	s.client = client
+	s.presigner = s3.NewPresignClient(client)
+	return s, nil
 }
 
 func (s *S3) GetClient() *awss3.Client {
 	return s.client
 }
 
-func (s *S3) Upload(ctx context.Context, bucketName string, folder string, fileHeader *multipart.FileHeader) (res string, err error) {
+func (s *S3) Upload(ctx context.Context, bucketName string, key string, fileHeader *multipart.FileHeader) (err error) {
 	ctx, parentSpan := tracer.Start(ctx, "pkg.s3.Upload")
 	defer func() {
 		if err != nil {
@@ -59,23 +88,9 @@ func (s *S3) Upload(ctx context.Context, bucketName string, folder string, fileH
 		parentSpan.End()
 	}()
 
-	// remove whitespace
-	filename := strings.ReplaceAll(fileHeader.Filename, " ", "_")
-
-	randId, err := nanoid.Standard(15)
-	if err != nil {
-		return "", err
-	}
-
-	filename = randId() + "_" + filename
-	filename = folder + "/" + filename
-	if config.Get().Service.Name != "" {
-		filename = config.Get().Service.Name + "/" + filename
-	}
-
 	file, err := fileHeader.Open()
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	defer file.Close()
@@ -83,15 +98,35 @@ func (s *S3) Upload(ctx context.Context, bucketName string, folder string, fileH
 	_, err = s.client.PutObject(ctx, &awss3.PutObjectInput{
 		Bucket:        aws.String(bucketName),
 		Body:          file,
-		Key:           aws.String(filename),
+		Key:           aws.String(key),
 		ContentLength: aws.Int64(fileHeader.Size),
 		ContentType:   aws.String(fileHeader.Header.Get("Content-Type")),
 		ACL:           types.ObjectCannedACLPublicRead,
 	})
-	return filename, err
+	return err
+}
+
+func (s *S3) Delete(ctx context.Context, bucketName, objectName string) (err error) {
+	ctx, parentSpan := tracer.Start(ctx, "pkg.s3.Delete")
+	defer func() {
+		if err != nil {
+			parentSpan.RecordError(err)
+		}
+		parentSpan.End()
+	}()
+
+	_, err = s.client.DeleteObject(ctx, &awss3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectName),
+	})
+	return err
 }
 
 func (s *S3) GetURL(bucketName, objectName string) string {
+	if s.endpoint != "" {
+		return s.endpoint + "/" + bucketName + "/" + objectName
+	}
+
 	baseURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucketName, config.Get().S3.Region)
 
 	return baseURL + "/" + objectName
@@ -0,0 +1,264 @@
+package sanitizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+	"golang.org/x/net/html"
+)
+
+const (
+	ModeStrip  = "strip"
+	ModeReject = "reject"
+
+	// anyTag is used in SANITIZER_ALLOWED_ATTRIBUTES to allow an attribute on every allowed tag, ex: *:class
+	anyTag = "*"
+)
+
+// content of these tags is dropped together with the tag instead of being kept as text
+var dropContentTags = map[string]struct{}{
+	"script":   {},
+	"style":    {},
+	"iframe":   {},
+	"object":   {},
+	"embed":    {},
+	"noscript": {},
+	"template": {},
+	"textarea": {},
+	"title":    {},
+}
+
+// attributes holding a url, only safe schemes are allowed
+var urlAttributes = map[string]struct{}{
+	"href": {},
+	"src":  {},
+	"cite": {},
+}
+
+var allowedSchemes = map[string]struct{}{
+	"http":   {},
+	"https":  {},
+	"mailto": {},
+}
+
+type Violation struct {
+	Tag       string
+	Attribute string
+}
+
+func (v Violation) String() string {
+	if v.Attribute != "" {
+		return fmt.Sprintf("attribute %s is not allowed on tag %s", v.Attribute, v.Tag)
+	}
+
+	return fmt.Sprintf("tag %s is not allowed", v.Tag)
+}
+
+type Policy struct {
+	mode       string
+	tags       map[string]struct{}
+	attributes map[string]map[string]struct{}
+}
+
+// NewPolicy creates a policy from an allowlist of tags and attributes,
+// attributes are written as tag:attribute, ex: a:href or *:class
+func NewPolicy(mode string, allowedTags, allowedAttributes []string) *Policy {
+	p := &Policy{
+		mode:       mode,
+		tags:       make(map[string]struct{}),
+		attributes: make(map[string]map[string]struct{}),
+	}
+
+	for _, tag := range allowedTags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			p.tags[tag] = struct{}{}
+		}
+	}
+
+	for _, attr := range allowedAttributes {
+		tag, name, ok := strings.Cut(strings.ToLower(strings.TrimSpace(attr)), ":")
+		if !ok || tag == "" || name == "" {
+			continue
+		}
+
+		if _, ok := p.attributes[tag]; !ok {
+			p.attributes[tag] = make(map[string]struct{})
+		}
+		p.attributes[tag][name] = struct{}{}
+	}
+
+	return p
+}
+
+var (
+	defaultPolicy *Policy
+	policyOnce    sync.Once
+)
+
+// Default returns the policy configured by SANITIZER_MODE, SANITIZER_ALLOWED_TAGS and SANITIZER_ALLOWED_ATTRIBUTES
+func Default() *Policy {
+	policyOnce.Do(func() {
+		cfg := config.Get().Sanitizer
+		defaultPolicy = NewPolicy(cfg.Mode, strings.Split(cfg.AllowedTags, ","), strings.Split(cfg.AllowedAttributes, ","))
+	})
+
+	return defaultPolicy
+}
+
+// Sanitize is a shortcut of Default().Sanitize
+func Sanitize(field, input string) (output string, err error) {
+	return Default().Sanitize(field, input)
+}
+
+// Sanitize cleans the input with the policy, in reject mode any disallowed markup
+// returns *constant.ErrValidation with one message per violation for the given field
+func (p *Policy) Sanitize(field, input string) (output string, err error) {
+	output, violations := p.Clean(input)
+
+	if len(violations) > 0 && p.mode == ModeReject {
+		return "", newErrValidation(field, violations)
+	}
+
+	if strings.TrimSpace(output) == "" && strings.TrimSpace(input) != "" {
+		return "", newErrValidation(field, []Violation{})
+	}
+
+	return output, nil
+}
+
+// Clean removes every tag and attribute outside of the allowlist and reports what has been removed
+func (p *Policy) Clean(input string) (output string, violations []Violation) {
+	var sb strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	seen := make(map[Violation]struct{})
+	addViolation := func(v Violation) {
+		if _, ok := seen[v]; ok {
+			return
+		}
+		seen[v] = struct{}{}
+		violations = append(violations, v)
+	}
+
+	// depth of the dropped elements we are currently in
+	dropDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// io.EOF or a malformed input, what has been written so far is kept
+			break
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.TextToken:
+			if dropDepth == 0 {
+				sb.WriteString(html.EscapeString(token.Data))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			_, isDropContent := dropContentTags[token.Data]
+			if isDropContent && tokenType == html.StartTagToken {
+				dropDepth++
+			}
+
+			if _, ok := p.tags[token.Data]; !ok || isDropContent {
+				addViolation(Violation{Tag: token.Data})
+				continue
+			}
+
+			if dropDepth > 0 {
+				continue
+			}
+
+			sb.WriteString("<" + token.Data)
+			for _, attr := range token.Attr {
+				if !p.isAttributeAllowed(token.Data, attr) {
+					addViolation(Violation{Tag: token.Data, Attribute: attr.Key})
+					continue
+				}
+				sb.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+			if tokenType == html.SelfClosingTagToken {
+				sb.WriteString("/")
+			}
+			sb.WriteString(">")
+		case html.EndTagToken:
+			if _, ok := dropContentTags[token.Data]; ok {
+				if dropDepth > 0 {
+					dropDepth--
+				}
+				continue
+			}
+
+			if _, ok := p.tags[token.Data]; ok && dropDepth == 0 {
+				sb.WriteString("</" + token.Data + ">")
+			}
+		case html.CommentToken, html.DoctypeToken:
+			// always dropped
+		}
+	}
+
+	return sb.String(), violations
+}
+
+func (p *Policy) isAttributeAllowed(tag string, attr html.Attribute) bool {
+	if attr.Namespace != "" {
+		return false
+	}
+
+	_, allowedOnTag := p.attributes[tag][attr.Key]
+	_, allowedOnAny := p.attributes[anyTag][attr.Key]
+	if !allowedOnTag && !allowedOnAny {
+		return false
+	}
+
+	if _, ok := urlAttributes[attr.Key]; ok {
+		return isSafeURL(attr.Val)
+	}
+
+	return true
+}
+
+func isSafeURL(val string) bool {
+	u, err := url.Parse(strings.TrimSpace(val))
+	if err != nil {
+		return false
+	}
+
+	if u.Scheme == "" {
+		return true
+	}
+
+	_, ok := allowedSchemes[strings.ToLower(u.Scheme)]
+	return ok
+}
+
+func newErrValidation(field string, violations []Violation) error {
+	errMap := []map[string]interface{}{}
+	for _, v := range violations {
+		errMap = append(errMap, map[string]interface{}{
+			"field":   field,
+			"message": v.String(),
+		})
+	}
+
+	if len(errMap) == 0 {
+		errMap = append(errMap, map[string]interface{}{
+			"field":   field,
+			"message": field + " is empty after removing disallowed markup",
+		})
+	}
+
+	jsonErr, err := json.Marshal(errMap)
+	if err != nil {
+		return err
+	}
+
+	return &constant.ErrValidation{Message: string(jsonErr)}
+}
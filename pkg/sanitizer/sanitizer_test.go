@@ -0,0 +1,145 @@
+package sanitizer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/arfan21/project-sprint-social-media-api/pkg/constant"
+)
+
+func newTestPolicy(mode string) *Policy {
+	return NewPolicy(mode, []string{"p", "b", "a", "br", " UL "}, []string{"a:href", "*:title", "invalid", ":x"})
+}
+
+func TestPolicyClean(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		want           string
+		wantViolations []Violation
+	}{
+		{
+			name:  "allowed markup is kept",
+			input: "<p>hello <b>world</b></p>",
+			want:  "<p>hello <b>world</b></p>",
+		},
+		{
+			name:  "text is escaped",
+			input: "1 < 2 & 3",
+			want:  "1 &lt; 2 &amp; 3",
+		},
+		{
+			name:           "script is dropped with its content",
+			input:          "<p>hi</p><script>alert(1)</script>",
+			want:           "<p>hi</p>",
+			wantViolations: []Violation{{Tag: "script"}},
+		},
+		{
+			name:           "disallowed tag keeps its text",
+			input:          "<div>text</div>",
+			want:           "text",
+			wantViolations: []Violation{{Tag: "div"}},
+		},
+		{
+			name:           "raw text of dropped tag is not parsed",
+			input:          "<style><script>x</script>y</style>z",
+			want:           "z",
+			wantViolations: []Violation{{Tag: "style"}},
+		},
+		{
+			name:           "event handler attribute is removed",
+			input:          `<a href="https://example.com" onclick="steal()">link</a>`,
+			want:           `<a href="https://example.com">link</a>`,
+			wantViolations: []Violation{{Tag: "a", Attribute: "onclick"}},
+		},
+		{
+			name:           "javascript url is removed",
+			input:          `<a href=" JavaScript:alert(1)">link</a>`,
+			want:           `<a>link</a>`,
+			wantViolations: []Violation{{Tag: "a", Attribute: "href"}},
+		},
+		{
+			name:  "relative url is kept",
+			input: `<a href="/post/1">link</a>`,
+			want:  `<a href="/post/1">link</a>`,
+		},
+		{
+			name:  "attribute allowed on any tag",
+			input: `<p title="t">x</p>`,
+			want:  `<p title="t">x</p>`,
+		},
+		{
+			name:  "attribute value is escaped",
+			input: `<p title='"><script>'>x</p>`,
+			want:  `<p title="&#34;&gt;&lt;script&gt;">x</p>`,
+		},
+		{
+			name:  "self closing tag",
+			input: "a<br/>b",
+			want:  "a<br/>b",
+		},
+		{
+			name:  "comments are dropped",
+			input: "a<!-- hidden -->b",
+			want:  "ab",
+		},
+		{
+			name:           "violations are reported once",
+			input:          "<i>a</i><i>b</i>",
+			want:           "ab",
+			wantViolations: []Violation{{Tag: "i"}},
+		},
+	}
+
+	p := newTestPolicy(ModeStrip)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, violations := p.Clean(tt.input)
+			if got != tt.want {
+				t.Errorf("Clean() = %q, want %q", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(violations, tt.wantViolations) {
+				t.Errorf("Clean() violations = %v, want %v", violations, tt.wantViolations)
+			}
+		})
+	}
+}
+
+func TestPolicySanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "strip removes disallowed markup", mode: ModeStrip, input: "<i>hi</i>", want: "hi"},
+		{name: "reject refuses disallowed markup", mode: ModeReject, input: "<i>hi</i>", wantErr: true},
+		{name: "reject accepts allowed markup", mode: ModeReject, input: "<p>hi</p>", want: "<p>hi</p>"},
+		{name: "nothing left after stripping", mode: ModeStrip, input: "<script>alert(1)</script>", wantErr: true},
+		{name: "blank input is kept", mode: ModeStrip, input: "  ", want: "  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestPolicy(tt.mode).Sanitize("body", tt.input)
+			if tt.wantErr {
+				var errValidation *constant.ErrValidation
+				if !errors.As(err, &errValidation) {
+					t.Fatalf("Sanitize() error = %v, want *constant.ErrValidation", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Sanitize() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Sanitize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package storage
+
+import "go.opentelemetry.io/otel"
+
+var tracer = otel.Tracer("github.com/arfan21/project-sprint-social-media-api/pkg/storage")
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores files on the local filesystem, the files are served by the api under LocalURLPrefix
+type Local struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocal(dir, baseURL string) (*Local, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("storage: failed to create local storage dir: %w", err)
+	}
+
+	return &Local{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// Dir returns the root directory of the stored files
+func (l *Local) Dir() string {
+	return l.dir
+}
+
+func (l *Local) Upload(ctx context.Context, folder string, fileHeader *multipart.FileHeader) (key string, err error) {
+	_, parentSpan := tracer.Start(ctx, "pkg.storage.Local.Upload")
+	defer func() {
+		if err != nil {
+			parentSpan.RecordError(err)
+		}
+		parentSpan.End()
+	}()
+
+	key, err = newObjectKey(folder, fileHeader.Filename)
+	if err != nil {
+		return "", err
+	}
+
+	dst := filepath.Join(l.dir, filepath.FromSlash(key))
+	err = os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+
+	defer src.Close()
+
+	file, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(file, src)
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (l *Local) GetURL(key string) string {
+	return l.baseURL + LocalURLPrefix + "/" + key
+}
+
+func (l *Local) KeyFromURL(url string) (key string, ok bool) {
+	return keyFromURL(l.GetURL(""), url)
+}
+
+func (l *Local) Delete(ctx context.Context, key string) (err error) {
+	_, parentSpan := tracer.Start(ctx, "pkg.storage.Local.Delete")
+	defer func() {
+		if err != nil {
+			parentSpan.RecordError(err)
+		}
+		parentSpan.End()
+	}()
+
+	path := filepath.Join(l.dir, filepath.FromSlash(key))
+	if rel, errRel := filepath.Rel(l.dir, path); errRel != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("storage: invalid key %s", key)
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
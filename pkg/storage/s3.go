@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/s3"
+)
+
+// S3 stores files in an aws s3 or s3 compatible bucket
+type S3 struct {
+	client *s3.S3
+	bucket string
+}
+
+func NewS3(opt ...s3.OptFunc) (*S3, error) {
+	client, err := s3.New(opt...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: failed to create s3 client: %w", err)
+	}
+
+	return &S3{
+		client: client,
+		bucket: config.Get().S3.Bucket,
+	}, nil
+}
+
+func (s *S3) Upload(ctx context.Context, folder string, fileHeader *multipart.FileHeader) (key string, err error) {
+	key, err = newObjectKey(folder, fileHeader.Filename)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.client.Upload(ctx, s.bucket, key, fileHeader)
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (s *S3) GetURL(key string) string {
+	return s.client.GetURL(s.bucket, key)
+}
+
+func (s *S3) KeyFromURL(url string) (key string, ok bool) {
+	return keyFromURL(s.GetURL(""), url)
+}
+
+func (s *S3) Delete(ctx context.Context, key string) (err error) {
+	return s.client.Delete(ctx, s.bucket, key)
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/arfan21/project-sprint-social-media-api/config"
+	"github.com/arfan21/project-sprint-social-media-api/pkg/s3"
+	"github.com/jaevor/go-nanoid"
+)
+
+const (
+	DriverS3           = "s3"
+	DriverS3Compatible = "s3compatible"
+	DriverLocal        = "local"
+
+	// LocalURLPrefix is the path where the api serves the files stored by the local driver
+	LocalURLPrefix = "/files"
+)
+
+type Storage interface {
+	Upload(ctx context.Context, folder string, fileHeader *multipart.FileHeader) (key string, err error)
+	GetURL(key string) string
+	// KeyFromURL is the reverse of GetURL, ok is false when the url does not point to a file of the storage
+	KeyFromURL(url string) (key string, ok bool)
+	Delete(ctx context.Context, key string) (err error)
+}
+
+// New creates the storage driver selected by STORAGE_DRIVER
+func New() (Storage, error) {
+	cfg := config.Get()
+
+	switch cfg.Storage.Driver {
+	case DriverS3, "":
+		return NewS3()
+	case DriverS3Compatible:
+		if cfg.S3.EndPoint == "" {
+			return nil, fmt.Errorf("storage: S3_BASE_URL is required for driver %s", DriverS3Compatible)
+		}
+
+		return NewS3(s3.WithEndpoint(cfg.S3.EndPoint))
+	case DriverLocal:
+		return NewLocal(cfg.Storage.LocalDir, cfg.Storage.LocalBaseURL)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %s", cfg.Storage.Driver)
+	}
+}
+
+// keyFromURL strips the url prefix of an empty key, the remaining key must not be empty or escape the storage
+func keyFromURL(prefix, url string) (key string, ok bool) {
+	key, ok = strings.CutPrefix(url, prefix)
+	if !ok || key == "" || path.Clean("/"+key) != "/"+key {
+		return "", false
+	}
+
+	return key, true
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// newObjectKey builds a unique key for the uploaded file while keeping the original filename readable
+func newObjectKey(folder, filename string) (string, error) {
+	randId, err := nanoid.Standard(15)
+	if err != nil {
+		return "", err
+	}
+
+	filename = unsafeFilenameChars.ReplaceAllString(path.Base(filename), "_")
+	key := path.Join(folder, randId()+"_"+filename)
+	if config.Get().Service.Name != "" {
+		key = path.Join(config.Get().Service.Name, key)
+	}
+
+	return key, nil
+}
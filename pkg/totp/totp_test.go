@@ -0,0 +1,136 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the sha1 seed "12345678901234567890" of the RFC 6238 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatalf("Code() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Code() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code() error = nil, want an error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", secret: rfcSecret, code: codeAt(current), wantStep: current, wantOK: true},
+		{name: "previous step", secret: rfcSecret, code: codeAt(current - 1), wantStep: current - 1, wantOK: true},
+		{name: "next step", secret: rfcSecret, code: codeAt(current + 1), wantStep: current + 1, wantOK: true},
+		{name: "lower case secret", secret: strings.ToLower(rfcSecret), code: codeAt(current), wantStep: current, wantOK: true},
+		{name: "outside the skew", secret: rfcSecret, code: codeAt(current - 2)},
+		{name: "wrong code", secret: rfcSecret, code: "000000"},
+		{name: "short code", secret: rfcSecret, code: codeAt(current)[:Digits-1]},
+		{name: "invalid secret", secret: "not base32!", code: codeAt(current)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate() = (%d, %t), want (%d, %t)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("GenerateSecret() = %s is not base32: %v", secret, err)
+	}
+
+	if len(key) != secretSize {
+		t.Errorf("GenerateSecret() decodes to %d bytes, want %d", len(key), secretSize)
+	}
+
+	other, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	if secret == other {
+		t.Error("GenerateSecret() returned the same secret twice")
+	}
+}
+
+func TestURI(t *testing.T) {
+	got, err := url.Parse(URI("Social Media", "john@example.com", rfcSecret))
+	if err != nil {
+		t.Fatalf("URI() is not a valid url: %v", err)
+	}
+
+	if got.Scheme != "otpauth" || got.Host != "totp" {
+		t.Errorf("URI() = %s, want otpauth://totp/...", got)
+	}
+
+	if got.Path != "/Social Media:john@example.com" {
+		t.Errorf("URI() label = %q, want %q", got.Path, "/Social Media:john@example.com")
+	}
+
+	want := map[string]string{
+		"secret":    rfcSecret,
+		"issuer":    "Social Media",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	}
+	for key, value := range want {
+		if v := got.Query().Get(key); v != value {
+			t.Errorf("URI() %s = %q, want %q", key, v, value)
+		}
+	}
+}